@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"router/client"
+	"storage"
+)
+
+// admin runs cmd if it is an administrative command.
+// Returns whether cmd was such a command.
+func admin(cmd string) bool {
+	rc := client.RouterClient{}
+	rtr := storage.ServiceAddr(*addr)
+	node := storage.ServiceAddr(*nd)
+	var err error
+	switch cmd {
+	case status:
+		err = printStatus(rtr)
+	case config:
+		err = printConfig(rtr)
+	case pending:
+		err = printPending(rtr)
+	case activate, readonly, drain, maintenance:
+		requireNode()
+		err = rc.SetNodeState(rtr, node, nodeStates[cmd])
+	case move:
+		requireNode()
+		if *na == "" {
+			fmt.Fprintln(os.Stderr, "-a cannot be empty")
+			os.Exit(2)
+		}
+		err = rc.SetNodeAddr(rtr, node, storage.ServiceAddr(*na))
+	case approve:
+		requireNode()
+		err = rc.ApproveNode(rtr, node, *rebalance)
+	case reject:
+		requireNode()
+		err = rc.RejectNode(rtr, node)
+	default:
+		return false
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running %s: %v\n", cmd, err)
+		os.Exit(1)
+	}
+	return true
+}
+
+func requireNode() {
+	if *nd == "" {
+		fmt.Fprintln(os.Stderr, "-n cannot be empty")
+		os.Exit(2)
+	}
+}
+
+// printStatus prints states of all nodes known to the router at addr.
+func printStatus(addr storage.ServiceAddr) error {
+	infos, err := client.RouterClient{}.ClusterStatus(addr)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tSTATE\tSTATUS\tHEARTBEAT\tPHI\tRECORDS\tBYTES\tGET/S\tPUT/S\tDEL/S\tP50\tP99\tUPTIME\tVERSION")
+	for _, info := range infos {
+		st := info.Stats
+		fmt.Fprintf(w, "%s\t%s\t%s\t%v\t%.2f\t%d\t%d\t%.1f\t%.1f\t%.1f\t%v\t%v\t%v\t%s\n",
+			info.Node, info.State, info.Status, info.HeartbeatAge.Round(time.Millisecond), info.Phi,
+			st.Records, st.Bytes, st.GetRate, st.PutRate, st.DelRate,
+			st.LatencyP50, st.LatencyP99, st.Uptime.Round(time.Second), st.Version)
+	}
+	return w.Flush()
+}
+
+// printConfig prints the effective configuration of a router, a node
+// or a frontend at addr.
+func printConfig(addr storage.ServiceAddr) error {
+	cfg, err := client.RouterClient{}.Config(addr)
+	if grpcstatus.Code(err) == codes.Unimplemented {
+		// not a router
+		cfg, err = storage.StorageClient{}.Config(addr)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(cfg)
+	return err
+}
+
+// printPending prints nodes waiting for approval on the router at addr.
+func printPending(addr storage.ServiceAddr) error {
+	nodes, err := client.RouterClient{}.ListPending(addr)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tID\tAGE\tHEARTBEAT")
+	for _, p := range nodes {
+		fmt.Fprintf(w, "%s\t%s\t%v\t%v\n", p.Node, p.ID, p.Age.Round(time.Second), p.HeartbeatAge.Round(time.Millisecond))
+	}
+	return w.Flush()
+}
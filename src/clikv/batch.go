@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"storage"
+)
+
+// batch runs cmd if it is a batch command. Keys and values are read
+// from the file given by -f, "-" is the standard input.
+// Returns whether cmd was such a command.
+func batch(cmd string) bool {
+	switch cmd {
+	case mget, mput, mdel:
+	default:
+		return false
+	}
+	if *file == "" {
+		fmt.Fprintln(os.Stderr, "-f cannot be empty")
+		os.Exit(2)
+	}
+	in := os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening %q: %v\n", *file, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+	}
+	records, err := readRecords(in, cmd == mput)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %q: %v\n", *file, err)
+		os.Exit(1)
+	}
+	keys := make([]storage.RecordID, 0, len(records))
+	for _, r := range records {
+		keys = append(keys, r.Key)
+	}
+
+	client := storage.StorageClient{}
+	node := storage.ServiceAddr(*addr)
+	var results []storage.Result
+	switch cmd {
+	case mget:
+		results = client.MultiGet(context.Background(), node, keys)
+	case mput:
+		results = client.MultiPut(context.Background(), node, records)
+	case mdel:
+		results = client.MultiDel(context.Background(), node, keys)
+	}
+
+	failed := 0
+	for _, res := range results {
+		switch {
+		case res.Err != nil:
+			failed++
+			fmt.Printf("%v\terror: %v\n", res.Key, res.Err)
+		case cmd == mget:
+			fmt.Printf("%v\t%q\n", res.Key, res.Data)
+		default:
+			fmt.Printf("%v\tok\n", res.Key)
+		}
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "%v of %v keys failed\n", failed, len(results))
+		os.Exit(1)
+	}
+	return true
+}
+
+// readRecords reads a key per line, followed by a value after a space
+// if withData is set. Empty lines and lines starting with # are skipped.
+func readRecords(r io.Reader, withData bool) ([]storage.Record, error) {
+	var records []storage.Record
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, storage.MaxBatchBytes)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value := text, ""
+		if withData {
+			fields := strings.SplitN(text, " ", 2)
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %v: a key and a value should be separated by a space", line)
+			}
+			key, value = fields[0], fields[1]
+		}
+		k, err := strconv.ParseUint(key, 10, 64)
+		if err != nil || k > math.MaxUint32 {
+			return nil, fmt.Errorf("line %v: %q is not a uint32 key", line, key)
+		}
+		records = append(records, storage.Record{Key: storage.RecordID(k), Data: []byte(value)})
+	}
+	return records, sc.Err()
+}
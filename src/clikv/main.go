@@ -6,25 +6,71 @@ import (
 	"math"
 	"os"
 
+	"router/router"
 	"storage"
 )
 
 const (
-	get = "get"
-	put = "put"
-	del = "del"
+	get    = "get"
+	put    = "put"
+	del    = "del"
+	mget   = "mget"
+	mput   = "mput"
+	mdel   = "mdel"
+	status = "status"
+	config = "config"
+
+	activate    = "activate"
+	readonly    = "readonly"
+	drain       = "drain"
+	maintenance = "maintenance"
+	move        = "move"
+	pending     = "pending"
+	approve     = "approve"
+	reject      = "reject"
 )
 
+// nodeStates maps admin commands to the node states they set.
+var nodeStates = map[string]router.NodeState{
+	activate:    router.NodeActive,
+	readonly:    router.NodeReadOnly,
+	drain:       router.NodeDraining,
+	maintenance: router.NodeMaintenance,
+}
+
 func usage() {
 	fmt.Println("Usage:")
 	fmt.Println("  clikv [-h]")
 	fmt.Println("  clikv <command> -s=<addr> -k=<key> [-v=<val>]")
+	fmt.Printf("  clikv %s -s=<addr> -k=<key> -f=<file>\n", put)
+	fmt.Printf("  clikv %s -s=<addr> -k=<key> -o=<file>\n", get)
+	fmt.Printf("  clikv %s -s=<router addr>\n", status)
+	fmt.Printf("  clikv {%s|%s|%s} -s=<addr> -f=<file>\n", mget, mput, mdel)
+	fmt.Printf("  clikv %s -s=<addr>\n", config)
+	fmt.Printf("  clikv {%s|%s|%s|%s} -s=<router addr> -n=<node addr>\n", activate, readonly, drain, maintenance)
+	fmt.Printf("  clikv %s -s=<router addr> -n=<node addr> -a=<new node addr>\n", move)
+	fmt.Printf("  clikv %s -s=<router addr>\n", pending)
+	fmt.Printf("  clikv %s -s=<router addr> -n=<node addr> [-rebalance]\n", approve)
+	fmt.Printf("  clikv %s -s=<router addr> -n=<node addr>\n", reject)
 
 	fmt.Println()
 	fmt.Println("List of available commands:")
 	fmt.Printf("  %s\n", get)
 	fmt.Printf("  %s\n", put)
 	fmt.Printf("  %s\n", del)
+	fmt.Printf("  %s\n", mget)
+	fmt.Printf("  %s\n", mput)
+	fmt.Printf("  %s\n", mdel)
+	fmt.Printf("  %s\n", status)
+	fmt.Printf("  %s\n", config)
+	fmt.Printf("  %s\n", activate)
+	fmt.Printf("  %s\n", readonly)
+	fmt.Printf("  %s\n", drain)
+	fmt.Printf("  %s\n", maintenance)
+	fmt.Printf("  %s\n", move)
+	fmt.Printf("  %s\n", pending)
+	fmt.Printf("  %s\n", approve)
+	fmt.Printf("  %s\n", reject)
 
 	fmt.Println()
 	fmt.Println("List of available options:")
@@ -35,7 +81,13 @@ var (
 	addr = flag.String("s", "", "address to send request to (e.g. localhost:7319) (REQUIRED)")
 	key  = flag.Int64("k", -1, "key (REQUIRED)")
 	val  = flag.String("v", "", "value")
+	nd   = flag.String("n", "", "node to change the state or the address of, approve or reject")
+	na   = flag.String("a", "", "new address of the node")
+	file = flag.String("f", "", "file with a value to stream for put, or with a key per line, followed by a space and a value for mput (- for stdin)")
+	out  = flag.String("o", "", "file to stream a value got to (- for stdout)")
 	help = flag.Bool("h", false, "show this help message")
+
+	rebalance = flag.Bool("rebalance", false, "make an approved node take its share of keys right away")
 )
 
 func main() {
@@ -48,15 +100,18 @@ func main() {
 		fmt.Fprintln(os.Stderr, "-s cannot be empty")
 		os.Exit(2)
 	}
-	if *key < 0 || *key > math.MaxUint32 {
-		fmt.Fprintln(os.Stderr, "-k should be set to a uint32 value")
-		os.Exit(2)
-	}
 	if flag.NArg() != 1 {
 		fmt.Fprintln(os.Stderr, "exactly one command should be provided")
 		os.Exit(2)
 
 	}
+	if admin(flag.Arg(0)) || batch(flag.Arg(0)) {
+		return
+	}
+	if *key < 0 || *key > math.MaxUint32 {
+		fmt.Fprintln(os.Stderr, "-k should be set to a uint32 value")
+		os.Exit(2)
+	}
 
 	client := storage.NewClient()
 	node := storage.ServiceAddr(*addr)
@@ -64,6 +119,10 @@ func main() {
 	k := storage.RecordID(*key)
 	data := []byte(*val)
 
+	if stream(flag.Arg(0), node, k) {
+		return
+	}
+
 	switch flag.Arg(0) {
 	case put:
 		if err := client.Put(node, k, data); err != nil {
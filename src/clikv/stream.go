@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"storage"
+)
+
+// stream runs put with -f or get with -o, streaming the value of k
+// from or to a file. Returns whether cmd was such a command.
+func stream(cmd string, node storage.ServiceAddr, k storage.RecordID) bool {
+	client := storage.StorageClient{}
+	switch {
+	case cmd == put && *file != "":
+		in := os.Stdin
+		if *file != "-" {
+			f, err := os.Open(*file)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error opening %q: %v\n", *file, err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			in = f
+		}
+		if err := client.PutStream(context.Background(), node, k, in); err != nil {
+			fmt.Fprintf(os.Stderr, "Error putting record: %v\n", err)
+			os.Exit(1)
+		}
+	case cmd == get && *out != "":
+		w := os.Stdout
+		if *out != "-" {
+			f, err := os.Create(*out)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error creating %q: %v\n", *out, err)
+				os.Exit(1)
+			}
+			w = f
+		}
+		err := client.GetStream(context.Background(), node, k, w)
+		if w != os.Stdout {
+			if cerr := w.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting record: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		return false
+	}
+	return true
+}
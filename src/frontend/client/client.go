@@ -0,0 +1,151 @@
+// Package client is a client of the storage which sends requests straight
+// to nodes. It runs the quorum logic of frontend.Frontend in-process, so
+// requests take one network hop less than via a frontend service.
+//
+// Package client -- клиент хранилища, посылающий запросы напрямую в node.
+// Он выполняет логику кворума frontend.Frontend в своем процессе, поэтому
+// запросы проходят на один сетевой переход меньше, чем через frontend.
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"frontend/frontend"
+	rclient "router/client"
+	"router/router"
+	"storage"
+)
+
+// Client is a storage client with the semantics and errors of
+// frontend.Frontend.
+//
+// Client -- клиент хранилища с семантикой и ошибками frontend.Frontend.
+type Client struct {
+	fe *frontend.Frontend
+}
+
+// ErrNoRouter is returned by New if neither cfg.Router nor cfg.Routers is set.
+//
+// ErrNoRouter возвращается New, если не задан ни cfg.Router, ни cfg.Routers.
+var ErrNoRouter = errors.New("Router or Routers should be set")
+
+// New creates a Client with a given cfg. The NodesFinder is created from
+// cfg.Placement and cfg.Hasher, missing clients are created for
+// cfg.Routers. cfg.Addr is not used.
+//
+// New создает новый Client с данным cfg. NodesFinder создается
+// по cfg.Placement и cfg.Hasher, недостающие клиенты создаются
+// для cfg.Routers. cfg.Addr не используется.
+func New(cfg frontend.Config) (*Client, error) {
+	if cfg.Router == "" && len(cfg.Routers) > 0 {
+		cfg.Router = cfg.Routers[0]
+	}
+	if cfg.Router == "" {
+		return nil, ErrNoRouter
+	}
+	nf, err := router.NewNodesFinderByName(cfg.Placement, cfg.Hasher)
+	if err != nil {
+		return nil, err
+	}
+	cfg.NF = nf
+	if cfg.NC == nil {
+		cfg.NC = storage.NewClient()
+	}
+	if cfg.RC == nil {
+		cfg.RC = rclient.New(cfg.Routers...)
+	}
+	return &Client{fe: frontend.New(cfg)}, nil
+}
+
+// Put an item to the storage, see frontend.Frontend.Put.
+//
+// Put -- добавить запись в хранилище, см. frontend.Frontend.Put.
+func (c *Client) Put(k storage.RecordID, d []byte) error {
+	return c.fe.Put(k, d)
+}
+
+// PutContext is like Put but requests are bound to ctx.
+//
+// PutContext -- то же, что Put, но запросы привязаны к ctx.
+func (c *Client) PutContext(ctx context.Context, k storage.RecordID, d []byte) error {
+	return c.fe.PutContext(ctx, k, d)
+}
+
+// Get an item from the storage, see frontend.Frontend.Get.
+//
+// Get -- получить запись из хранилища, см. frontend.Frontend.Get.
+func (c *Client) Get(k storage.RecordID) ([]byte, error) {
+	return c.fe.Get(k)
+}
+
+// GetContext is like Get but requests are bound to ctx.
+//
+// GetContext -- то же, что Get, но запросы привязаны к ctx.
+func (c *Client) GetContext(ctx context.Context, k storage.RecordID) ([]byte, error) {
+	return c.fe.GetContext(ctx, k)
+}
+
+// Del an item from the storage, see frontend.Frontend.Del.
+//
+// Del -- удалить запись из хранилища, см. frontend.Frontend.Del.
+func (c *Client) Del(k storage.RecordID) error {
+	return c.fe.Del(k)
+}
+
+// DelContext is like Del but requests are bound to ctx.
+//
+// DelContext -- то же, что Del, но запросы привязаны к ctx.
+func (c *Client) DelContext(ctx context.Context, k storage.RecordID) error {
+	return c.fe.DelContext(ctx, k)
+}
+
+// HedgeStats returns counters of hedged reads, see frontend.Config.ReadMode.
+//
+// HedgeStats возвращает счетчики чтений в режиме frontend.ReadHedged.
+func (c *Client) HedgeStats() frontend.HedgeStats {
+	return c.fe.HedgeStats()
+}
+
+// CacheStats returns counters of the read cache, see frontend.Config.CacheSize.
+//
+// CacheStats возвращает счетчики кеша чтений, см. frontend.Config.CacheSize.
+func (c *Client) CacheStats() frontend.CacheStats {
+	return c.fe.CacheStats()
+}
+
+// MultiGet gets records for keys, see frontend.Frontend.MultiGet.
+//
+// MultiGet получает записи для keys, см. frontend.Frontend.MultiGet.
+func (c *Client) MultiGet(ctx context.Context, keys []storage.RecordID) []storage.Result {
+	return c.fe.MultiGet(ctx, keys)
+}
+
+// MultiPut puts records, see frontend.Frontend.MultiPut.
+//
+// MultiPut добавляет записи, см. frontend.Frontend.MultiPut.
+func (c *Client) MultiPut(ctx context.Context, records []storage.Record) []storage.Result {
+	return c.fe.MultiPut(ctx, records)
+}
+
+// MultiDel deletes records for keys, see frontend.Frontend.MultiDel.
+//
+// MultiDel удаляет записи для keys, см. frontend.Frontend.MultiDel.
+func (c *Client) MultiDel(ctx context.Context, keys []storage.RecordID) []storage.Result {
+	return c.fe.MultiDel(ctx, keys)
+}
+
+// PutStream puts the value read from r, see frontend.Frontend.PutStream.
+//
+// PutStream добавляет значение, прочитанное из r, см. frontend.Frontend.PutStream.
+func (c *Client) PutStream(ctx context.Context, k storage.RecordID, r io.Reader) error {
+	return c.fe.PutStream(ctx, k, r)
+}
+
+// GetStream writes the value for k to w, see frontend.Frontend.GetStream.
+//
+// GetStream записывает значение для k в w, см. frontend.Frontend.GetStream.
+func (c *Client) GetStream(ctx context.Context, k storage.RecordID, w io.Writer) error {
+	return c.fe.GetStream(ctx, k, w)
+}
@@ -0,0 +1,209 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"frontend/frontend"
+	"router/router"
+	"storage"
+)
+
+type FakeRouter struct {
+	nodes []storage.ServiceAddr
+	nf    router.NodesFinder
+}
+
+func (r *FakeRouter) Heartbeat(router, node storage.ServiceAddr) error {
+	return nil
+}
+
+func (r *FakeRouter) NodesFind(router storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, error) {
+	return r.nf.NodesFind(k, r.nodes), nil
+}
+
+func (r *FakeRouter) List(router storage.ServiceAddr) ([]storage.ServiceAddr, error) {
+	return r.nodes, nil
+}
+
+// FakeNode keeps records of nodes in memory. Requests to nodes
+// in down fail with storage.ErrUnknownDaemon.
+type FakeNode struct {
+	lock    sync.Mutex
+	records map[storage.ServiceAddr]map[storage.RecordID][]byte
+	down    map[storage.ServiceAddr]bool
+}
+
+func NewFakeNode(down ...storage.ServiceAddr) *FakeNode {
+	n := &FakeNode{
+		records: make(map[storage.ServiceAddr]map[storage.RecordID][]byte),
+		down:    make(map[storage.ServiceAddr]bool),
+	}
+	for _, node := range down {
+		n.down[node] = true
+	}
+	return n
+}
+
+func (n *FakeNode) Put(node storage.ServiceAddr, k storage.RecordID, d []byte) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.down[node] {
+		return storage.ErrUnknownDaemon
+	}
+	if n.records[node] == nil {
+		n.records[node] = make(map[storage.RecordID][]byte)
+	}
+	if _, ok := n.records[node][k]; ok {
+		return storage.ErrRecordExists
+	}
+	n.records[node][k] = append([]byte(nil), d...)
+	return nil
+}
+
+func (n *FakeNode) Get(node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.down[node] {
+		return nil, storage.ErrUnknownDaemon
+	}
+	d, ok := n.records[node][k]
+	if !ok {
+		return nil, storage.ErrRecordNotFound
+	}
+	return d, nil
+}
+
+func (n *FakeNode) Del(node storage.ServiceAddr, k storage.RecordID) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.down[node] {
+		return storage.ErrUnknownDaemon
+	}
+	if _, ok := n.records[node][k]; !ok {
+		return storage.ErrRecordNotFound
+	}
+	delete(n.records[node], k)
+	return nil
+}
+
+func TestNew_NoRouter(t *testing.T) {
+	if _, err := New(frontend.Config{}); err != ErrNoRouter {
+		t.Errorf("New() error: got %v, want %v", err, ErrNoRouter)
+	}
+	c, err := New(frontend.Config{Routers: []storage.ServiceAddr{"router1", "router2"}})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if c == nil {
+		t.Errorf("New() returned nil Client")
+	}
+}
+
+// TestClient_Frontend runs the same requests with a Client and
+// a frontend.Frontend and checks that their results are the same.
+func TestClient_Frontend(t *testing.T) {
+	for _, down := range [][]storage.ServiceAddr{nil, {"node3"}, {"node2", "node3"}} {
+		nodes := []storage.ServiceAddr{"node1", "node2", "node3", "node4"}
+		newConfig := func() frontend.Config {
+			nf, err := router.NewNodesFinderByName("", "")
+			if err != nil {
+				t.Fatalf("NewNodesFinderByName() error: %v", err)
+			}
+			return frontend.Config{
+				Router: "router",
+				RC:     &FakeRouter{nodes: nodes, nf: nf},
+				NC:     NewFakeNode(down...),
+			}
+		}
+		c, err := New(newConfig())
+		if err != nil {
+			t.Fatalf("New() error: %v", err)
+		}
+		cfg := newConfig()
+		nf, _ := router.NewNodesFinderByName("", "")
+		cfg.NF = nf
+		fe := frontend.New(cfg)
+
+		ctx := context.Background()
+		type result struct {
+			d   []byte
+			err error
+		}
+		// want is the result of a step when all nodes are up.
+		steps := []struct {
+			name string
+			want result
+			c    func() result
+			fe   func() result
+		}{
+			{"Get missing", result{nil, storage.ErrRecordNotFound},
+				func() result { d, err := c.Get(1); return result{d, err} },
+				func() result { d, err := fe.Get(1); return result{d, err} }},
+			{"Put", result{},
+				func() result { return result{nil, c.Put(1, []byte("one"))} },
+				func() result { return result{nil, fe.Put(1, []byte("one"))} }},
+			{"Put existing", result{nil, storage.ErrRecordExists},
+				func() result { return result{nil, c.PutContext(ctx, 1, []byte("two"))} },
+				func() result { return result{nil, fe.PutContext(ctx, 1, []byte("two"))} }},
+			{"Get", result{[]byte("one"), nil},
+				func() result { d, err := c.GetContext(ctx, 1); return result{d, err} },
+				func() result { d, err := fe.GetContext(ctx, 1); return result{d, err} }},
+			{"Del", result{},
+				func() result { return result{nil, c.Del(1)} },
+				func() result { return result{nil, fe.Del(1)} }},
+			{"Del missing", result{nil, storage.ErrRecordNotFound},
+				func() result { return result{nil, c.DelContext(ctx, 1)} },
+				func() result { return result{nil, fe.DelContext(ctx, 1)} }},
+			{"PutStream", result{},
+				func() result { return result{nil, c.PutStream(ctx, 2, bytes.NewBufferString("stream"))} },
+				func() result { return result{nil, fe.PutStream(ctx, 2, bytes.NewBufferString("stream"))} }},
+			{"GetStream", result{[]byte("stream"), nil},
+				func() result {
+					var b bytes.Buffer
+					err := c.GetStream(ctx, 2, &b)
+					return result{b.Bytes(), err}
+				},
+				func() result {
+					var b bytes.Buffer
+					err := fe.GetStream(ctx, 2, &b)
+					return result{b.Bytes(), err}
+				}},
+		}
+		for _, s := range steps {
+			got, want := s.c(), s.fe()
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s with %v down: got %q, %v, want %q, %v", s.name, down, got.d, got.err, want.d, want.err)
+			}
+			if down == nil && (!bytes.Equal(got.d, s.want.d) || got.err != s.want.err) {
+				t.Errorf("%s: got %q, %v, want %q, %v", s.name, got.d, got.err, s.want.d, s.want.err)
+			}
+		}
+
+		records := []storage.Record{{Key: 3, Data: []byte("three")}, {Key: 2, Data: []byte("two")}}
+		keys := []storage.RecordID{3, 2, 4}
+		multi := []struct {
+			name string
+			c    func() []storage.Result
+			fe   func() []storage.Result
+		}{
+			{"MultiPut",
+				func() []storage.Result { return c.MultiPut(ctx, records) },
+				func() []storage.Result { return fe.MultiPut(ctx, records) }},
+			{"MultiGet",
+				func() []storage.Result { return c.MultiGet(ctx, keys) },
+				func() []storage.Result { return fe.MultiGet(ctx, keys) }},
+			{"MultiDel",
+				func() []storage.Result { return c.MultiDel(ctx, keys) },
+				func() []storage.Result { return fe.MultiDel(ctx, keys) }},
+		}
+		for _, m := range multi {
+			if got, want := m.c(), m.fe(); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s with %v down: got %v, want %v", m.name, down, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package frontend
+
+import (
+	"context"
+	"sync"
+
+	"storage"
+)
+
+// multiClient returns nc as a storage.MultiClient. If nc cannot send
+// batches, requests for keys of a batch are sent one by one.
+func multiClient(nc storage.Client) storage.MultiClient {
+	if c, ok := nc.(storage.MultiClient); ok {
+		return c
+	}
+	return singleClient{contextClient(nc)}
+}
+
+type singleClient struct {
+	storage.ContextClient
+}
+
+func (c singleClient) MultiGet(ctx context.Context, node storage.ServiceAddr, keys []storage.RecordID) []storage.Result {
+	results := make([]storage.Result, 0, len(keys))
+	for _, k := range keys {
+		data, err := c.GetContext(ctx, node, k)
+		results = append(results, storage.Result{Key: k, Data: data, Err: err})
+	}
+	return results
+}
+
+func (c singleClient) MultiPut(ctx context.Context, node storage.ServiceAddr, records []storage.Record) []storage.Result {
+	results := make([]storage.Result, 0, len(records))
+	for _, r := range records {
+		results = append(results, storage.Result{Key: r.Key, Err: c.PutContext(ctx, node, r.Key, r.Data)})
+	}
+	return results
+}
+
+func (c singleClient) MultiDel(ctx context.Context, node storage.ServiceAddr, keys []storage.RecordID) []storage.Result {
+	results := make([]storage.Result, 0, len(keys))
+	for _, k := range keys {
+		results = append(results, storage.Result{Key: k, Err: c.DelContext(ctx, node, k)})
+	}
+	return results
+}
+
+// multiFindLimit is the largest number of keys whose nodes are looked
+// up at once by a batched request.
+const multiFindLimit = 64
+
+// findAll finds nodes for each of keys with find, looking up to
+// multiFindLimit keys at once. Returns the nodes and the error for
+// each key index.
+func findAll(ctx context.Context, keys []storage.RecordID, find func(ctx context.Context, k storage.RecordID) ([]storage.ServiceAddr, error)) ([][]storage.ServiceAddr, []error) {
+	nodes := make([][]storage.ServiceAddr, len(keys))
+	errs := make([]error, len(keys))
+	sem := make(chan struct{}, multiFindLimit)
+	var wg sync.WaitGroup
+	for i, k := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, k storage.RecordID) {
+			defer wg.Done()
+			nodes[i], errs[i] = find(ctx, k)
+			<-sem
+		}(i, k)
+	}
+	wg.Wait()
+	return nodes, errs
+}
+
+// fanOut calls send for each node with indices of keys of its batch
+// in parallel. Returns results of all nodes for each key index.
+func fanOut(n int, batches map[storage.ServiceAddr][]int, send func(node storage.ServiceAddr, idx []int) []storage.Result) [][]storage.Result {
+	answers := make([][]storage.Result, n)
+	var (
+		lock sync.Mutex
+		wg   sync.WaitGroup
+	)
+	for node, idx := range batches {
+		wg.Add(1)
+		go func(node storage.ServiceAddr, idx []int) {
+			defer wg.Done()
+			results := send(node, idx)
+			lock.Lock()
+			defer lock.Unlock()
+			for j, i := range idx {
+				answers[i] = append(answers[i], results[j])
+			}
+		}(node, idx)
+	}
+	wg.Wait()
+	return answers
+}
+
+// readQuorum returns the outcome of a read given results of all nodes.
+func readQuorum(results []storage.Result) ([]byte, error) {
+	dataMap := make(map[string]int)
+	errorMap := make(map[error]int)
+	for _, res := range results {
+		if res.Err == nil {
+			dataMap[string(res.Data)]++
+			if dataMap[string(res.Data)] >= storage.MinRedundancy {
+				return res.Data, nil
+			}
+			continue
+		}
+		errorMap[res.Err]++
+		if errorMap[res.Err] >= storage.MinRedundancy {
+			return nil, res.Err
+		}
+	}
+	return nil, storage.ErrQuorumNotReached
+}
+
+// MultiGet gets records for keys like Get but sends one batched request
+// to each node. Results are in the order of keys. Batched reads are
+// not hedged.
+//
+// MultiGet получает записи для keys так же, как Get, но посылает
+// в каждую node один пакетный запрос. Результаты идут в порядке keys.
+// Пакетные чтения не используют дополнительных запросов.
+func (fe *Frontend) MultiGet(ctx context.Context, keys []storage.RecordID) []storage.Result {
+	cfg := fe.config()
+	results := make([]storage.Result, len(keys))
+	gens := make([]uint64, len(keys))
+	missed := make([]int, 0, len(keys))
+	for i, k := range keys {
+		results[i].Key = k
+		if cfg.CacheSize > 0 {
+			data, gen, ok := fe.cache.get(k)
+			if ok {
+				results[i].Data = data
+				continue
+			}
+			gens[i] = gen
+		}
+		missed = append(missed, i)
+	}
+	if len(missed) == 0 {
+		return results
+	}
+
+	t, err := fe.currentTopology(ctx)
+	if err != nil {
+		for _, i := range missed {
+			results[i].Err = err
+		}
+		return results
+	}
+	batches := make(map[storage.ServiceAddr][]int)
+	for _, i := range missed {
+		for _, node := range cfg.NF.NodesFindTopology(keys[i], t) {
+			if t.States[node].Readable() {
+				batches[node] = append(batches[node], i)
+			}
+		}
+	}
+	nc := multiClient(cfg.NC)
+	answers := fanOut(len(keys), batches, func(node storage.ServiceAddr, idx []int) []storage.Result {
+		batch := make([]storage.RecordID, 0, len(idx))
+		for _, i := range idx {
+			batch = append(batch, keys[i])
+		}
+		return nc.MultiGet(ctx, node, batch)
+	})
+	for _, i := range missed {
+		results[i].Data, results[i].Err = readQuorum(answers[i])
+		if cfg.CacheSize > 0 && results[i].Err == nil {
+			fe.cache.put(keys[i], results[i].Data, gens[i], cacheTTL(cfg), cfg.CacheSize)
+		}
+	}
+	return results
+}
+
+// multiWrite finds nodes for each of keys with find and calls send for
+// each node with indices of keys of its batch. Nodes of keys are looked
+// up concurrently. Quorum is applied per key.
+func (fe *Frontend) multiWrite(ctx context.Context, keys []storage.RecordID, find func(ctx context.Context, k storage.RecordID) ([]storage.ServiceAddr, error), send func(nc storage.MultiClient, node storage.ServiceAddr, idx []int) []storage.Result) []storage.Result {
+	cfg := fe.config()
+	results := make([]storage.Result, len(keys))
+	batches := make(map[storage.ServiceAddr][]int)
+	found, errs := findAll(ctx, keys, find)
+	for i, k := range keys {
+		results[i].Key = k
+		nodes, err := found[i], errs[i]
+		if err == nil && len(nodes) < storage.MinRedundancy {
+			err = storage.ErrNotEnoughDaemons
+		}
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		for _, node := range nodes {
+			batches[node] = append(batches[node], i)
+		}
+	}
+	nc := multiClient(cfg.NC)
+	answers := fanOut(len(keys), batches, func(node storage.ServiceAddr, idx []int) []storage.Result {
+		return send(nc, node, idx)
+	})
+	for i, k := range keys {
+		if results[i].Err != nil {
+			continue
+		}
+		errs := make([]error, 0, len(answers[i]))
+		for _, res := range answers[i] {
+			if res.Err != nil && storage.ErrToStatus(res.Err) == storage.StatusUnknown {
+				// the node may have died since alive nodes were fetched
+				fe.invalidateAlive()
+			}
+			errs = append(errs, res.Err)
+		}
+		results[i].Err = writeQuorum(errs)
+		fe.invalidate(cfg, k)
+	}
+	return results
+}
+
+// MultiPut puts records like Put but sends one batched request to each
+// node. Results are in the order of records.
+//
+// MultiPut добавляет записи так же, как Put, но посылает в каждую node
+// один пакетный запрос. Результаты идут в порядке records.
+func (fe *Frontend) MultiPut(ctx context.Context, records []storage.Record) []storage.Result {
+	keys := make([]storage.RecordID, 0, len(records))
+	for _, r := range records {
+		keys = append(keys, r.Key)
+	}
+	return fe.multiWrite(ctx, keys, fe.nodesFind, func(nc storage.MultiClient, node storage.ServiceAddr, idx []int) []storage.Result {
+		batch := make([]storage.Record, 0, len(idx))
+		for _, i := range idx {
+			batch = append(batch, records[i])
+		}
+		return nc.MultiPut(ctx, node, batch)
+	})
+}
+
+// MultiDel deletes records for keys like Del but sends one batched request
+// to each node. Results are in the order of keys.
+//
+// MultiDel удаляет записи для keys так же, как Del, но посылает в каждую
+// node один пакетный запрос. Результаты идут в порядке keys.
+func (fe *Frontend) MultiDel(ctx context.Context, keys []storage.RecordID) []storage.Result {
+	return fe.multiWrite(ctx, keys, fe.delNodes, func(nc storage.MultiClient, node storage.ServiceAddr, idx []int) []storage.Result {
+		batch := make([]storage.RecordID, 0, len(idx))
+		for _, i := range idx {
+			batch = append(batch, keys[i])
+		}
+		return nc.MultiDel(ctx, node, batch)
+	})
+}
@@ -0,0 +1,178 @@
+package frontend
+
+import (
+	"container/list"
+	"log"
+	"sync"
+	"time"
+
+	"storage"
+)
+
+// DefaultCacheTTL is used if Config.CacheTTL is 0.
+//
+// DefaultCacheTTL используется, если Config.CacheTTL равен 0.
+const DefaultCacheTTL = time.Second
+
+// cacheEntryOverhead is an estimate of memory taken by a cache entry
+// besides its data.
+const cacheEntryOverhead = 64
+
+// CacheStats counts lookups of the read cache.
+//
+// CacheStats -- счетчики обращений к кешу чтений.
+type CacheStats struct {
+	// Hits is a number of records found in the cache.
+	// Hits -- количество записей, найденных в кеше.
+	Hits uint64
+	// Misses is a number of records read from nodes.
+	// Misses -- количество записей, прочитанных из node.
+	Misses uint64
+	// Evictions is a number of records evicted to fit the size limit.
+	// Evictions -- количество записей, вытесненных из-за ограничения размера.
+	Evictions uint64
+	// Bytes is the current size of the cache.
+	// Bytes -- текущий размер кеша.
+	Bytes int64
+}
+
+// readCache is a LRU cache of records bounded by size in bytes.
+type readCache struct {
+	mu    sync.Mutex
+	lru   *list.List
+	items map[storage.RecordID]*list.Element
+	// gen is incremented by each invalidation, records read before
+	// an invalidation are not cached.
+	gen   uint64
+	stats CacheStats
+}
+
+type cacheEntry struct {
+	key     storage.RecordID
+	data    []byte
+	expires time.Time
+}
+
+func newReadCache() *readCache {
+	return &readCache{
+		lru:   list.New(),
+		items: make(map[storage.RecordID]*list.Element),
+	}
+}
+
+// get returns a copy of the record k and the generation to pass to put
+// if the record is not cached.
+func (c *readCache) get(k storage.RecordID) ([]byte, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[k]; ok {
+		entry := e.Value.(*cacheEntry)
+		if time.Now().Before(entry.expires) {
+			c.lru.MoveToFront(e)
+			c.stats.Hits++
+			return append([]byte(nil), entry.data...), c.gen, true
+		}
+		c.remove(e)
+	}
+	c.stats.Misses++
+	return nil, c.gen, false
+}
+
+// put caches a copy of the record k read at the generation gen for ttl
+// evicting least recently used records to fit into size bytes.
+func (c *readCache) put(k storage.RecordID, d []byte, gen uint64, ttl time.Duration, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen || int64(len(d)+cacheEntryOverhead) > size {
+		return
+	}
+	if e, ok := c.items[k]; ok {
+		c.remove(e)
+	}
+	entry := &cacheEntry{key: k, data: append([]byte(nil), d...), expires: time.Now().Add(ttl)}
+	c.items[k] = c.lru.PushFront(entry)
+	c.stats.Bytes += int64(len(d) + cacheEntryOverhead)
+	c.shrink(size)
+}
+
+// shrink evicts least recently used records until the cache fits
+// into size bytes.
+func (c *readCache) shrink(size int64) {
+	for c.stats.Bytes > size && c.lru.Len() > 0 {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *readCache) remove(e *list.Element) {
+	entry := c.lru.Remove(e).(*cacheEntry)
+	delete(c.items, entry.key)
+	c.stats.Bytes -= int64(len(entry.data) + cacheEntryOverhead)
+}
+
+// invalidate drops the record k.
+func (c *readCache) invalidate(k storage.RecordID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	if e, ok := c.items[k]; ok {
+		c.remove(e)
+	}
+}
+
+// resize evicts records to fit into size bytes, all of them if size
+// is not positive.
+func (c *readCache) resize(size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.shrink(size)
+}
+
+// CacheStats returns counters of the read cache, see Config.CacheSize.
+//
+// CacheStats возвращает счетчики кеша чтений, см. Config.CacheSize.
+func (fe *Frontend) CacheStats() CacheStats {
+	fe.cache.mu.Lock()
+	defer fe.cache.mu.Unlock()
+	return fe.cache.stats
+}
+
+// Invalidate drops the record k from the read cache. Frontends call it
+// on their Config.CachePeers after writes.
+//
+// Invalidate удаляет запись k из кеша чтений. Frontend вызывают его
+// у своих Config.CachePeers после записи.
+func (fe *Frontend) Invalidate(k storage.RecordID) {
+	fe.cache.invalidate(k)
+}
+
+// invalidate drops the record k from the read cache after a write
+// and tells the cache peers about it.
+func (fe *Frontend) invalidate(cfg Config, k storage.RecordID) {
+	if cfg.CacheSize <= 0 {
+		return
+	}
+	fe.cache.invalidate(k)
+	if len(cfg.CachePeers) == 0 {
+		return
+	}
+	ic, ok := cfg.NC.(storage.InvalidatorClient)
+	if !ok {
+		return
+	}
+	for _, peer := range cfg.CachePeers {
+		go func(peer storage.ServiceAddr) {
+			if err := ic.Invalidate(peer, k); err != nil {
+				log.Printf("Failed to invalidate key %v at %v: %v", k, peer, err)
+			}
+		}(peer)
+	}
+}
+
+func cacheTTL(cfg Config) time.Duration {
+	if cfg.CacheTTL == 0 {
+		return DefaultCacheTTL
+	}
+	return cfg.CacheTTL
+}
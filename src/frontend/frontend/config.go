@@ -0,0 +1,91 @@
+package frontend
+
+import (
+	"reflect"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"storage"
+)
+
+func (fe *Frontend) config() Config {
+	fe.cfgLock.RLock()
+	defer fe.cfgLock.RUnlock()
+	return fe.cfg
+}
+
+// Config returns the running configuration of the Frontend.
+//
+// Config возвращает текущую конфигурацию Frontend.
+func (fe *Frontend) Config() Config {
+	return fe.config()
+}
+
+// Reload applies cfg to the running Frontend. The listening addresses,
+// the placement and the hasher cannot be changed live, a
+// storage.RestartError is returned if they differ, other fields are
+// applied anyway. Topology is fetched anew if routers change.
+//
+// Reload применяет cfg к работающему Frontend. Слушающие адреса, стратегию
+// размещения и hasher нельзя изменить без перезапуска, если они
+// отличаются, возвращается storage.RestartError, остальные поля
+// применяются. Если router изменились, топология запрашивается заново.
+func (fe *Frontend) Reload(cfg Config) error {
+	fe.cfgLock.Lock()
+	old := fe.cfg
+	var restart []string
+	if cfg.Addr != old.Addr {
+		restart = append(restart, "addr")
+		cfg.Addr = old.Addr
+	}
+	if cfg.HTTPAddr != old.HTTPAddr {
+		restart = append(restart, "http_addr")
+		cfg.HTTPAddr = old.HTTPAddr
+	}
+	if cfg.RESPAddr != old.RESPAddr {
+		restart = append(restart, "resp_addr")
+		cfg.RESPAddr = old.RESPAddr
+	}
+	if cfg.Placement != old.Placement || cfg.Hasher != old.Hasher {
+		restart = append(restart, "placement")
+		cfg.Placement, cfg.Hasher = old.Placement, old.Hasher
+	}
+	cfg.NF = old.NF
+	if cfg.NC == nil {
+		cfg.NC = old.NC
+	}
+	if cfg.RC == nil {
+		cfg.RC = old.RC
+	}
+	fe.cfg = cfg
+	fe.cfgLock.Unlock()
+
+	if cfg.CacheSize != old.CacheSize {
+		fe.cache.resize(cfg.CacheSize)
+	}
+
+	if cfg.Router != old.Router || !reflect.DeepEqual(cfg.Routers, old.Routers) {
+		fe.restartWatch()
+	}
+	if len(restart) > 0 {
+		return storage.RestartError{Fields: restart}
+	}
+	return nil
+}
+
+// EffectiveConfig returns the running configuration in YAML.
+//
+// EffectiveConfig возвращает текущую конфигурацию в YAML.
+func (fe *Frontend) EffectiveConfig() ([]byte, error) {
+	return yaml.Marshal(fe.config())
+}
+
+// restartWatch restarts the topology watch, if any, so that the topology
+// is fetched anew.
+func (fe *Frontend) restartWatch() {
+	fe.watchLock.Lock()
+	if fe.watchCancel != nil {
+		fe.watchCancel()
+	}
+	fe.watchLock.Unlock()
+}
@@ -1,7 +1,10 @@
 package frontend
 
 import (
+	"context"
+	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	rclient "router/client"
@@ -22,9 +25,77 @@ type Config struct {
 	// Addr is an address to listen at.
 	// Addr -- слушающий адрес Frontend.
 	Addr storage.ServiceAddr
+	// HTTPAddr is an address to serve the REST API at, see NewHTTPHandler.
+	// The API is not served if HTTPAddr is not set.
+	// HTTPAddr -- адрес, по которому обслуживается REST API, см.
+	// NewHTTPHandler. Если HTTPAddr не задан, API не обслуживается.
+	HTTPAddr storage.ServiceAddr `yaml:"http_addr"`
+	// RESPAddr is an address to serve Redis commands at, see RESPServer.
+	// They are not served if RESPAddr is not set.
+	// RESPAddr -- адрес, по которому обслуживаются команды Redis, см.
+	// RESPServer. Если RESPAddr не задан, команды не обслуживаются.
+	RESPAddr storage.ServiceAddr `yaml:"resp_addr"`
 	// Router is an address of Router service.
 	// Router -- адрес Router service.
 	Router storage.ServiceAddr
+	// Routers is a list of routers of the cluster to fail over to.
+	// Routers -- список router кластера, на которые нужно переключаться при отказе.
+	Routers []storage.ServiceAddr
+
+	// Placement is a name of a placement strategy, see router.Config.Placement.
+	// It must be the same as the one of the Router.
+	// Placement -- имя стратегии размещения, см. router.Config.Placement.
+	// Должно совпадать с заданным в Router.
+	Placement string
+	// Hasher is a name of a hasher, see router.Config.Hasher.
+	// It must be the same as the one of the Router.
+	// Hasher -- имя hasher, см. router.Config.Hasher.
+	// Должно совпадать с заданным в Router.
+	Hasher string
+
+	// ReadMode is ReadAll or ReadHedged, ReadAll if not set.
+	// ReadMode -- ReadAll или ReadHedged, по умолчанию ReadAll.
+	ReadMode string `yaml:"read_mode"`
+	// HedgeDelay is a delay after which a spare replica is requested
+	// in the ReadHedged mode. If not set, the delay is the HedgePercentile
+	// of observed latencies of requested nodes.
+	// HedgeDelay -- задержка, после которой в режиме ReadHedged запрашивается
+	// запасная реплика. Если не задана, используется процентиль HedgePercentile
+	// наблюдаемых задержек запрошенных node.
+	HedgeDelay time.Duration `yaml:"hedge_delay"`
+	// HedgePercentile is a percentile of latencies used as HedgeDelay,
+	// DefaultHedgePercentile if not set.
+	// HedgePercentile -- процентиль задержек, используемый как HedgeDelay,
+	// по умолчанию DefaultHedgePercentile.
+	HedgePercentile float64 `yaml:"hedge_percentile"`
+
+	// LivenessInterval is a time interval between fetches of alive nodes
+	// from the Router. Nodes for writes are found locally from the topology
+	// and alive nodes while they are fresh, the Router is asked otherwise.
+	// DefaultLivenessInterval is used if LivenessInterval is 0, nodes are
+	// always found by the Router if it is negative.
+	// LivenessInterval -- интервал между запросами доступных node у Router.
+	// Пока они актуальны, node для записи находятся локально по топологии
+	// и доступным node, иначе запрашиваются у Router. Если LivenessInterval
+	// равен 0, используется DefaultLivenessInterval, если отрицателен,
+	// node всегда находит Router.
+	LivenessInterval time.Duration `yaml:"liveness_interval"`
+
+	// CacheSize is a size limit of the read cache in bytes. Records got
+	// by Get are cached for CacheTTL and dropped by Put and Del made
+	// through the Frontend. The cache is off if CacheSize is 0.
+	// CacheSize -- ограничение размера кеша чтений в байтах. Записи,
+	// полученные Get, кешируются на CacheTTL и удаляются при Put и Del
+	// через данный Frontend. Кеш отключен, если CacheSize равен 0.
+	CacheSize int64 `yaml:"cache_size"`
+	// CacheTTL is a time a record is cached for, DefaultCacheTTL if not set.
+	// CacheTTL -- время хранения записи в кеше, по умолчанию DefaultCacheTTL.
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+	// CachePeers is a list of other frontends which are told to drop
+	// a record from their caches after it is written through the Frontend.
+	// CachePeers -- список других frontend, которым сообщается об удалении
+	// записи из кеша после записи через данный Frontend.
+	CachePeers []storage.ServiceAddr `yaml:"cache_peers"`
 
 	// NC specifies client for Node.
 	// NC -- клиент для node.
@@ -39,20 +110,188 @@ type Config struct {
 
 // Frontend is a frontend service.
 type Frontend struct {
-	cfg  Config
-	list []storage.ServiceAddr
-	once sync.Once
+	cfgLock sync.RWMutex
+	cfg     Config
+
+	// topology holds the current router.Topology.
+	topology atomic.Value
+	// alive holds the last aliveNodes.
+	alive atomic.Value
+	ready chan struct{}
+	once  sync.Once
+
+	watchLock   sync.Mutex
+	watchCancel context.CancelFunc
+
+	hedging *hedging
+	cache   *readCache
 }
 
 // New creates a new Frontend with a given cfg.
 //
 // New создает новый Frontend с данным cfg.
 func New(cfg Config) *Frontend {
-	return &Frontend{cfg: cfg}
+	return &Frontend{cfg: cfg, ready: make(chan struct{}), hedging: newHedging(), cache: newReadCache()}
+}
+
+// start fetches the list of nodes from the Router and, if the Router client
+// is a rclient.Watcher, follows topology changes in background. Alive nodes
+// are polled if the Router client is a rclient.AliveLister.
+func (fe *Frontend) start() {
+	for {
+		t, err := fe.list()
+		if err == nil {
+			fe.topology.Store(t)
+			break
+		}
+		time.Sleep(InitTimeout)
+	}
+	close(fe.ready)
+
+	if _, ok := fe.config().RC.(rclient.AliveLister); ok {
+		go fe.pollAlive()
+	}
+
+	if _, ok := fe.config().RC.(rclient.Watcher); !ok {
+		return
+	}
+	go func() {
+		for {
+			ctx, cancel := context.WithCancel(context.Background())
+			fe.watchLock.Lock()
+			fe.watchCancel = cancel
+			fe.watchLock.Unlock()
+
+			// the client is taken anew as it may be changed by Reload
+			cfg := fe.config()
+			w, ok := cfg.RC.(rclient.Watcher)
+			if !ok {
+				cancel()
+				return
+			}
+			err := w.WatchTopology(ctx, cfg.Router, func(t router.Topology) {
+				log.Printf("Topology epoch %v: %v", t.Epoch, t.Nodes)
+				fe.topology.Store(t)
+			})
+			cancel()
+			if ctx.Err() == nil {
+				log.Printf("Watching topology failed: %v", err)
+				time.Sleep(InitTimeout)
+			}
+		}
+	}()
+}
+
+// list fetches the topology from the Router. Weights of nodes are fetched
+// only if the Router client is a rclient.TopologyLister.
+func (fe *Frontend) list() (router.Topology, error) {
+	cfg := fe.config()
+	if l, ok := cfg.RC.(rclient.TopologyLister); ok {
+		return l.ListTopology(cfg.Router)
+	}
+	list, err := cfg.RC.List(cfg.Router)
+	return router.Topology{Nodes: list}, err
 }
 
-func (fe *Frontend) putDel(k storage.RecordID, job func(node storage.ServiceAddr) error) error {
-	nodes, err := fe.cfg.RC.NodesFind(fe.cfg.Router, k)
+// init starts fetching the topology once.
+func (fe *Frontend) init() {
+	fe.once.Do(func() {
+		go fe.start()
+	})
+}
+
+// currentTopology returns the current topology waiting for the first one
+// to be fetched from the Router until ctx is done.
+func (fe *Frontend) currentTopology(ctx context.Context) (router.Topology, error) {
+	fe.init()
+	select {
+	case <-fe.ready:
+		return fe.topology.Load().(router.Topology), nil
+	case <-ctx.Done():
+		return router.Topology{}, ctx.Err()
+	}
+}
+
+// nodesFind finds nodes for the key k from the cached topology and alive
+// nodes, see Config.LivenessInterval, or asks the Router for them. If the
+// Router reports a newer topology than the local one, the topology watch
+// is restarted to fetch it. The request is bound to ctx if the Router client
+// is a rclient.ContextClient.
+func (fe *Frontend) nodesFind(ctx context.Context, k storage.RecordID) ([]storage.ServiceAddr, error) {
+	cfg := fe.config()
+	if _, ok := cfg.RC.(rclient.AliveLister); ok {
+		fe.init()
+		if nodes, ok := fe.cachedNodes(cfg, k); ok {
+			return nodes, nil
+		}
+	}
+	cc, hasContext := cfg.RC.(rclient.ContextClient)
+	w, ok := cfg.RC.(rclient.Watcher)
+	if !ok {
+		if hasContext {
+			return cc.NodesFindContext(ctx, cfg.Router, k)
+		}
+		return cfg.RC.NodesFind(cfg.Router, k)
+	}
+	var (
+		nodes []storage.ServiceAddr
+		epoch uint64
+		err   error
+	)
+	if hasContext {
+		nodes, epoch, err = cc.NodesFindEpochContext(ctx, cfg.Router, k)
+	} else {
+		nodes, epoch, err = w.NodesFindEpoch(cfg.Router, k)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := fe.topology.Load().(router.Topology); ok && t.Epoch < epoch {
+		log.Printf("Topology epoch %v is stale, router has %v", t.Epoch, epoch)
+		fe.restartWatch()
+	}
+	return nodes, nil
+}
+
+// delNodes finds nodes to delete the key k from: the nodes for writes,
+// see nodesFind, and draining nodes of the placement for reads, which
+// hold records until they are handed off. Draining nodes are known only
+// if the Router client is a rclient.TopologyLister.
+func (fe *Frontend) delNodes(ctx context.Context, k storage.RecordID) ([]storage.ServiceAddr, error) {
+	nodes, err := fe.nodesFind(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+	cfg := fe.config()
+	if _, ok := cfg.RC.(rclient.TopologyLister); !ok {
+		return nodes, nil
+	}
+	t, err := fe.currentTopology(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range cfg.NF.NodesFindTopology(k, t) {
+		if t.States[node] == router.NodeDraining && !hasNode(nodes, node) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+func hasNode(nodes []storage.ServiceAddr, node storage.ServiceAddr) bool {
+	for _, n := range nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
+
+// putDel runs job on all nodes found by find for the key k. It waits
+// for every node so that all replicas are updated, only a done ctx
+// cancels the jobs.
+func (fe *Frontend) putDel(ctx context.Context, k storage.RecordID, find func(ctx context.Context, k storage.RecordID) ([]storage.ServiceAddr, error), job func(node storage.ServiceAddr) error) error {
+	nodes, err := find(ctx, k)
 	if err != nil {
 		return err
 	}
@@ -60,17 +299,30 @@ func (fe *Frontend) putDel(k storage.RecordID, job func(node storage.ServiceAddr
 		return storage.ErrNotEnoughDaemons
 	}
 
-	et := make(map[error]int)
 	ch := make(chan error, len(nodes))
 
 	for _, node := range nodes {
 		go func(node storage.ServiceAddr) {
-			ch <- job(node)
+			err := job(node)
+			if err != nil && storage.ErrToStatus(err) == storage.StatusUnknown {
+				// the node may have died since alive nodes were fetched
+				fe.invalidateAlive()
+			}
+			ch <- err
 		}(node)
 	}
 
+	errs := make([]error, 0, len(nodes))
 	for range nodes {
-		err := <-ch
+		errs = append(errs, <-ch)
+	}
+	return writeQuorum(errs)
+}
+
+// writeQuorum returns the outcome of a write given errors of all nodes.
+func writeQuorum(errs []error) error {
+	et := make(map[error]int)
+	for _, err := range errs {
 		if err != nil {
 			et[err]++
 		}
@@ -82,7 +334,7 @@ func (fe *Frontend) putDel(k storage.RecordID, job func(node storage.ServiceAddr
 		}
 	}
 
-	if len(nodes)-len(et) >= storage.MinRedundancy {
+	if len(errs)-len(et) >= storage.MinRedundancy {
 		return nil
 	}
 
@@ -95,8 +347,18 @@ func (fe *Frontend) putDel(k storage.RecordID, job func(node storage.ServiceAddr
 // Put -- добавить запись в хранилище, если запись для данного ключа
 // не существует. Иначе вернуть ошибку.
 func (fe *Frontend) Put(k storage.RecordID, d []byte) error {
-	return fe.putDel(k, func(node storage.ServiceAddr) error {
-		return fe.cfg.NC.Put(node, k, d)
+	return fe.PutContext(context.Background(), k, d)
+}
+
+// PutContext is like Put but requests to the Router and nodes are bound to ctx.
+//
+// PutContext -- то же, что Put, но запросы к Router и node привязаны к ctx.
+func (fe *Frontend) PutContext(ctx context.Context, k storage.RecordID, d []byte) error {
+	cfg := fe.config()
+	defer fe.invalidate(cfg, k)
+	nc := contextClient(cfg.NC)
+	return fe.putDel(ctx, k, fe.nodesFind, func(node storage.ServiceAddr) error {
+		return nc.PutContext(ctx, node, k, d)
 	})
 }
 
@@ -106,8 +368,18 @@ func (fe *Frontend) Put(k storage.RecordID, d []byte) error {
 // Del -- удалить запись из хранилища, если запись для данного ключа
 // существует. Иначе вернуть ошибку.
 func (fe *Frontend) Del(k storage.RecordID) error {
-	return fe.putDel(k, func(node storage.ServiceAddr) error {
-		return fe.cfg.NC.Del(node, k)
+	return fe.DelContext(context.Background(), k)
+}
+
+// DelContext is like Del but requests to the Router and nodes are bound to ctx.
+//
+// DelContext -- то же, что Del, но запросы к Router и node привязаны к ctx.
+func (fe *Frontend) DelContext(ctx context.Context, k storage.RecordID) error {
+	cfg := fe.config()
+	defer fe.invalidate(cfg, k)
+	nc := contextClient(cfg.NC)
+	return fe.putDel(ctx, k, fe.delNodes, func(node storage.ServiceAddr) error {
+		return nc.DelContext(ctx, node, k)
 	})
 }
 
@@ -117,31 +389,64 @@ func (fe *Frontend) Del(k storage.RecordID) error {
 // Get -- получить запись из хранилища, если запись для данного ключа
 // существует. Иначе вернуть ошибку.
 func (fe *Frontend) Get(k storage.RecordID) ([]byte, error) {
-	fe.once.Do(func() {		
-		for {
-			var err error
-			fe.list, err = fe.cfg.RC.List(fe.cfg.Router)
-			if err == nil {
-				break
-			}
-			time.Sleep(InitTimeout)
-		}
-	})
+	return fe.GetContext(context.Background(), k)
+}
 
-	nodes := fe.cfg.NF.NodesFind(k, fe.list)
+// GetContext is like Get but requests to nodes are bound to ctx. Requests
+// still outstanding when the quorum outcome is known are cancelled.
+//
+// GetContext -- то же, что Get, но запросы к node привязаны к ctx. Запросы,
+// не завершенные к моменту, когда исход кворума известен, отменяются.
+func (fe *Frontend) GetContext(ctx context.Context, k storage.RecordID) ([]byte, error) {
+	cfg := fe.config()
+	if cfg.CacheSize <= 0 {
+		return fe.get(ctx, k)
+	}
+	data, gen, ok := fe.cache.get(k)
+	if ok {
+		return data, nil
+	}
+	data, err := fe.get(ctx, k)
+	if err == nil {
+		fe.cache.put(k, data, gen, cacheTTL(cfg), cfg.CacheSize)
+	}
+	return data, err
+}
+
+// get gets the record k from a quorum of nodes.
+func (fe *Frontend) get(ctx context.Context, k storage.RecordID) ([]byte, error) {
+	t, err := fe.currentTopology(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cfg := fe.config()
+	nc := contextClient(cfg.NC)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	nodes := cfg.NF.NodesFindTopology(k, t)
+	readable := nodes[:0]
+	for _, node := range nodes {
+		if t.States[node].Readable() {
+			readable = append(readable, node)
+		}
+	}
+	nodes = readable
+	if cfg.ReadMode == ReadHedged && len(nodes) > storage.MinRedundancy {
+		return fe.getHedged(ctx, cfg, nc, k, nodes)
+	}
 	dataMap := make(map[string]int)
 	errorMap := make(map[error]int)
 
 	type result struct {
 		data []byte
-		err error
+		err  error
 	}
 
 	resChan := make(chan result, len(nodes))
 
 	for _, node := range nodes {
 		go func(node storage.ServiceAddr) {
-			tempData, tempError := fe.cfg.NC.Get(node, k)
+			tempData, tempError := fe.getNode(ctx, nc, node, k)
 			resChan <- result{tempData, tempError}
 		}(node)
 	}
@@ -164,3 +469,28 @@ func (fe *Frontend) Get(k storage.RecordID) ([]byte, error) {
 	}
 	return nil, storage.ErrQuorumNotReached
 }
+
+// contextClient returns nc as a storage.ContextClient. Contexts are ignored
+// if nc does not accept them.
+func contextClient(nc storage.Client) storage.ContextClient {
+	if c, ok := nc.(storage.ContextClient); ok {
+		return c
+	}
+	return noContextClient{nc}
+}
+
+type noContextClient struct {
+	storage.Client
+}
+
+func (c noContextClient) PutContext(ctx context.Context, node storage.ServiceAddr, k storage.RecordID, d []byte) error {
+	return c.Put(node, k, d)
+}
+
+func (c noContextClient) GetContext(ctx context.Context, node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+	return c.Get(node, k)
+}
+
+func (c noContextClient) DelContext(ctx context.Context, node storage.ServiceAddr, k storage.RecordID) error {
+	return c.Del(node, k)
+}
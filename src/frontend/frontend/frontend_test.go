@@ -1,8 +1,11 @@
 package frontend
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"reflect"
 	"runtime"
 	"sync"
@@ -551,3 +554,837 @@ func TestParallelOps(t *testing.T) {
 	}()
 	time.Sleep(3 * time.Second)
 }
+
+type MockWatcher struct {
+	MockRouter
+	epoch   uint64
+	updates chan router.Topology
+	watches uint32
+}
+
+func (r *MockWatcher) WatchTopology(ctx context.Context, rtr storage.ServiceAddr, cb func(router.Topology)) error {
+	atomic.AddUint32(&r.watches, 1)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case t := <-r.updates:
+			cb(t)
+		}
+	}
+}
+
+func (r *MockWatcher) NodesFindEpoch(rtr storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, uint64, error) {
+	nodes, err := r.nodesFind(rtr, k)
+	return nodes, r.epoch, err
+}
+
+func TestGet_TopologyChange(t *testing.T) {
+	key := storage.RecordID(1)
+	testData := []byte("test")
+	oldNodes := []storage.ServiceAddr{"node1", "node2", "node3"}
+	newNodes := []storage.ServiceAddr{"node4", "node5", "node6"}
+
+	rc := &MockWatcher{updates: make(chan router.Topology)}
+	rc.list = func(router storage.ServiceAddr) ([]storage.ServiceAddr, error) {
+		return oldNodes, nil
+	}
+	rc.nodesFind = func(router storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, error) {
+		return newNodes, nil
+	}
+
+	var lock sync.Mutex
+	used := make(map[storage.ServiceAddr]bool)
+	nc := new(MockNode)
+	nc.get = func(node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+		lock.Lock()
+		used[node] = true
+		lock.Unlock()
+		return testData, nil
+	}
+	nc.put = func(node storage.ServiceAddr, k storage.RecordID, d []byte) error {
+		return nil
+	}
+
+	hashes := make(map[storage.ServiceAddr]uint64)
+	for i, node := range append(oldNodes, newNodes...) {
+		hashes[node] = uint64(i)
+	}
+	fe := New(Config{
+		RC:     rc,
+		NC:     nc,
+		NF:     router.NewNodesFinder(FakeHasher{t: t, hashes: hashes}),
+		Router: "router",
+	})
+
+	if _, err := fe.Get(key); err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	rc.updates <- router.Topology{Epoch: 2, Nodes: newNodes}
+	// let the update be applied and the late replies of the first Get arrive
+	time.Sleep(50 * time.Millisecond)
+
+	lock.Lock()
+	used = make(map[storage.ServiceAddr]bool)
+	lock.Unlock()
+	if _, err := fe.Get(key); err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	lock.Lock()
+	for _, node := range oldNodes {
+		if used[node] {
+			t.Errorf("Get() used node %v of the old topology", node)
+		}
+	}
+	lock.Unlock()
+
+	rc.epoch = 3
+	if err := fe.Put(key, testData); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := atomic.LoadUint32(&rc.watches); got != 2 {
+		t.Errorf("Topology watch was started %v times after a stale epoch, want 2", got)
+	}
+}
+
+type MockTopologyLister struct {
+	MockRouter
+	topology router.Topology
+}
+
+func (r *MockTopologyLister) ListTopology(rtr storage.ServiceAddr) (router.Topology, error) {
+	return r.topology, nil
+}
+
+func TestGet_Weights(t *testing.T) {
+	key := storage.RecordID(1)
+	testData := []byte("test")
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3", "node4"}
+
+	rc := &MockTopologyLister{topology: router.Topology{
+		Nodes:   nodes,
+		Weights: map[storage.ServiceAddr]float64{"node4": 100},
+	}}
+	var lock sync.Mutex
+	used := make(map[storage.ServiceAddr]bool)
+	nc := new(MockNode)
+	nc.get = func(node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+		lock.Lock()
+		used[node] = true
+		lock.Unlock()
+		return testData, nil
+	}
+
+	hashes := make(map[storage.ServiceAddr]uint64)
+	for i, node := range nodes {
+		hashes[node] = uint64(len(nodes)-i) << 60
+	}
+	fe := New(Config{
+		RC:     rc,
+		NC:     nc,
+		NF:     router.NewNodesFinder(FakeHasher{t: t, hashes: hashes}),
+		Router: "router",
+	})
+
+	if _, err := fe.Get(key); err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	if !used["node4"] || used["node3"] {
+		t.Errorf("Get() used nodes %v, want the heavy node4 instead of node3", used)
+	}
+}
+
+func TestGet_Maintenance(t *testing.T) {
+	key := storage.RecordID(1)
+	testData := []byte("test")
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3"}
+
+	rc := &MockTopologyLister{topology: router.Topology{
+		Nodes:  nodes,
+		States: map[storage.ServiceAddr]router.NodeState{"node1": router.NodeMaintenance},
+	}}
+	nc := new(MockNode)
+	nc.get = func(node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+		if node == "node1" {
+			t.Errorf("Get() requested node1 in maintenance")
+		}
+		return testData, nil
+	}
+
+	fe := New(Config{
+		RC:     rc,
+		NC:     nc,
+		NF:     router.NewNodesFinder(router.NewMD5Hasher()),
+		Router: "router",
+	})
+
+	if got, err := fe.Get(key); err != nil || !reflect.DeepEqual(got, testData) {
+		t.Errorf("Get() got %q, %v, want %q", got, err, testData)
+	}
+}
+
+func TestGetDel_Draining(t *testing.T) {
+	key := storage.RecordID(1)
+	testData := []byte("test")
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3", "node4"}
+
+	rc := &MockTopologyLister{topology: router.Topology{
+		Nodes:  nodes,
+		States: map[storage.ServiceAddr]router.NodeState{"node4": router.NodeDraining},
+	}}
+	rc.nodesFind = func(rtr storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, error) {
+		return nodes[:3], nil
+	}
+	hashes := make(map[storage.ServiceAddr]uint64)
+	for i, node := range nodes {
+		hashes[node] = uint64(i+1) << 60
+	}
+	// the record was put before node4 started draining
+	nc := newMemoryNode()
+	for _, node := range nodes[1:] {
+		if err := nc.Put(node, key, testData); err != nil {
+			t.Fatalf("Put() error: %v", err)
+		}
+	}
+
+	fe := New(Config{
+		RC:     rc,
+		NC:     nc,
+		NF:     router.NewNodesFinder(FakeHasher{t: t, hashes: hashes}),
+		Router: "router",
+	})
+
+	if got, err := fe.Get(key); err != nil || !reflect.DeepEqual(got, testData) {
+		t.Errorf("Get() got %q, %v, want %q", got, err, testData)
+	}
+	if err := fe.Del(key); err != nil {
+		t.Fatalf("Del() error: %v", err)
+	}
+	if _, err := nc.Get("node4", key); err != storage.ErrRecordNotFound {
+		t.Errorf("Get() from draining node4 after Del() got error %v, want %v", err, storage.ErrRecordNotFound)
+	}
+}
+
+// MockContextNode is a MockNode whose Get requests take a context.
+type MockContextNode struct {
+	MockNode
+	getContext func(ctx context.Context, node storage.ServiceAddr, k storage.RecordID) ([]byte, error)
+}
+
+func (n *MockContextNode) PutContext(ctx context.Context, node storage.ServiceAddr, k storage.RecordID, d []byte) error {
+	return n.put(node, k, d)
+}
+
+func (n *MockContextNode) GetContext(ctx context.Context, node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+	return n.getContext(ctx, node, k)
+}
+
+func (n *MockContextNode) DelContext(ctx context.Context, node storage.ServiceAddr, k storage.RecordID) error {
+	return n.del(node, k)
+}
+
+func TestGetContext_Cancel(t *testing.T) {
+	key := storage.RecordID(1)
+	testData := []byte("test")
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3"}
+
+	rc := &MockTopologyLister{topology: router.Topology{Nodes: nodes}}
+	const slow = "node1"
+	cancelled := make(chan struct{})
+	nc := new(MockContextNode)
+	nc.getContext = func(ctx context.Context, node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+		if string(node) != slow {
+			return testData, nil
+		}
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	}
+	fe := New(Config{
+		RC:     rc,
+		NC:     nc,
+		NF:     router.NewNodesFinder(router.NewMD5Hasher()),
+		Router: "router",
+	})
+
+	if got, err := fe.GetContext(context.Background(), key); err != nil || !reflect.DeepEqual(got, testData) {
+		t.Fatalf("GetContext() got %q, %v, want %q", got, err, testData)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatalf("GetContext() did not cancel the slow replica after the quorum")
+	}
+
+	// no quorum is possible before the deadline
+	nc.getContext = func(ctx context.Context, node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := fe.GetContext(ctx, key); err != context.DeadlineExceeded {
+		t.Errorf("GetContext() got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestGet_Hedged(t *testing.T) {
+	key := storage.RecordID(1)
+	testData := []byte("test")
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3"}
+	nf := router.NewNodesFinder(router.NewMD5Hasher())
+	order := nf.NodesFindTopology(key, router.Topology{Nodes: nodes})
+
+	for _, test := range []struct {
+		name  string
+		slow  storage.ServiceAddr
+		fail  storage.ServiceAddr
+		stats HedgeStats
+	}{
+		{name: "fast", stats: HedgeStats{Reads: 1}},
+		{name: "slow", slow: order[0], stats: HedgeStats{Reads: 1, Hedged: 1}},
+		{name: "spare slow", slow: order[2], stats: HedgeStats{Reads: 1}},
+		{name: "failed", fail: order[1], stats: HedgeStats{Reads: 1, Fallbacks: 1}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var lock sync.Mutex
+			used := make(map[storage.ServiceAddr]bool)
+			nc := new(MockContextNode)
+			nc.getContext = func(ctx context.Context, node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+				lock.Lock()
+				used[node] = true
+				lock.Unlock()
+				if node == test.fail {
+					return nil, errors.New("failed")
+				}
+				if node == test.slow {
+					select {
+					case <-time.After(time.Second):
+					case <-ctx.Done():
+						return nil, ctx.Err()
+					}
+				}
+				return testData, nil
+			}
+			fe := New(Config{
+				RC:         &MockTopologyLister{topology: router.Topology{Nodes: nodes}},
+				NC:         nc,
+				NF:         nf,
+				Router:     "router",
+				ReadMode:   ReadHedged,
+				HedgeDelay: 50 * time.Millisecond,
+			})
+
+			start := time.Now()
+			if got, err := fe.Get(key); err != nil || !reflect.DeepEqual(got, testData) {
+				t.Fatalf("Get() got %q, %v, want %q", got, err, testData)
+			}
+			if d := time.Since(start); d > 500*time.Millisecond {
+				t.Errorf("Get() took %v", d)
+			}
+			if got := fe.HedgeStats(); got != test.stats {
+				t.Errorf("HedgeStats() got %+v, want %+v", got, test.stats)
+			}
+			if test.slow != "" {
+				// the slow node returns after the cancellation
+				time.Sleep(50 * time.Millisecond)
+				fe.hedging.mu.Lock()
+				_, ok := fe.hedging.latencies[test.slow]
+				fe.hedging.mu.Unlock()
+				if ok {
+					t.Errorf("Get() remembered the latency of the cancelled request to %v", test.slow)
+				}
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			if spare := test.stats != (HedgeStats{Reads: 1}); used[order[2]] != spare {
+				t.Errorf("Get() requested the spare replica: %v, want %v", used[order[2]], spare)
+			}
+		})
+	}
+}
+
+func TestHedgeDelay(t *testing.T) {
+	nodes := []storage.ServiceAddr{"node1", "node2"}
+	fe := New(Config{})
+	if got := fe.hedgeDelay(fe.config(), nodes); got != DefaultHedgeDelay {
+		t.Errorf("hedgeDelay() without latencies got %v, want %v", got, DefaultHedgeDelay)
+	}
+	for i := 1; i <= 100; i++ {
+		fe.hedging.record(nodes[0], time.Duration(i)*time.Millisecond)
+		fe.hedging.record(nodes[1], time.Duration(i)*time.Microsecond)
+	}
+	if got, want := fe.hedgeDelay(fe.config(), nodes), 95*time.Millisecond; got != want {
+		t.Errorf("hedgeDelay() got %v, want %v", got, want)
+	}
+	if got, want := fe.hedgeDelay(Config{HedgePercentile: 50}, nodes), 50*time.Millisecond; got != want {
+		t.Errorf("hedgeDelay() of the median got %v, want %v", got, want)
+	}
+	if got, want := fe.hedgeDelay(Config{HedgeDelay: time.Second}, nodes), time.Second; got != want {
+		t.Errorf("hedgeDelay() with HedgeDelay got %v, want %v", got, want)
+	}
+}
+
+type MockAliveLister struct {
+	MockTopologyLister
+	alive []storage.ServiceAddr
+	calls uint32
+}
+
+func (r *MockAliveLister) NodesFind(rtr storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, error) {
+	atomic.AddUint32(&r.calls, 1)
+	return r.alive[:storage.ReplicationFactor], nil
+}
+
+func (r *MockAliveLister) ListAlive(rtr storage.ServiceAddr) ([]storage.ServiceAddr, uint64, error) {
+	return r.alive, r.topology.Epoch, nil
+}
+
+func TestPutDel_CachedNodes(t *testing.T) {
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3", "node4"}
+	rc := &MockAliveLister{
+		MockTopologyLister: MockTopologyLister{topology: router.Topology{Epoch: 1, Nodes: nodes}},
+		alive:              nodes[:3],
+	}
+	var lock sync.Mutex
+	written := make(map[storage.ServiceAddr]bool)
+	failed := storage.ServiceAddr("")
+	nc := new(MockNode)
+	nc.put = func(node storage.ServiceAddr, k storage.RecordID, d []byte) error {
+		lock.Lock()
+		defer lock.Unlock()
+		written[node] = true
+		if node == failed {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	nf := router.NewNodesFinder(router.NewMD5Hasher())
+	fe := New(Config{
+		RC:               rc,
+		NC:               nc,
+		NF:               nf,
+		Router:           "router",
+		LivenessInterval: time.Minute,
+	})
+
+	// alive nodes are fetched in background after the first request
+	fe.Put(0, nil)
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if _, ok := fe.cachedNodes(fe.config(), 0); ok {
+			break
+		}
+		if time.Since(start) > time.Second {
+			t.Fatalf("Alive nodes are not fetched")
+		}
+	}
+
+	calls := atomic.LoadUint32(&rc.calls)
+	for k := storage.RecordID(1); k < 10; k++ {
+		lock.Lock()
+		written = make(map[storage.ServiceAddr]bool)
+		lock.Unlock()
+		if err := fe.Put(k, nil); err != nil {
+			t.Fatalf("Put() error: %v", err)
+		}
+		var want []storage.ServiceAddr
+		for _, node := range nf.NodesFindTopology(k, rc.topology) {
+			if node != nodes[3] {
+				want = append(want, node)
+			}
+		}
+		lock.Lock()
+		for _, node := range want {
+			if !written[node] {
+				t.Errorf("Put(%v) did not write to %v, want %v", k, node, want)
+			}
+		}
+		if written[nodes[3]] {
+			t.Errorf("Put(%v) wrote to the dead %v", k, nodes[3])
+		}
+		lock.Unlock()
+	}
+	if got := atomic.LoadUint32(&rc.calls); got != calls {
+		t.Errorf("Put() asked the router %v times, want cached nodes", got-calls)
+	}
+
+	// a node dies between fetches of alive nodes, a key without
+	// the dead node4 still gets a quorum
+	key := storage.RecordID(0)
+	for ; ; key++ {
+		placed := nf.NodesFindTopology(key, rc.topology)
+		if placed[0] != nodes[3] && placed[1] != nodes[3] && placed[2] != nodes[3] {
+			break
+		}
+	}
+	lock.Lock()
+	failed = nf.NodesFindTopology(key, rc.topology)[0]
+	lock.Unlock()
+	if err := fe.Put(key, nil); err != nil {
+		t.Errorf("Put() with a dead node error: %v", err)
+	}
+	fe.Put(2, nil)
+	if got := atomic.LoadUint32(&rc.calls); got != calls+1 {
+		t.Errorf("Put() after a node failure asked the router %v times, want 1", got-calls)
+	}
+
+	// alive nodes of another epoch are not used
+	fe.alive.Store(aliveNodes{epoch: 2, nodes: map[storage.ServiceAddr]bool{"node1": true}, at: time.Now()})
+	fe.Put(3, nil)
+	if got := atomic.LoadUint32(&rc.calls); got != calls+2 {
+		t.Errorf("Put() with stale alive nodes asked the router %v times, want 2", got-calls)
+	}
+}
+
+// MockInvalidatorNode is a MockNode which can send invalidations.
+type MockInvalidatorNode struct {
+	MockNode
+	invalidated chan storage.ServiceAddr
+}
+
+func (n *MockInvalidatorNode) Invalidate(node storage.ServiceAddr, k storage.RecordID) error {
+	n.invalidated <- node
+	return nil
+}
+
+func TestGet_Cache(t *testing.T) {
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3"}
+	rc := &MockTopologyLister{topology: router.Topology{Nodes: nodes}}
+	rc.nodesFind = func(rtr storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, error) {
+		return nodes, nil
+	}
+	var gets uint32
+	nc := &MockInvalidatorNode{invalidated: make(chan storage.ServiceAddr, 1)}
+	nc.get = func(node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+		atomic.AddUint32(&gets, 1)
+		return []byte(fmt.Sprintf("value%d", k)), nil
+	}
+	nc.put = func(node storage.ServiceAddr, k storage.RecordID, d []byte) error {
+		return nil
+	}
+	cfg := Config{
+		RC:     rc,
+		NC:     nc,
+		NF:     router.NewNodesFinder(router.NewMD5Hasher()),
+		Router: "router",
+	}
+	fe := New(cfg)
+
+	// cached reports whether the record k was got from the cache
+	cached := func(k storage.RecordID) bool {
+		before := atomic.LoadUint32(&gets)
+		got, err := fe.Get(k)
+		if want := []byte(fmt.Sprintf("value%d", k)); err != nil || !reflect.DeepEqual(got, want) {
+			t.Fatalf("Get(%v) got %q, %v, want %q", k, got, err, want)
+		}
+		return atomic.LoadUint32(&gets) == before
+	}
+
+	if cached(1) || cached(1) {
+		t.Errorf("Get() used the cache which is off by default")
+	}
+	if got := fe.CacheStats(); got != (CacheStats{}) {
+		t.Errorf("CacheStats() got %+v with the cache off", got)
+	}
+
+	cfg.CacheSize = 1 << 20
+	cfg.CachePeers = []storage.ServiceAddr{"frontend2"}
+	fe.Reload(cfg)
+	if cached(1) || !cached(1) {
+		t.Errorf("Get() did not cache a record")
+	}
+	if err := fe.Put(1, nil); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	if cached(1) {
+		t.Errorf("Get() got a record from the cache after Put()")
+	}
+	select {
+	case peer := <-nc.invalidated:
+		if peer != "frontend2" {
+			t.Errorf("Put() invalidated the record at %v, want frontend2", peer)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Put() did not invalidate the record at the cache peer")
+	}
+	fe.Invalidate(1)
+	if cached(1) {
+		t.Errorf("Get() got a record from the cache after Invalidate()")
+	}
+
+	// only one record fits
+	cfg.CacheSize = 100
+	fe.Reload(cfg)
+	cached(1)
+	cached(2)
+	if cached(1) {
+		t.Errorf("Get() got a record from the cache which was evicted")
+	}
+
+	cfg.CacheTTL = 10 * time.Millisecond
+	fe.Reload(cfg)
+	cached(3)
+	time.Sleep(20 * time.Millisecond)
+	if cached(3) {
+		t.Errorf("Get() got an expired record from the cache")
+	}
+
+	want := CacheStats{Hits: 2, Misses: 7, Evictions: 3, Bytes: 70}
+	if got := fe.CacheStats(); got != want {
+		t.Errorf("CacheStats() got %+v, want %+v", got, want)
+	}
+}
+
+func TestReload(t *testing.T) {
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3"}
+	rc := &MockWatcher{updates: make(chan router.Topology)}
+	rc.list = func(router storage.ServiceAddr) ([]storage.ServiceAddr, error) {
+		return nodes, nil
+	}
+	nc := new(MockNode)
+	nc.get = func(node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+		return []byte("test"), nil
+	}
+	fe := New(Config{
+		Addr:   "frontend",
+		RC:     rc,
+		NC:     nc,
+		NF:     router.NewNodesFinder(router.NewMD5Hasher()),
+		Router: "router1",
+	})
+	if _, err := fe.Get(1); err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	if err := fe.Reload(Config{Addr: "frontend", Router: "router1"}); err != nil {
+		t.Errorf("Reload() error: %v", err)
+	}
+	err := fe.Reload(Config{Addr: "frontend", Router: "router2", Placement: router.PlacementRing})
+	if e, ok := err.(storage.RestartError); !ok || !reflect.DeepEqual(e.Fields, []string{"placement"}) {
+		t.Errorf("Reload() got error %v, want restart of placement", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := atomic.LoadUint32(&rc.watches); got != 2 {
+		t.Errorf("Topology watch was started %v times after routers changed, want 2", got)
+	}
+	cfg := fe.Config()
+	if cfg.Router != "router2" || cfg.Placement != "" || cfg.RC != rc || cfg.NC != nc {
+		t.Errorf("Config() after Reload() got %+v", cfg)
+	}
+	if _, err := fe.Get(1); err != nil {
+		t.Errorf("Get() after Reload() error: %v", err)
+	}
+}
+
+// MockMultiNode is a MockNode which counts batched requests.
+type MockMultiNode struct {
+	MockNode
+	batches uint32
+}
+
+func (n *MockMultiNode) MultiGet(ctx context.Context, node storage.ServiceAddr, keys []storage.RecordID) []storage.Result {
+	atomic.AddUint32(&n.batches, 1)
+	return singleClient{contextClient(&n.MockNode)}.MultiGet(ctx, node, keys)
+}
+
+func (n *MockMultiNode) MultiPut(ctx context.Context, node storage.ServiceAddr, records []storage.Record) []storage.Result {
+	atomic.AddUint32(&n.batches, 1)
+	return singleClient{contextClient(&n.MockNode)}.MultiPut(ctx, node, records)
+}
+
+func (n *MockMultiNode) MultiDel(ctx context.Context, node storage.ServiceAddr, keys []storage.RecordID) []storage.Result {
+	atomic.AddUint32(&n.batches, 1)
+	return singleClient{contextClient(&n.MockNode)}.MultiDel(ctx, node, keys)
+}
+
+func TestMulti(t *testing.T) {
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3"}
+	rc := &MockTopologyLister{topology: router.Topology{Nodes: nodes}}
+	rc.nodesFind = func(rtr storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, error) {
+		return nodes, nil
+	}
+	// key 2 is lost by node3, key 3 by node2 and node3
+	fail := func(node storage.ServiceAddr, k storage.RecordID) error {
+		if (k == 2 && node == "node3") || (k == 3 && node != "node1") {
+			return storage.ErrRecordNotFound
+		}
+		return nil
+	}
+	nc := new(MockMultiNode)
+	nc.get = func(node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+		if err := fail(node, k); err != nil {
+			return nil, err
+		}
+		return []byte(fmt.Sprintf("value%d", k)), nil
+	}
+	nc.put = func(node storage.ServiceAddr, k storage.RecordID, d []byte) error {
+		if want := fmt.Sprintf("value%d", k); string(d) != want {
+			t.Errorf("Put(%v) got %q, want %q", k, d, want)
+		}
+		return fail(node, k)
+	}
+	nc.del = func(node storage.ServiceAddr, k storage.RecordID) error {
+		return fail(node, k)
+	}
+	cfg := Config{
+		RC:     rc,
+		NC:     nc,
+		NF:     router.NewNodesFinder(router.NewMD5Hasher()),
+		Router: "router",
+	}
+
+	keys := []storage.RecordID{1, 2, 3}
+	var records []storage.Record
+	for _, k := range keys {
+		records = append(records, storage.Record{Key: k, Data: []byte(fmt.Sprintf("value%d", k))})
+	}
+	wantErrs := []error{nil, nil, storage.ErrRecordNotFound}
+	check := func(name string, results []storage.Result, read bool) {
+		if len(results) != len(keys) {
+			t.Fatalf("%v() got %v results, want %v", name, len(results), len(keys))
+		}
+		for i, res := range results {
+			if res.Key != keys[i] || res.Err != wantErrs[i] {
+				t.Errorf("%v() got %v, %v for key %v, want %v, %v", name, res.Key, res.Err, keys[i], keys[i], wantErrs[i])
+			}
+			if want := records[i].Data; read && res.Err == nil && !reflect.DeepEqual(res.Data, want) {
+				t.Errorf("%v() got %q for key %v, want %q", name, res.Data, res.Key, want)
+			}
+		}
+	}
+
+	for _, batched := range []bool{true, false} {
+		cfg.NC = nc
+		if !batched {
+			cfg.NC = &nc.MockNode
+		}
+		fe := New(cfg)
+		atomic.StoreUint32(&nc.batches, 0)
+		check("MultiPut", fe.MultiPut(context.Background(), records), false)
+		check("MultiGet", fe.MultiGet(context.Background(), keys), true)
+		check("MultiDel", fe.MultiDel(context.Background(), keys), false)
+		want := uint32(0)
+		if batched {
+			// one batch per node for each request
+			want = 3 * uint32(len(nodes))
+		}
+		if got := atomic.LoadUint32(&nc.batches); got != want {
+			t.Errorf("Sent %v batches (batched %v), want %v", got, batched, want)
+		}
+	}
+}
+
+func TestMulti_ConcurrentFind(t *testing.T) {
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3"}
+	keys := []storage.RecordID{1, 2, 3, 4}
+	// lookups of all keys block until all of them are started
+	var started sync.WaitGroup
+	started.Add(len(keys))
+	rc := &MockRouter{nodesFind: func(rtr storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, error) {
+		started.Done()
+		started.Wait()
+		return nodes, nil
+	}}
+	nc := new(MockMultiNode)
+	nc.del = func(node storage.ServiceAddr, k storage.RecordID) error {
+		return nil
+	}
+	fe := New(Config{
+		RC:     rc,
+		NC:     nc,
+		NF:     router.NewNodesFinder(router.NewMD5Hasher()),
+		Router: "router",
+	})
+	done := make(chan []storage.Result)
+	go func() { done <- fe.MultiDel(context.Background(), keys) }()
+	select {
+	case results := <-done:
+		for i, res := range results {
+			if res.Key != keys[i] || res.Err != nil {
+				t.Errorf("MultiDel() got %v, %v, want %v, <nil>", res.Key, res.Err, keys[i])
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("MultiDel() looked up nodes of keys one by one")
+	}
+}
+
+func TestStream(t *testing.T) {
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3"}
+	rc := &MockTopologyLister{topology: router.Topology{Nodes: nodes}}
+	rc.nodesFind = func(rtr storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, error) {
+		return nodes, nil
+	}
+	value := bytes.Repeat([]byte("0123456789"), storage.ChunkSize)
+	var (
+		lock    sync.Mutex
+		values  = make(map[storage.ServiceAddr][]byte)
+		corrupt bool
+	)
+	nc := new(MockNode)
+	nc.put = func(node storage.ServiceAddr, k storage.RecordID, d []byte) error {
+		if node == "node3" {
+			return errors.New("node3 is down")
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		values[node] = d
+		return nil
+	}
+	nc.get = func(node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		d, ok := values[node]
+		if !ok {
+			return nil, storage.ErrRecordNotFound
+		}
+		if corrupt {
+			// the value changes between the digest and the stream
+			values[node] = []byte("corrupt")
+		}
+		return d, nil
+	}
+	fe := New(Config{
+		RC:     rc,
+		NC:     nc,
+		NF:     router.NewNodesFinder(router.NewMD5Hasher()),
+		Router: "router",
+	})
+
+	ctx := context.Background()
+	if err := fe.GetStream(ctx, 1, ioutil.Discard); err != storage.ErrRecordNotFound {
+		t.Errorf("GetStream() got error %v, want %v", err, storage.ErrRecordNotFound)
+	}
+	if err := fe.PutStream(ctx, 1, bytes.NewReader(value)); err != nil {
+		t.Fatalf("PutStream() error: %v", err)
+	}
+	for _, node := range nodes[:2] {
+		if !bytes.Equal(values[node], value) {
+			t.Errorf("PutStream() put %v bytes to %v, want %v", len(values[node]), node, len(value))
+		}
+	}
+
+	// node3 has a different value, a quorum is still reached
+	lock.Lock()
+	values["node3"] = []byte("stale")
+	lock.Unlock()
+	var buf bytes.Buffer
+	if err := fe.GetStream(ctx, 1, &buf); err != nil || !bytes.Equal(buf.Bytes(), value) {
+		t.Errorf("GetStream() got %v bytes, %v, want %v bytes", buf.Len(), err, len(value))
+	}
+	if d, err := fe.Digest(ctx, 1); err != nil || !bytes.Equal(d, storage.DigestOf(value)) {
+		t.Errorf("Digest() got %x, %v, want %x", d, err, storage.DigestOf(value))
+	}
+
+	lock.Lock()
+	corrupt = true
+	lock.Unlock()
+	if err := fe.GetStream(ctx, 1, ioutil.Discard); err != errDigestMismatch {
+		t.Errorf("GetStream() of a changed value got error %v, want %v", err, errDigestMismatch)
+	}
+}
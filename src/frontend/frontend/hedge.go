@@ -0,0 +1,228 @@
+package frontend
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"storage"
+)
+
+// Read modes of the Frontend, see Config.ReadMode.
+//
+// Режимы чтения Frontend, см. Config.ReadMode.
+const (
+	// ReadAll requests all replicas at once.
+	// ReadAll -- запрашивать все реплики сразу.
+	ReadAll = "all"
+	// ReadHedged requests a quorum of replicas first and the rest of them
+	// only if an answer is late or does not agree.
+	// ReadHedged -- сначала запрашивать кворум реплик, а остальные только
+	// если ответ запаздывает или не совпадает.
+	ReadHedged = "hedged"
+)
+
+const (
+	// DefaultHedgePercentile is a percentile of observed latencies of nodes
+	// used as a hedging delay if Config.HedgeDelay is not set.
+	//
+	// DefaultHedgePercentile -- процентиль наблюдаемых задержек node,
+	// используемый как задержка перед дополнительным запросом, если
+	// Config.HedgeDelay не задан.
+	DefaultHedgePercentile = 95
+	// DefaultHedgeDelay is a hedging delay used until latencies of nodes
+	// are observed.
+	//
+	// DefaultHedgeDelay -- задержка перед дополнительным запросом,
+	// используемая, пока задержки node не известны.
+	DefaultHedgeDelay = 10 * time.Millisecond
+)
+
+// latencySamples is a number of the last latencies remembered for a node.
+const latencySamples = 128
+
+// HedgeStats counts reads made in the ReadHedged mode.
+//
+// HedgeStats -- счетчики чтений в режиме ReadHedged.
+type HedgeStats struct {
+	// Reads is a number of hedged reads.
+	// Reads -- количество чтений.
+	Reads uint64
+	// Hedged is a number of reads which requested a spare replica
+	// because an answer was late.
+	// Hedged -- количество чтений, запросивших запасную реплику
+	// из-за запоздавшего ответа.
+	Hedged uint64
+	// Fallbacks is a number of reads which requested a spare replica
+	// because an answer failed or did not agree.
+	// Fallbacks -- количество чтений, запросивших запасную реплику
+	// из-за ошибки или несовпадения ответов.
+	Fallbacks uint64
+}
+
+// hedging holds counters of hedged reads and latencies of nodes.
+type hedging struct {
+	stats HedgeStats
+
+	mu        sync.Mutex
+	latencies map[storage.ServiceAddr]*latencyRing
+}
+
+type latencyRing struct {
+	samples []time.Duration
+	next    int
+}
+
+func newHedging() *hedging {
+	return &hedging{latencies: make(map[storage.ServiceAddr]*latencyRing)}
+}
+
+// record remembers a latency d of a request to node.
+func (h *hedging) record(node storage.ServiceAddr, d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r, ok := h.latencies[node]
+	if !ok {
+		r = &latencyRing{samples: make([]time.Duration, 0, latencySamples)}
+		h.latencies[node] = r
+	}
+	if len(r.samples) < cap(r.samples) {
+		r.samples = append(r.samples, d)
+		return
+	}
+	r.samples[r.next] = d
+	r.next = (r.next + 1) % len(r.samples)
+}
+
+// delay returns the largest percentile p of latencies of nodes,
+// DefaultHedgeDelay if latencies of some of them are not known.
+func (h *hedging) delay(nodes []storage.ServiceAddr, p float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var max time.Duration
+	for _, node := range nodes {
+		r, ok := h.latencies[node]
+		if !ok {
+			return DefaultHedgeDelay
+		}
+		sorted := append([]time.Duration(nil), r.samples...)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i] < sorted[j]
+		})
+		if d := sorted[int(float64(len(sorted)-1)*p/100)]; d > max {
+			max = d
+		}
+	}
+	return max
+}
+
+// HedgeStats returns counters of reads made in the ReadHedged mode.
+//
+// HedgeStats возвращает счетчики чтений в режиме ReadHedged.
+func (fe *Frontend) HedgeStats() HedgeStats {
+	return HedgeStats{
+		Reads:     atomic.LoadUint64(&fe.hedging.stats.Reads),
+		Hedged:    atomic.LoadUint64(&fe.hedging.stats.Hedged),
+		Fallbacks: atomic.LoadUint64(&fe.hedging.stats.Fallbacks),
+	}
+}
+
+// hedgeDelay returns a delay after which a spare replica is requested
+// if nodes have not answered.
+func (fe *Frontend) hedgeDelay(cfg Config, nodes []storage.ServiceAddr) time.Duration {
+	if cfg.HedgeDelay > 0 {
+		return cfg.HedgeDelay
+	}
+	p := cfg.HedgePercentile
+	if p <= 0 || p > 100 {
+		p = DefaultHedgePercentile
+	}
+	return fe.hedging.delay(nodes, p)
+}
+
+// getNode gets the record k from node remembering the latency. Latencies
+// of cancelled requests are not remembered, since they end when the quorum
+// is reached rather than when the node answers.
+func (fe *Frontend) getNode(ctx context.Context, nc storage.ContextClient, node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+	start := time.Now()
+	data, err := nc.GetContext(ctx, node, k)
+	if ctx.Err() == nil && err != context.Canceled {
+		fe.hedging.record(node, time.Since(start))
+	}
+	return data, err
+}
+
+// getHedged gets the record k from a quorum of nodes first. The rest
+// of nodes are requested one by one when the hedging delay passes or
+// the quorum cannot be reached without them.
+func (fe *Frontend) getHedged(ctx context.Context, cfg Config, nc storage.ContextClient, k storage.RecordID, nodes []storage.ServiceAddr) ([]byte, error) {
+	atomic.AddUint64(&fe.hedging.stats.Reads, 1)
+	dataMap := make(map[string]int)
+	errorMap := make(map[error]int)
+
+	type result struct {
+		data []byte
+		err  error
+	}
+
+	resChan := make(chan result, len(nodes))
+	sent := 0
+	send := func() {
+		go func(node storage.ServiceAddr) {
+			data, err := fe.getNode(ctx, nc, node, k)
+			resChan <- result{data, err}
+		}(nodes[sent])
+		sent++
+	}
+	for sent < storage.MinRedundancy {
+		send()
+	}
+	timer := time.NewTimer(fe.hedgeDelay(cfg, nodes[:sent]))
+	defer timer.Stop()
+
+	for received := 0; received < sent; {
+		select {
+		case <-timer.C:
+			if sent < len(nodes) {
+				atomic.AddUint64(&fe.hedging.stats.Hedged, 1)
+				send()
+			}
+			continue
+		case res := <-resChan:
+			received++
+			agree := 0
+			if res.err == nil {
+				dataMap[string(res.data)]++
+				agree = dataMap[string(res.data)]
+				if agree >= storage.MinRedundancy {
+					return res.data, nil
+				}
+			} else {
+				errorMap[res.err]++
+				agree = errorMap[res.err]
+				if agree >= storage.MinRedundancy {
+					return nil, res.err
+				}
+			}
+			for _, n := range dataMap {
+				if n > agree {
+					agree = n
+				}
+			}
+			for _, n := range errorMap {
+				if n > agree {
+					agree = n
+				}
+			}
+			if sent-received < storage.MinRedundancy-agree && sent < len(nodes) {
+				atomic.AddUint64(&fe.hedging.stats.Fallbacks, 1)
+				for sent-received < storage.MinRedundancy-agree && sent < len(nodes) {
+					send()
+				}
+			}
+		}
+	}
+	return nil, storage.ErrQuorumNotReached
+}
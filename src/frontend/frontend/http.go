@@ -0,0 +1,225 @@
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"storage"
+)
+
+// HTTPKeysPath is the path of records served by NewHTTPHandler,
+// a record is at HTTPKeysPath followed by its key.
+//
+// HTTPKeysPath -- путь записей, обслуживаемых NewHTTPHandler,
+// запись находится по пути HTTPKeysPath, за которым следует ее ключ.
+const HTTPKeysPath = "/v1/keys/"
+
+// HTTPHealthPath is the path of the health check served by NewHTTPHandler.
+//
+// HTTPHealthPath -- путь проверки состояния, обслуживаемой NewHTTPHandler.
+const HTTPHealthPath = "/v1/health"
+
+var errMethodNotAllowed = errors.New("Method not allowed")
+
+var errKeyMismatch = errors.New("Key of the record differs from the key in the path")
+
+// httpMaxJSONLen is the largest JSON body of a request. JSON values
+// are put to nodes in one gRPC message, which is limited to 4 MiB
+// by default, and base64 makes them a third larger.
+const httpMaxJSONLen = 6 << 20
+
+// httpHealthTimeout is a time the health check waits for the topology.
+const httpHealthTimeout = time.Second
+
+// jsonType is the media type of JSON-wrapped records.
+const jsonType = "application/json"
+
+// httpRecord is a JSON-wrapped record, its value is encoded in base64.
+type httpRecord struct {
+	Key   storage.RecordID `json:"key"`
+	Value []byte           `json:"value"`
+}
+
+// httpPutRecord is a JSON-wrapped record of a PUT request, its key
+// may be omitted.
+type httpPutRecord struct {
+	Key   *storage.RecordID `json:"key"`
+	Value []byte            `json:"value"`
+}
+
+// httpError is a JSON body of an error reply.
+type httpError struct {
+	Error string `json:"error"`
+}
+
+// httpHealth is a JSON body of a health check reply.
+type httpHealth struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Epoch  uint64 `json:"epoch"`
+	Nodes  int    `json:"nodes"`
+}
+
+// httpStatus returns the HTTP status code reporting err.
+func httpStatus(err error) int {
+	switch err {
+	case nil:
+		return http.StatusOK
+	case storage.ErrRecordNotFound:
+		return http.StatusNotFound
+	case storage.ErrRecordExists:
+		return http.StatusConflict
+	case storage.ErrQuorumNotReached, storage.ErrNotEnoughDaemons, context.Canceled:
+		return http.StatusServiceUnavailable
+	case context.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", jsonType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to write a reply: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, httpError{Error: err.Error()})
+}
+
+// isJSON reports whether the media type t is JSON.
+func isJSON(t string) bool {
+	mt, _, err := mime.ParseMediaType(t)
+	return err == nil && mt == jsonType
+}
+
+// acceptsJSON reports whether r asks for a JSON reply.
+func acceptsJSON(r *http.Request) bool {
+	for _, t := range strings.Split(r.Header.Get("Accept"), ",") {
+		if isJSON(strings.TrimSpace(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+type httpHandler struct {
+	fe *Frontend
+}
+
+// NewHTTPHandler returns a handler serving a REST API over fe:
+//
+//	GET    /v1/keys/{key} gets a record
+//	PUT    /v1/keys/{key} puts a record
+//	DELETE /v1/keys/{key} deletes a record
+//	GET    /v1/health     reports whether fe knows the topology
+//
+// Values are raw request and reply bodies unless their type is
+// application/json, then they are JSON objects {"key": 1, "value": "..."}
+// with values in base64. Raw values are streamed, JSON ones are limited
+// by the size of a gRPC message, and the key of a JSON record, if given,
+// must be the key in the path. Errors are replied as JSON objects
+// {"error": "..."} with status codes matching storage errors, such as 404
+// for storage.ErrRecordNotFound and 409 for storage.ErrRecordExists.
+//
+// NewHTTPHandler возвращает обработчик, обслуживающий REST API над fe.
+// Значения передаются в телах запросов и ответов как есть, если их тип
+// не application/json, иначе -- в объектах JSON {"key": 1, "value": "..."}
+// со значениями в base64. Значения как есть передаются потоком, значения
+// в JSON ограничены размером сообщения gRPC, а ключ записи в JSON, если
+// он задан, должен совпадать с ключом в пути. Ошибки возвращаются
+// в объектах JSON {"error": "..."} с кодами, соответствующими ошибкам
+// storage, например, 404 для storage.ErrRecordNotFound и 409 для
+// storage.ErrRecordExists.
+func NewHTTPHandler(fe *Frontend) http.Handler {
+	h := httpHandler{fe: fe}
+	mux := http.NewServeMux()
+	mux.HandleFunc(HTTPKeysPath, h.keys)
+	mux.HandleFunc(HTTPHealthPath, h.health)
+	return mux
+}
+
+func (h httpHandler) keys(w http.ResponseWriter, r *http.Request) {
+	key, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, HTTPKeysPath), 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	k := storage.RecordID(key)
+	log.Printf("HTTP %v request: key = %v", r.Method, k)
+
+	ctx := r.Context()
+	switch r.Method {
+	case http.MethodGet:
+		if acceptsJSON(r) {
+			data, err := h.fe.GetContext(ctx, k)
+			if err != nil {
+				writeError(w, httpStatus(err), err)
+				return
+			}
+			writeJSON(w, http.StatusOK, httpRecord{Key: k, Value: data})
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		cw := &countWriter{w: w}
+		if err := h.fe.GetStream(ctx, k, cw); err != nil {
+			if cw.n > 0 {
+				// the status is sent, only a cut reply tells about the error
+				log.Printf("HTTP GET of %v failed after %d bytes: %v", k, cw.n, err)
+				panic(http.ErrAbortHandler)
+			}
+			writeError(w, httpStatus(err), err)
+		}
+	case http.MethodPut:
+		if isJSON(r.Header.Get("Content-Type")) {
+			var rec httpPutRecord
+			body := http.MaxBytesReader(w, r.Body, httpMaxJSONLen)
+			if err := json.NewDecoder(body).Decode(&rec); err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			if rec.Key != nil && *rec.Key != k {
+				writeError(w, http.StatusBadRequest, errKeyMismatch)
+				return
+			}
+			err = h.fe.PutContext(ctx, k, rec.Value)
+		} else {
+			err = h.fe.PutStream(ctx, k, r.Body)
+		}
+		if err != nil {
+			writeError(w, httpStatus(err), err)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		if err := h.fe.DelContext(ctx, k); err != nil {
+			writeError(w, httpStatus(err), err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		writeError(w, http.StatusMethodNotAllowed, errMethodNotAllowed)
+	}
+}
+
+func (h httpHandler) health(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), httpHealthTimeout)
+	defer cancel()
+	t, err := h.fe.currentTopology(ctx)
+	if err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, httpHealth{Status: "unavailable", Error: "Topology is not known"})
+		return
+	}
+	writeJSON(w, http.StatusOK, httpHealth{Status: "ok", Epoch: t.Epoch, Nodes: len(t.Nodes)})
+}
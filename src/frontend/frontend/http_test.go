@@ -0,0 +1,171 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"router/router"
+	"storage"
+)
+
+// newMemoryNode returns a MockNode keeping records of all nodes in memory.
+func newMemoryNode() *MockNode {
+	var lock sync.Mutex
+	records := make(map[storage.ServiceAddr]map[storage.RecordID][]byte)
+	return &MockNode{
+		put: func(node storage.ServiceAddr, k storage.RecordID, d []byte) error {
+			lock.Lock()
+			defer lock.Unlock()
+			if records[node] == nil {
+				records[node] = make(map[storage.RecordID][]byte)
+			}
+			if _, ok := records[node][k]; ok {
+				return storage.ErrRecordExists
+			}
+			records[node][k] = d
+			return nil
+		},
+		get: func(node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			d, ok := records[node][k]
+			if !ok {
+				return nil, storage.ErrRecordNotFound
+			}
+			return d, nil
+		},
+		del: func(node storage.ServiceAddr, k storage.RecordID) error {
+			lock.Lock()
+			defer lock.Unlock()
+			if _, ok := records[node][k]; !ok {
+				return storage.ErrRecordNotFound
+			}
+			delete(records[node], k)
+			return nil
+		},
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3"}
+	rc := &MockTopologyLister{topology: router.Topology{Nodes: nodes, Epoch: 7}}
+	rc.nodesFind = func(rtr storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, error) {
+		return nodes, nil
+	}
+	fe := New(Config{
+		RC:     rc,
+		NC:     newMemoryNode(),
+		NF:     router.NewNodesFinder(router.NewMD5Hasher()),
+		Router: "router",
+	})
+	srv := httptest.NewServer(NewHTTPHandler(fe))
+	defer srv.Close()
+
+	// do sends a request and checks the status code of its reply
+	do := func(method, path, contentType, accept, body string, want int) []byte {
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("NewRequest() error: %v", err)
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%v %v error: %v", method, path, err)
+		}
+		defer resp.Body.Close()
+		got, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("%v %v: reading the body failed: %v", method, path, err)
+		}
+		if resp.StatusCode != want {
+			t.Errorf("%v %v got status %v (%s), want %v", method, path, resp.StatusCode, got, want)
+		}
+		return got
+	}
+
+	var health httpHealth
+	if err := json.Unmarshal(do("GET", HTTPHealthPath, "", "", "", http.StatusOK), &health); err != nil || health.Status != "ok" || health.Epoch != 7 || health.Nodes != 3 {
+		t.Errorf("Health check got %+v, %v", health, err)
+	}
+
+	var e httpError
+	if err := json.Unmarshal(do("GET", "/v1/keys/1", "", "", "", http.StatusNotFound), &e); err != nil || e.Error != storage.ErrRecordNotFound.Error() {
+		t.Errorf("GET of a missing record got %+v, %v", e, err)
+	}
+	do("PUT", "/v1/keys/1", "application/octet-stream", "", "raw value", http.StatusCreated)
+	do("PUT", "/v1/keys/1", "", "", "raw value", http.StatusConflict)
+	if got := do("GET", "/v1/keys/1", "", "", "", http.StatusOK); string(got) != "raw value" {
+		t.Errorf("GET got %q, want \"raw value\"", got)
+	}
+	var rec httpRecord
+	if err := json.Unmarshal(do("GET", "/v1/keys/1", "", "text/html, application/json; q=0.9", "", http.StatusOK), &rec); err != nil || rec.Key != 1 || string(rec.Value) != "raw value" {
+		t.Errorf("GET of JSON got %+v, %v", rec, err)
+	}
+
+	body, _ := json.Marshal(httpRecord{Key: 2, Value: []byte("json value")})
+	do("PUT", "/v1/keys/2", "application/json; charset=utf-8", "", string(body), http.StatusCreated)
+	if got := do("GET", "/v1/keys/2", "", "", "", http.StatusOK); string(got) != "json value" {
+		t.Errorf("GET of a record put in JSON got %q, want \"json value\"", got)
+	}
+	do("PUT", "/v1/keys/3", "application/json", "", "{", http.StatusBadRequest)
+	body, _ = json.Marshal(httpRecord{Key: 4, Value: []byte("json value")})
+	do("PUT", "/v1/keys/3", "application/json", "", string(body), http.StatusBadRequest)
+	do("PUT", "/v1/keys/4", "application/json", "", string(body), http.StatusCreated)
+	do("PUT", "/v1/keys/5", "application/json", "", `{"value": "anNvbg=="}`, http.StatusCreated)
+	big := `{"value": "` + strings.Repeat("A", httpMaxJSONLen) + `"}`
+	do("PUT", "/v1/keys/3", "application/json", "", big, http.StatusBadRequest)
+	do("GET", "/v1/keys/3", "", "", "", http.StatusNotFound)
+
+	do("DELETE", "/v1/keys/1", "", "", "", http.StatusNoContent)
+	do("DELETE", "/v1/keys/1", "", "", "", http.StatusNotFound)
+	do("GET", "/v1/keys/1", "", "", "", http.StatusNotFound)
+
+	do("GET", "/v1/keys/abc", "", "", "", http.StatusBadRequest)
+	do("GET", "/v1/keys/4294967296", "", "", "", http.StatusBadRequest)
+	do("POST", "/v1/keys/1", "", "", "", http.StatusMethodNotAllowed)
+	do("GET", "/v1/other", "", "", "", http.StatusNotFound)
+}
+
+func TestHTTPStatus(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want int
+	}{
+		{nil, http.StatusOK},
+		{storage.ErrRecordNotFound, http.StatusNotFound},
+		{storage.ErrRecordExists, http.StatusConflict},
+		{storage.ErrQuorumNotReached, http.StatusServiceUnavailable},
+		{storage.ErrNotEnoughDaemons, http.StatusServiceUnavailable},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{errors.New("node is down"), http.StatusInternalServerError},
+	} {
+		if got := httpStatus(tc.err); got != tc.want {
+			t.Errorf("httpStatus(%v) got %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestHTTPHandler_Unavailable(t *testing.T) {
+	rc := &MockRouter{list: func(router storage.ServiceAddr) ([]storage.ServiceAddr, error) {
+		return nil, errors.New("router is down")
+	}}
+	fe := New(Config{RC: rc, NC: newMemoryNode(), Router: "router"})
+	rec := httptest.NewRecorder()
+	NewHTTPHandler(fe).ServeHTTP(rec, httptest.NewRequest("GET", HTTPHealthPath, nil))
+	if rec.Code != http.StatusServiceUnavailable || !bytes.Contains(rec.Body.Bytes(), []byte("unavailable")) {
+		t.Errorf("Health check without a topology got %v %q, want %v", rec.Code, rec.Body.String(), http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,91 @@
+package frontend
+
+import (
+	"log"
+	"time"
+
+	rclient "router/client"
+	"router/router"
+	"storage"
+)
+
+// DefaultLivenessInterval is used if Config.LivenessInterval is 0.
+//
+// DefaultLivenessInterval используется, если Config.LivenessInterval равен 0.
+const DefaultLivenessInterval = 250 * time.Millisecond
+
+// aliveNodes is a snapshot of nodes alive at some topology epoch.
+type aliveNodes struct {
+	epoch uint64
+	nodes map[storage.ServiceAddr]bool
+	at    time.Time
+}
+
+func livenessInterval(cfg Config) time.Duration {
+	if cfg.LivenessInterval == 0 {
+		return DefaultLivenessInterval
+	}
+	return cfg.LivenessInterval
+}
+
+// pollAlive fetches alive nodes from the Router each liveness interval.
+func (fe *Frontend) pollAlive() {
+	for {
+		cfg := fe.config()
+		interval := livenessInterval(cfg)
+		if interval < 0 {
+			time.Sleep(DefaultLivenessInterval)
+			continue
+		}
+		if l, ok := cfg.RC.(rclient.AliveLister); ok {
+			nodes, epoch, err := l.ListAlive(cfg.Router)
+			if err == nil {
+				alive := aliveNodes{epoch: epoch, nodes: make(map[storage.ServiceAddr]bool, len(nodes)), at: time.Now()}
+				for _, node := range nodes {
+					alive.nodes[node] = true
+				}
+				fe.alive.Store(alive)
+				if t, ok := fe.topology.Load().(router.Topology); ok && t.Epoch < epoch {
+					log.Printf("Topology epoch %v is stale, router has %v", t.Epoch, epoch)
+					fe.restartWatch()
+				}
+			}
+		}
+		time.Sleep(interval)
+	}
+}
+
+// invalidateAlive makes writes ask the Router for nodes until alive nodes
+// are fetched anew.
+func (fe *Frontend) invalidateAlive() {
+	fe.alive.Store(aliveNodes{})
+}
+
+// cachedNodes returns alive writable nodes for the key k computed from
+// the cached topology. It reports false if the cache is stale or too few
+// nodes are alive, so that the Router decides.
+func (fe *Frontend) cachedNodes(cfg Config, k storage.RecordID) ([]storage.ServiceAddr, bool) {
+	interval := livenessInterval(cfg)
+	if interval < 0 {
+		return nil, false
+	}
+	alive, ok := fe.alive.Load().(aliveNodes)
+	if !ok || time.Since(alive.at) > 2*interval {
+		return nil, false
+	}
+	t, ok := fe.topology.Load().(router.Topology)
+	if !ok || t.Epoch != alive.epoch {
+		return nil, false
+	}
+	nodes := cfg.NF.NodesFindServing(k, t)
+	ret := make([]storage.ServiceAddr, 0, len(nodes))
+	for _, node := range nodes {
+		if alive.nodes[node] && t.States[node].Writable() {
+			ret = append(ret, node)
+		}
+	}
+	if len(ret) < storage.MinRedundancy {
+		return nil, false
+	}
+	return ret, true
+}
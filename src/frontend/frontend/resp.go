@@ -0,0 +1,326 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"frontend/resp"
+	"router/router"
+	"storage"
+)
+
+// RESPServer serves a subset of Redis commands over a Frontend for
+// numeric keys of records:
+//
+//	GET key
+//	SET key value [NX]
+//	DEL key [key ...]
+//	EXISTS key [key ...]
+//	MGET key [key ...]
+//	PING [message]
+//	INFO [section]
+//	QUIT
+//
+// Records cannot be overwritten, so SET of an existing record replies
+// with an error, or with a null reply if NX is given, as Redis does.
+// Other commands are replied with errors.
+//
+// RESPServer обслуживает подмножество команд Redis над Frontend для
+// числовых ключей записей. Записи нельзя перезаписать, поэтому SET
+// существующей записи возвращает ошибку или, если задан NX, пустой
+// (null) ответ, как в Redis. На остальные команды возвращаются ошибки.
+type RESPServer struct {
+	fe   *Frontend
+	addr string
+
+	lock    sync.Mutex
+	l       net.Listener
+	conns   map[net.Conn]bool
+	stopped bool
+}
+
+// NewRESPServer creates a RESPServer for fe listening at addr.
+//
+// NewRESPServer создает RESPServer для fe, слушающий адрес addr.
+func NewRESPServer(fe *Frontend, addr storage.ServiceAddr) *RESPServer {
+	return &RESPServer{
+		fe:    fe,
+		addr:  string(addr),
+		conns: make(map[net.Conn]bool),
+	}
+}
+
+// ListenAndServe listens at the address of s and serves connections
+// until Stop.
+//
+// ListenAndServe слушает адрес s и обслуживает соединения до вызова Stop.
+func (s *RESPServer) ListenAndServe() error {
+	l, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("Failed to listen: %v", err)
+	}
+	log.Printf("Starting RESP service at %v", s.addr)
+	return s.Serve(l)
+}
+
+// Serve serves connections accepted by l until Stop.
+//
+// Serve обслуживает соединения, принятые l, до вызова Stop.
+func (s *RESPServer) Serve(l net.Listener) error {
+	s.lock.Lock()
+	if s.stopped {
+		s.lock.Unlock()
+		return l.Close()
+	}
+	s.l = l
+	s.lock.Unlock()
+	for {
+		conn, err := l.Accept()
+		s.lock.Lock()
+		if s.stopped {
+			s.lock.Unlock()
+			if conn != nil {
+				conn.Close()
+			}
+			return nil
+		}
+		if err != nil {
+			s.lock.Unlock()
+			return err
+		}
+		s.conns[conn] = true
+		s.lock.Unlock()
+		go s.serveConn(conn)
+	}
+}
+
+// Stop closes the listener and all connections.
+//
+// Stop закрывает слушающий сокет и все соединения.
+func (s *RESPServer) Stop() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.stopped = true
+	if s.l != nil {
+		s.l.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *RESPServer) serveConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.lock.Lock()
+		delete(s.conns, conn)
+		s.lock.Unlock()
+	}()
+	r := resp.NewReader(conn)
+	w := resp.NewWriter(conn)
+	for {
+		args, err := r.ReadCommand()
+		if err == resp.ErrProtocol {
+			w.WriteError("ERR Protocol error")
+			w.Flush()
+			return
+		}
+		if err != nil {
+			return
+		}
+		quit := s.command(context.Background(), w, args)
+		// replies to pipelined commands are flushed together
+		if r.Buffered() == 0 || quit {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+		if quit {
+			return
+		}
+	}
+}
+
+// respError returns the RESP error reporting err.
+func respError(err error) string {
+	switch err {
+	case storage.ErrQuorumNotReached, storage.ErrNotEnoughDaemons:
+		return "TRYAGAIN " + err.Error()
+	default:
+		return "ERR " + err.Error()
+	}
+}
+
+// respKeys parses args as keys of records.
+func respKeys(args [][]byte) ([]storage.RecordID, error) {
+	keys := make([]storage.RecordID, 0, len(args))
+	for _, arg := range args {
+		k, err := strconv.ParseUint(string(arg), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("ERR key %q is not a uint32 number", arg)
+		}
+		keys = append(keys, storage.RecordID(k))
+	}
+	return keys, nil
+}
+
+// respArity holds the least and the most numbers of arguments of
+// commands, -1 is any number.
+var respArity = map[string][2]int{
+	"PING":   {0, 1},
+	"QUIT":   {0, 0},
+	"GET":    {1, 1},
+	"SET":    {2, -1},
+	"DEL":    {1, -1},
+	"EXISTS": {1, -1},
+	"MGET":   {1, -1},
+	"INFO":   {0, 1},
+}
+
+// command runs a command and writes its reply to w. Returns whether
+// the connection should be closed.
+func (s *RESPServer) command(ctx context.Context, w *resp.Writer, args [][]byte) bool {
+	name := strings.ToUpper(string(args[0]))
+	args = args[1:]
+	arity, ok := respArity[name]
+	if !ok {
+		w.WriteError(fmt.Sprintf("ERR unknown command '%s'", strings.ToLower(name)))
+		return false
+	}
+	if len(args) < arity[0] || (arity[1] >= 0 && len(args) > arity[1]) {
+		w.WriteError(fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(name)))
+		return false
+	}
+
+	var keyArgs [][]byte
+	switch name {
+	case "GET", "SET":
+		keyArgs = args[:1]
+	case "DEL", "EXISTS", "MGET":
+		keyArgs = args
+	}
+	keys, err := respKeys(keyArgs)
+	if err != nil {
+		w.WriteError(err.Error())
+		return false
+	}
+
+	switch name {
+	case "PING":
+		if len(args) == 1 {
+			w.WriteBulk(args[0])
+			break
+		}
+		w.WriteSimple("PONG")
+	case "QUIT":
+		w.WriteSimple("OK")
+		return true
+	case "GET":
+		data, err := s.fe.GetContext(ctx, keys[0])
+		switch {
+		case err == storage.ErrRecordNotFound:
+			w.WriteBulk(nil)
+		case err != nil:
+			w.WriteError(respError(err))
+		default:
+			if data == nil {
+				data = []byte{}
+			}
+			w.WriteBulk(data)
+		}
+	case "SET":
+		nx := false
+		for _, opt := range args[2:] {
+			if !strings.EqualFold(string(opt), "NX") {
+				w.WriteError(fmt.Sprintf("ERR SET option %q is not supported", opt))
+				return false
+			}
+			nx = true
+		}
+		err := s.fe.PutContext(ctx, keys[0], args[1])
+		switch {
+		case err == nil:
+			w.WriteSimple("OK")
+		case err == storage.ErrRecordExists && nx:
+			w.WriteBulk(nil)
+		default:
+			w.WriteError(respError(err))
+		}
+	case "DEL":
+		n := 0
+		for _, res := range s.fe.MultiDel(ctx, keys) {
+			switch res.Err {
+			case nil:
+				n++
+			case storage.ErrRecordNotFound:
+			default:
+				w.WriteError(respError(res.Err))
+				return false
+			}
+		}
+		w.WriteInt(int64(n))
+	case "EXISTS", "MGET":
+		results := s.fe.MultiGet(ctx, keys)
+		for _, res := range results {
+			if res.Err != nil && res.Err != storage.ErrRecordNotFound {
+				w.WriteError(respError(res.Err))
+				return false
+			}
+		}
+		if name == "EXISTS" {
+			n := 0
+			for _, res := range results {
+				if res.Err == nil {
+					n++
+				}
+			}
+			w.WriteInt(int64(n))
+			break
+		}
+		w.WriteArray(len(results))
+		for _, res := range results {
+			switch {
+			case res.Err != nil:
+				w.WriteBulk(nil)
+			case res.Data == nil:
+				w.WriteBulk([]byte{})
+			default:
+				w.WriteBulk(res.Data)
+			}
+		}
+	case "INFO":
+		w.WriteBulk(s.info())
+	}
+	return false
+}
+
+// info returns the reply to INFO in the format of Redis.
+func (s *RESPServer) info() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Server\r\n")
+	fmt.Fprintf(&b, "version:%v\r\n", storage.Version)
+	fmt.Fprintf(&b, "role:frontend\r\n")
+	if t, ok := s.fe.topology.Load().(router.Topology); ok {
+		fmt.Fprintf(&b, "\r\n# Topology\r\n")
+		fmt.Fprintf(&b, "epoch:%v\r\n", t.Epoch)
+		fmt.Fprintf(&b, "nodes:%v\r\n", len(t.Nodes))
+	}
+	cs := s.fe.CacheStats()
+	fmt.Fprintf(&b, "\r\n# Cache\r\n")
+	fmt.Fprintf(&b, "cache_hits:%v\r\n", cs.Hits)
+	fmt.Fprintf(&b, "cache_misses:%v\r\n", cs.Misses)
+	fmt.Fprintf(&b, "cache_evictions:%v\r\n", cs.Evictions)
+	fmt.Fprintf(&b, "cache_bytes:%v\r\n", cs.Bytes)
+	hs := s.fe.HedgeStats()
+	fmt.Fprintf(&b, "\r\n# Reads\r\n")
+	fmt.Fprintf(&b, "hedged_reads:%v\r\n", hs.Reads)
+	fmt.Fprintf(&b, "hedged:%v\r\n", hs.Hedged)
+	fmt.Fprintf(&b, "hedge_fallbacks:%v\r\n", hs.Fallbacks)
+	return b.Bytes()
+}
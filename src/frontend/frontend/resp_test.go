@@ -0,0 +1,138 @@
+package frontend
+
+import (
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"frontend/resp"
+	"router/router"
+	"storage"
+)
+
+func TestRESPServer(t *testing.T) {
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3"}
+	rc := &MockTopologyLister{topology: router.Topology{Nodes: nodes, Epoch: 3}}
+	rc.nodesFind = func(rtr storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, error) {
+		return nodes, nil
+	}
+	fe := New(Config{
+		RC:     rc,
+		NC:     newMemoryNode(),
+		NF:     router.NewNodesFinder(router.NewMD5Hasher()),
+		Router: "router",
+	})
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error: %v", err)
+	}
+	srv := NewRESPServer(fe, "")
+	go srv.Serve(l)
+	defer srv.Stop()
+
+	c, err := resp.Dial(l.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer c.Close()
+
+	bulk := func(s string) resp.Value { return resp.Value{Type: resp.BulkString, Bulk: []byte(s)} }
+	null := resp.Value{Type: resp.BulkString, Null: true}
+	ok := resp.Value{Type: resp.SimpleString, Str: "OK"}
+	integer := func(n int64) resp.Value { return resp.Value{Type: resp.Integer, Int: n} }
+	respErr := func(s string) resp.Value { return resp.Value{Type: resp.Error, Str: s} }
+
+	for _, tc := range []struct {
+		args []string
+		want resp.Value
+	}{
+		{[]string{"PING"}, resp.Value{Type: resp.SimpleString, Str: "PONG"}},
+		{[]string{"ping", "hello"}, bulk("hello")},
+		{[]string{"GET", "1"}, null},
+		{[]string{"SET", "1", "one"}, ok},
+		{[]string{"SET", "1", "uno"}, respErr("ERR " + storage.ErrRecordExists.Error())},
+		{[]string{"SET", "1", "uno", "nx"}, null},
+		{[]string{"SET", "2", "two", "NX"}, ok},
+		{[]string{"SET", "3", ""}, ok},
+		{[]string{"SET", "4", "four", "EX", "10"}, respErr(`ERR SET option "EX" is not supported`)},
+		{[]string{"GET", "1"}, bulk("one")},
+		{[]string{"GET", "3"}, bulk("")},
+		{[]string{"MGET", "1", "4", "2"}, resp.Value{Type: resp.Array, Array: []resp.Value{bulk("one"), null, bulk("two")}}},
+		{[]string{"EXISTS", "1", "1", "4"}, integer(2)},
+		{[]string{"DEL", "1", "4", "2"}, integer(2)},
+		{[]string{"EXISTS", "1", "2"}, integer(0)},
+		{[]string{"GET", "key"}, respErr(`ERR key "key" is not a uint32 number`)},
+		{[]string{"GET", "4294967296"}, respErr(`ERR key "4294967296" is not a uint32 number`)},
+		{[]string{"GET"}, respErr("ERR wrong number of arguments for 'get' command")},
+		{[]string{"GET", "1", "2"}, respErr("ERR wrong number of arguments for 'get' command")},
+		{[]string{"FLUSHALL"}, respErr("ERR unknown command 'flushall'")},
+	} {
+		got, err := c.Do(tc.args...)
+		if err != nil {
+			t.Fatalf("Do(%q) error: %v", tc.args, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Do(%q) got %+v, want %+v", tc.args, got, tc.want)
+		}
+	}
+
+	info, err := c.Do("INFO")
+	if err != nil || info.Type != resp.BulkString || !strings.Contains(string(info.Bulk), "epoch:3\r\n") {
+		t.Errorf("INFO got %q, %v", info.Bulk, err)
+	}
+
+	// pipelined commands are answered in order
+	for _, k := range []string{"5", "6", "5"} {
+		if err := c.Send("SET", k, "v"+k, "NX"); err != nil {
+			t.Fatalf("Send() error: %v", err)
+		}
+	}
+	for _, want := range []resp.Value{ok, ok, null} {
+		if got, err := c.Receive(); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("Receive() got %+v, %v, want %+v", got, err, want)
+		}
+	}
+
+	if got, err := c.Do("QUIT"); err != nil || !reflect.DeepEqual(got, ok) {
+		t.Errorf("QUIT got %+v, %v, want %+v", got, err, ok)
+	}
+	if _, err := c.Do("PING"); err != io.EOF {
+		t.Errorf("PING after QUIT got error %v, want %v", err, io.EOF)
+	}
+}
+
+func TestRESPServer_Stop(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error: %v", err)
+	}
+	srv := NewRESPServer(New(Config{RC: new(MockRouter), NC: newMemoryNode()}), "")
+	done := make(chan error)
+	go func() {
+		done <- srv.Serve(l)
+	}()
+	c, err := resp.Dial(l.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer c.Close()
+	if _, err := c.Do("PING"); err != nil {
+		t.Fatalf("PING error: %v", err)
+	}
+
+	srv.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve() after Stop() got error %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Serve() did not return after Stop()")
+	}
+	if _, err := c.Do("PING"); err == nil {
+		t.Errorf("PING after Stop() succeeded")
+	}
+}
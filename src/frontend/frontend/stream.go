@@ -0,0 +1,221 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"storage"
+)
+
+var errDigestMismatch = errors.New("Streamed value does not match the digest of replicas")
+
+// streamClient returns nc as a storage.StreamClient. If nc cannot stream
+// values, they are sent and got whole.
+func streamClient(nc storage.Client) storage.StreamClient {
+	if c, ok := nc.(storage.StreamClient); ok {
+		return c
+	}
+	return wholeClient{contextClient(nc)}
+}
+
+type wholeClient struct {
+	storage.ContextClient
+}
+
+func (c wholeClient) PutStream(ctx context.Context, node storage.ServiceAddr, k storage.RecordID, r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return c.PutContext(ctx, node, k, data)
+}
+
+func (c wholeClient) GetStream(ctx context.Context, node storage.ServiceAddr, k storage.RecordID, w io.Writer) error {
+	data, err := c.GetContext(ctx, node, k)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (c wholeClient) Digest(ctx context.Context, node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+	data, err := c.GetContext(ctx, node, k)
+	if err != nil {
+		return nil, err
+	}
+	return storage.DigestOf(data), nil
+}
+
+// fanOutWriter writes to all of its writers which have not failed yet.
+type fanOutWriter []io.Writer
+
+func (w fanOutWriter) Write(p []byte) (int, error) {
+	for i, dst := range w {
+		if dst == nil {
+			continue
+		}
+		if _, err := dst.Write(p); err != nil {
+			w[i] = nil
+		}
+	}
+	return len(p), nil
+}
+
+// countWriter counts bytes written to w.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// PutStream puts the value read from r like Put. The value is streamed
+// to all nodes of the key at once without being held whole in memory.
+// Nothing is put if r fails.
+//
+// PutStream добавляет значение, прочитанное из r, так же, как Put.
+// Значение передается во все node ключа одновременно и не хранится
+// в памяти целиком. Если чтение r не удалось, ничего не добавляется.
+func (fe *Frontend) PutStream(ctx context.Context, k storage.RecordID, r io.Reader) error {
+	cfg := fe.config()
+	defer fe.invalidate(cfg, k)
+	nodes, err := fe.nodesFind(ctx, k)
+	if err != nil {
+		return err
+	}
+	if len(nodes) < storage.MinRedundancy {
+		return storage.ErrNotEnoughDaemons
+	}
+
+	nc := streamClient(cfg.NC)
+	pipes := make([]*io.PipeWriter, len(nodes))
+	w := make(fanOutWriter, len(nodes))
+	errs := make([]error, len(nodes))
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		pr, pw := io.Pipe()
+		pipes[i], w[i] = pw, pw
+		wg.Add(1)
+		go func(i int, node storage.ServiceAddr) {
+			defer wg.Done()
+			err := nc.PutStream(ctx, node, k, pr)
+			if err != nil && storage.ErrToStatus(err) == storage.StatusUnknown {
+				// the node may have died since alive nodes were fetched
+				fe.invalidateAlive()
+			}
+			errs[i] = err
+			// the rest of the value is not needed by the node anymore
+			pr.CloseWithError(io.ErrClosedPipe)
+		}(i, node)
+	}
+	_, err = io.CopyBuffer(w, r, make([]byte, storage.ChunkSize))
+	for _, pw := range pipes {
+		// nil err closes streams with io.EOF
+		pw.CloseWithError(err)
+	}
+	wg.Wait()
+	if err != nil {
+		return err
+	}
+	return writeQuorum(errs)
+}
+
+// digest returns the digest of the value of k a quorum of readable nodes
+// agree on and the nodes which reported it.
+func (fe *Frontend) digest(ctx context.Context, k storage.RecordID) ([]byte, []storage.ServiceAddr, error) {
+	t, err := fe.currentTopology(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg := fe.config()
+	nc := streamClient(cfg.NC)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		node   storage.ServiceAddr
+		digest []byte
+		err    error
+	}
+	var nodes []storage.ServiceAddr
+	for _, node := range cfg.NF.NodesFindTopology(k, t) {
+		if t.States[node].Readable() {
+			nodes = append(nodes, node)
+		}
+	}
+	ch := make(chan result, len(nodes))
+	for _, node := range nodes {
+		go func(node storage.ServiceAddr) {
+			digest, err := nc.Digest(ctx, node, k)
+			ch <- result{node, digest, err}
+		}(node)
+	}
+
+	digests := make(map[string][]storage.ServiceAddr)
+	errorMap := make(map[error]int)
+	for range nodes {
+		res := <-ch
+		if res.err != nil {
+			errorMap[res.err]++
+			if errorMap[res.err] >= storage.MinRedundancy {
+				return nil, nil, res.err
+			}
+			continue
+		}
+		matched := append(digests[string(res.digest)], res.node)
+		digests[string(res.digest)] = matched
+		if len(matched) >= storage.MinRedundancy {
+			return res.digest, matched, nil
+		}
+	}
+	return nil, nil, storage.ErrQuorumNotReached
+}
+
+// Digest returns the digest of the value for k a quorum of nodes agree on,
+// see storage.NewDigest.
+//
+// Digest возвращает дайджест значения для k, согласованный кворумом node,
+// см. storage.NewDigest.
+func (fe *Frontend) Digest(ctx context.Context, k storage.RecordID) ([]byte, error) {
+	digest, _, err := fe.digest(ctx, k)
+	return digest, err
+}
+
+// GetStream writes the value for k to w like Get. Nodes are compared by
+// digests of their values, then the value is streamed from one of the
+// nodes a quorum agree with and checked against the digest. Reads by
+// GetStream bypass the read cache.
+//
+// GetStream записывает значение для k в w так же, как Get. Node
+// сравниваются по дайджестам значений, затем значение передается из одной
+// из node, согласных с кворумом, и сверяется с дайджестом. Чтения
+// GetStream не используют кеш чтений.
+func (fe *Frontend) GetStream(ctx context.Context, k storage.RecordID, w io.Writer) error {
+	digest, nodes, err := fe.digest(ctx, k)
+	if err != nil {
+		return err
+	}
+	nc := streamClient(fe.config().NC)
+	for _, node := range nodes {
+		cw := &countWriter{w: w}
+		h := storage.NewDigest()
+		err = nc.GetStream(ctx, node, k, io.MultiWriter(cw, h))
+		if err == nil && !bytes.Equal(h.Sum(nil), digest) {
+			err = errDigestMismatch
+		}
+		if err == nil || cw.n > 0 {
+			// a partly written value cannot be retried
+			return err
+		}
+	}
+	return err
+}
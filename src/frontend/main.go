@@ -3,7 +3,9 @@ package main
 import (
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"time"
 
 	yaml "gopkg.in/yaml.v2"
 
@@ -34,13 +36,44 @@ func parseConfig(fname string) (cfg frontend.Config, err error) {
 	if cfg.Addr == "" {
 		return cfg, fmt.Errorf("Failed to parse config file %q: Addr should be set", fname)
 	}
+	if cfg.Router == "" && len(cfg.Routers) > 0 {
+		cfg.Router = cfg.Routers[0]
+	}
 	if cfg.Router == "" {
-		return cfg, fmt.Errorf("Failed to parse config file %q: Router should be set", fname)
+		return cfg, fmt.Errorf("Failed to parse config file %q: Router or Routers should be set", fname)
+	}
+	switch cfg.ReadMode {
+	case "", frontend.ReadAll, frontend.ReadHedged:
+	default:
+		return cfg, fmt.Errorf("Failed to parse config file %q: unknown read_mode %q", fname, cfg.ReadMode)
 	}
 
 	return cfg, nil
 }
 
+// statsInterval is an interval between reports of hedged reads
+// and of the read cache.
+const statsInterval = time.Minute
+
+// logStats periodically reports hedged reads and lookups of the read
+// cache made by fe, if any.
+func logStats(fe *frontend.Frontend) {
+	var (
+		lastHedge frontend.HedgeStats
+		lastCache frontend.CacheStats
+	)
+	for range time.Tick(statsInterval) {
+		if st := fe.HedgeStats(); st.Reads != lastHedge.Reads {
+			log.Printf("Hedged reads: %v, hedged %v, fallbacks %v", st.Reads-lastHedge.Reads, st.Hedged-lastHedge.Hedged, st.Fallbacks-lastHedge.Fallbacks)
+			lastHedge = st
+		}
+		if st := fe.CacheStats(); st.Hits+st.Misses != lastCache.Hits+lastCache.Misses {
+			log.Printf("Read cache: hits %v, misses %v, evictions %v, %v bytes", st.Hits-lastCache.Hits, st.Misses-lastCache.Misses, st.Evictions-lastCache.Evictions, st.Bytes)
+			lastCache = st
+		}
+	}
+}
+
 func main() {
 	if len(os.Args) != 2 {
 		usage()
@@ -53,12 +86,39 @@ func main() {
 	}
 
 	cfg.NC = storage.NewClient()
-	cfg.RC = rclient.New()
+	cfg.RC = rclient.New(cfg.Routers...)
 
-	hasher := router.NewMD5Hasher()
-	cfg.NF = router.NewNodesFinder(hasher)
+	cfg.NF, err = router.NewNodesFinderByName(cfg.Placement, cfg.Hasher)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	fe := frontend.New(cfg)
+	storage.WatchConfig(os.Args[1], func() {
+		cfg, err := parseConfig(os.Args[1])
+		if err != nil {
+			log.Printf("Config is not reloaded: %v", err)
+			return
+		}
+		cfg.RC = rclient.New(cfg.Routers...)
+		if err := fe.Reload(cfg); err != nil {
+			log.Printf("Config is reloaded partially: %v", err)
+			return
+		}
+		log.Printf("Config is reloaded")
+	})
+	go logStats(fe)
+	if cfg.HTTPAddr != "" {
+		go func() {
+			log.Printf("Starting REST API at %v", cfg.HTTPAddr)
+			log.Fatal(http.ListenAndServe(string(cfg.HTTPAddr), frontend.NewHTTPHandler(fe)))
+		}()
+	}
+	if cfg.RESPAddr != "" {
+		go func() {
+			log.Fatal(frontend.NewRESPServer(fe, cfg.RESPAddr).ListenAndServe())
+		}()
+	}
 	srv := storage.NewServer(fe, string(cfg.Addr))
 	if err := srv.ListenAndServe(); err != nil {
 		log.Fatal(err)
@@ -0,0 +1,74 @@
+package resp
+
+import (
+	"net"
+	"time"
+)
+
+// Client sends commands to a RESP server over a single connection.
+// It is not safe for concurrent use.
+//
+// Client посылает команды серверу RESP через одно соединение.
+// Client нельзя использовать из нескольких горутин одновременно.
+type Client struct {
+	conn net.Conn
+	r    *Reader
+	w    *Writer
+}
+
+// Dial connects to a RESP server at addr.
+//
+// Dial подключается к серверу RESP по адресу addr.
+func Dial(addr string, timeout time.Duration) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, r: NewReader(conn), w: NewWriter(conn)}, nil
+}
+
+// Send writes a command without waiting for its reply, so that commands
+// can be pipelined. Replies are read by Receive in the order of commands.
+//
+// Send записывает команду, не дожидаясь ответа, так что команды можно
+// посылать конвейером. Ответы читаются Receive в порядке команд.
+func (c *Client) Send(args ...string) error {
+	if err := c.w.WriteArray(len(args)); err != nil {
+		return err
+	}
+	for _, arg := range args {
+		if err := c.w.WriteBulk([]byte(arg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Receive flushes sent commands and reads a reply.
+//
+// Receive отправляет записанные команды и читает ответ.
+func (c *Client) Receive() (Value, error) {
+	if err := c.w.Flush(); err != nil {
+		return Value{}, err
+	}
+	return c.r.ReadValue()
+}
+
+// Do sends a command and reads its reply. Error replies are returned
+// as values, see Value.Err.
+//
+// Do посылает команду и читает ответ на нее. Ответы-ошибки возвращаются
+// как значения, см. Value.Err.
+func (c *Client) Do(args ...string) (Value, error) {
+	if err := c.Send(args...); err != nil {
+		return Value{}, err
+	}
+	return c.Receive()
+}
+
+// Close closes the connection.
+//
+// Close закрывает соединение.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,365 @@
+// Package resp implements the Redis serialization protocol (RESP2):
+// a reader and a writer of its values and a client sending commands.
+//
+// Package resp реализует протокол Redis (RESP2): чтение и запись его
+// значений и клиент, посылающий команды.
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Types of values.
+//
+// Типы значений.
+const (
+	SimpleString = '+'
+	Error        = '-'
+	Integer      = ':'
+	BulkString   = '$'
+	Array        = '*'
+)
+
+// MaxBulkLen is the largest length of a bulk string accepted by Reader.
+// Values are sent to nodes in one gRPC message, which is limited
+// to 4 MiB by default, so larger ones could not be stored anyway.
+//
+// MaxBulkLen -- наибольшая длина bulk string, принимаемая Reader.
+// Значения посылаются в node одним сообщением gRPC, размер которого
+// по умолчанию ограничен 4 MiB, поэтому большие значения все равно
+// не удалось бы сохранить.
+const MaxBulkLen = 4 << 20
+
+// MaxArrayLen is the largest number of elements of an array accepted
+// by Reader.
+//
+// MaxArrayLen -- наибольшее число элементов массива, принимаемое Reader.
+const MaxArrayLen = 1 << 16
+
+// MaxDepth is the largest nesting depth of arrays accepted by ReadValue.
+//
+// MaxDepth -- наибольшая глубина вложенности массивов, принимаемая ReadValue.
+const MaxDepth = 8
+
+// allocLen is the largest number of elements or bytes allocated ahead
+// of data. Buffers grow as data arrives, so that a client cannot make
+// Reader allocate much memory only by declaring large lengths.
+const allocLen = 1 << 10
+
+// ErrProtocol is returned by Reader for malformed input.
+//
+// ErrProtocol возвращается Reader для некорректных данных.
+var ErrProtocol = errors.New("Protocol error")
+
+// Value is a RESP value. Str holds simple strings and errors, Int holds
+// integers, Bulk holds bulk strings and Array holds arrays. Null bulk
+// strings and arrays have Null set.
+//
+// Value -- значение RESP. Str содержит простые строки и ошибки, Int --
+// целые числа, Bulk -- bulk strings, Array -- массивы. У пустых (null)
+// bulk strings и массивов установлен Null.
+type Value struct {
+	Type  byte
+	Str   string
+	Int   int64
+	Bulk  []byte
+	Array []Value
+	Null  bool
+}
+
+// Err returns the error of an Error value, nil for other values.
+//
+// Err возвращает ошибку значения типа Error, nil для других значений.
+func (v Value) Err() error {
+	if v.Type != Error {
+		return nil
+	}
+	return errors.New(v.Str)
+}
+
+// Reader reads RESP values.
+//
+// Reader читает значения RESP.
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader returns a Reader reading from r.
+//
+// NewReader возвращает Reader, читающий из r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Buffered returns the number of bytes read from the underlying reader
+// but not consumed yet, e.g. of pipelined commands.
+//
+// Buffered возвращает количество байт, прочитанных, но еще не
+// разобранных, например, байт последующих команд.
+func (r *Reader) Buffered() int {
+	return r.r.Buffered()
+}
+
+// line reads a line without its CRLF ending.
+func (r *Reader) line() (string, error) {
+	line, err := r.r.ReadString('\n')
+	if err != nil {
+		if err == io.EOF && line != "" {
+			return "", io.ErrUnexpectedEOF
+		}
+		return "", err
+	}
+	if !strings.HasSuffix(line, "\r\n") {
+		return "", ErrProtocol
+	}
+	return line[:len(line)-2], nil
+}
+
+// length parses a length of a bulk string or an array not greater than max,
+// -1 means null.
+func length(s string, max int64) (int64, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < -1 || n > max {
+		return 0, ErrProtocol
+	}
+	return n, nil
+}
+
+// ReadValue reads a value. Arrays nested deeper than MaxDepth are
+// rejected with ErrProtocol.
+//
+// ReadValue читает значение. Массивы с вложенностью глубже MaxDepth
+// отвергаются с ошибкой ErrProtocol.
+func (r *Reader) ReadValue() (Value, error) {
+	return r.readValue(0)
+}
+
+// readValue reads a value nested in depth arrays.
+func (r *Reader) readValue(depth int) (Value, error) {
+	line, err := r.line()
+	if err != nil {
+		return Value{}, err
+	}
+	if line == "" {
+		return Value{}, ErrProtocol
+	}
+	v := Value{Type: line[0]}
+	switch v.Type {
+	case SimpleString, Error:
+		v.Str = line[1:]
+	case Integer:
+		if v.Int, err = strconv.ParseInt(line[1:], 10, 64); err != nil {
+			return Value{}, ErrProtocol
+		}
+	case BulkString:
+		n, err := length(line[1:], MaxBulkLen)
+		if err != nil {
+			return Value{}, err
+		}
+		if n < 0 {
+			v.Null = true
+			break
+		}
+		if v.Bulk, err = r.bulk(n); err != nil {
+			return Value{}, err
+		}
+	case Array:
+		if depth >= MaxDepth {
+			return Value{}, ErrProtocol
+		}
+		n, err := length(line[1:], MaxArrayLen)
+		if err != nil {
+			return Value{}, err
+		}
+		if n < 0 {
+			v.Null = true
+			break
+		}
+		v.Array = make([]Value, 0, allocCap(n))
+		for i := int64(0); i < n; i++ {
+			elem, err := r.readValue(depth + 1)
+			if err != nil {
+				return Value{}, unexpected(err)
+			}
+			v.Array = append(v.Array, elem)
+		}
+	default:
+		return Value{}, ErrProtocol
+	}
+	return v, nil
+}
+
+// bulk reads n bytes of a bulk string and its CRLF ending.
+func (r *Reader) bulk(n int64) ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, allocCap(n+2)))
+	if _, err := io.CopyN(buf, r.r, n+2); err != nil {
+		return nil, unexpected(err)
+	}
+	b := buf.Bytes()
+	if b[n] != '\r' || b[n+1] != '\n' {
+		return nil, ErrProtocol
+	}
+	return b[:n], nil
+}
+
+// allocCap returns the capacity to allocate for n elements or bytes.
+func allocCap(n int64) int64 {
+	if n > allocLen {
+		return allocLen
+	}
+	return n
+}
+
+// unexpected turns io.EOF in the middle of a value into io.ErrUnexpectedEOF.
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// ReadCommand reads a command, either a flat array of bulk strings or
+// an inline command of words separated by spaces. Empty commands
+// are skipped. Other values are rejected with ErrProtocol.
+//
+// ReadCommand читает команду: плоский массив bulk strings или строку
+// слов, разделенных пробелами. Пустые команды пропускаются. Другие
+// значения отвергаются с ошибкой ErrProtocol.
+func (r *Reader) ReadCommand() ([][]byte, error) {
+	for {
+		b, err := r.r.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		if b[0] != Array {
+			line, err := r.line()
+			if err != nil {
+				return nil, err
+			}
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			args := make([][]byte, 0, len(fields))
+			for _, f := range fields {
+				args = append(args, []byte(f))
+			}
+			return args, nil
+		}
+		line, err := r.line()
+		if err != nil {
+			return nil, err
+		}
+		n, err := length(line[1:], MaxArrayLen)
+		if err != nil {
+			return nil, err
+		}
+		if n <= 0 {
+			continue
+		}
+		args := make([][]byte, 0, allocCap(n))
+		for i := int64(0); i < n; i++ {
+			line, err := r.line()
+			if err != nil {
+				return nil, unexpected(err)
+			}
+			if line == "" || line[0] != BulkString {
+				return nil, ErrProtocol
+			}
+			m, err := length(line[1:], MaxBulkLen)
+			if err != nil {
+				return nil, err
+			}
+			if m < 0 {
+				return nil, ErrProtocol
+			}
+			arg, err := r.bulk(m)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+		}
+		return args, nil
+	}
+}
+
+// Writer writes RESP values. Values are buffered until Flush.
+//
+// Writer записывает значения RESP. Значения буферизуются до вызова Flush.
+type Writer struct {
+	w *bufio.Writer
+}
+
+// NewWriter returns a Writer writing to w.
+//
+// NewWriter возвращает Writer, записывающий в w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// WriteSimple writes a simple string, s must not contain CR or LF.
+//
+// WriteSimple записывает простую строку, s не должна содержать CR и LF.
+func (w *Writer) WriteSimple(s string) error {
+	_, err := fmt.Fprintf(w.w, "+%s\r\n", s)
+	return err
+}
+
+// WriteError writes an error, CR and LF of s are replaced with spaces.
+//
+// WriteError записывает ошибку, CR и LF в s заменяются пробелами.
+func (w *Writer) WriteError(s string) error {
+	s = strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+	_, err := fmt.Fprintf(w.w, "-%s\r\n", s)
+	return err
+}
+
+// WriteInt writes an integer.
+//
+// WriteInt записывает целое число.
+func (w *Writer) WriteInt(n int64) error {
+	_, err := fmt.Fprintf(w.w, ":%d\r\n", n)
+	return err
+}
+
+// WriteBulk writes a bulk string, nil b is written as a null bulk string.
+//
+// WriteBulk записывает bulk string, nil b записывается как пустая (null)
+// bulk string.
+func (w *Writer) WriteBulk(b []byte) error {
+	if b == nil {
+		_, err := w.w.WriteString("$-1\r\n")
+		return err
+	}
+	if _, err := fmt.Fprintf(w.w, "$%d\r\n", len(b)); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(b); err != nil {
+		return err
+	}
+	_, err := w.w.WriteString("\r\n")
+	return err
+}
+
+// WriteArray writes a header of an array of n values, which are written
+// after it.
+//
+// WriteArray записывает заголовок массива из n значений, которые
+// записываются после него.
+func (w *Writer) WriteArray(n int) error {
+	_, err := fmt.Fprintf(w.w, "*%d\r\n", n)
+	return err
+}
+
+// Flush writes buffered values.
+//
+// Flush записывает буферизованные значения.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
@@ -0,0 +1,115 @@
+package resp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteRead(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.WriteSimple("OK")
+	w.WriteError("ERR bad\r\nthing")
+	w.WriteInt(-42)
+	w.WriteBulk([]byte("a\r\nb"))
+	w.WriteBulk([]byte{})
+	w.WriteBulk(nil)
+	w.WriteArray(2)
+	w.WriteInt(1)
+	w.WriteBulk([]byte("x"))
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error: %v", err)
+	}
+
+	want := []Value{
+		{Type: SimpleString, Str: "OK"},
+		{Type: Error, Str: "ERR bad  thing"},
+		{Type: Integer, Int: -42},
+		{Type: BulkString, Bulk: []byte("a\r\nb")},
+		{Type: BulkString, Bulk: []byte{}},
+		{Type: BulkString, Null: true},
+		{Type: Array, Array: []Value{{Type: Integer, Int: 1}, {Type: BulkString, Bulk: []byte("x")}}},
+	}
+	r := NewReader(&buf)
+	for _, v := range want {
+		got, err := r.ReadValue()
+		if err != nil {
+			t.Fatalf("ReadValue() error: %v", err)
+		}
+		if !reflect.DeepEqual(got, v) {
+			t.Errorf("ReadValue() got %+v, want %+v", got, v)
+		}
+	}
+	if _, err := r.ReadValue(); err != io.EOF {
+		t.Errorf("ReadValue() at the end got error %v, want %v", err, io.EOF)
+	}
+	if err := want[1].Err(); err == nil || err.Error() != "ERR bad  thing" {
+		t.Errorf("Err() got %v", err)
+	}
+	if err := want[0].Err(); err != nil {
+		t.Errorf("Err() of a simple string got %v", err)
+	}
+}
+
+func TestReadCommand(t *testing.T) {
+	r := NewReader(strings.NewReader("*2\r\n$3\r\nGET\r\n$1\r\n1\r\n\r\n*0\r\nset  1 two\r\n"))
+	for _, want := range [][]string{{"GET", "1"}, {"set", "1", "two"}} {
+		args, err := r.ReadCommand()
+		if err != nil {
+			t.Fatalf("ReadCommand() error: %v", err)
+		}
+		var got []string
+		for _, arg := range args {
+			got = append(got, string(arg))
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ReadCommand() got %q, want %q", got, want)
+		}
+	}
+	if _, err := r.ReadCommand(); err != io.EOF {
+		t.Errorf("ReadCommand() at the end got error %v, want %v", err, io.EOF)
+	}
+}
+
+func TestRead_Malformed(t *testing.T) {
+	for _, tc := range []struct {
+		in  string
+		err error
+	}{
+		{"+OK\n", ErrProtocol},
+		{"\r\n", ErrProtocol},
+		{"?x\r\n", ErrProtocol},
+		{":x\r\n", ErrProtocol},
+		{"$-2\r\n", ErrProtocol},
+		{"$3\r\nabcd\r\n", ErrProtocol},
+		{fmt.Sprintf("$%d\r\n", MaxBulkLen+1), ErrProtocol},
+		{fmt.Sprintf("*%d\r\n", MaxArrayLen+1), ErrProtocol},
+		{strings.Repeat("*1\r\n", MaxDepth+1) + ":1\r\n", ErrProtocol},
+		{"$3\r\nab", io.ErrUnexpectedEOF},
+		{"*2\r\n:1\r\n", io.ErrUnexpectedEOF},
+		{"+OK", io.ErrUnexpectedEOF},
+	} {
+		if _, err := NewReader(strings.NewReader(tc.in)).ReadValue(); err != tc.err {
+			t.Errorf("ReadValue(%q) got error %v, want %v", tc.in, err, tc.err)
+		}
+	}
+	for _, in := range []string{
+		"*1\r\n:1\r\n",
+		"*1\r\n$-1\r\n",
+		"*1\r\n*1\r\n$3\r\nGET\r\n",
+		strings.Repeat("*1\r\n", 1<<20),
+	} {
+		if _, err := NewReader(strings.NewReader(in)).ReadCommand(); err != ErrProtocol {
+			t.Errorf("ReadCommand(%.20q) got error %v, want %v", in, err, ErrProtocol)
+		}
+	}
+	// lengths alone do not allocate
+	in := fmt.Sprintf("*%d\r\n$%d\r\nab", MaxArrayLen, MaxBulkLen)
+	if _, err := NewReader(strings.NewReader(in)).ReadCommand(); err != io.ErrUnexpectedEOF {
+		t.Errorf("ReadCommand(%q) got error %v, want %v", in, err, io.ErrUnexpectedEOF)
+	}
+}
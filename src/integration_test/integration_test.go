@@ -2,6 +2,7 @@ package integration_test
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/binary"
 	"flag"
@@ -12,6 +13,8 @@ import (
 	"os"
 	"time"
 
+	"frontend/client"
+	"frontend/frontend"
 	"integration_test/runner"
 	"storage"
 	"testing"
@@ -45,8 +48,29 @@ func getTestData(key storage.RecordID) []byte {
 	return h.Sum(buf)
 }
 
+// direct sends requests of storage.Client straight to nodes ignoring
+// frontend addresses.
+type direct struct {
+	c *client.Client
+}
+
+func (d direct) Put(node storage.ServiceAddr, k storage.RecordID, data []byte) error {
+	return d.c.Put(k, data)
+}
+
+func (d direct) Get(node storage.ServiceAddr, k storage.RecordID) ([]byte, error) {
+	return d.c.Get(k)
+}
+
+func (d direct) Del(node storage.ServiceAddr, k storage.RecordID) error {
+	return d.c.Del(k)
+}
+
 func iterationSimple(t *testing.T, n int) {
-	client := storage.NewClient()
+	iteration(t, storage.NewClient(), n)
+}
+
+func iteration(t *testing.T, client storage.Client, n int) {
 
 	keys := make([]storage.RecordID, 0, n)
 	for k := 0; k < n; k++ {
@@ -118,6 +142,40 @@ func TestAllAlive(t *testing.T) {
 	r.Stop()
 }
 
+func TestAllAlive_Direct(t *testing.T) {
+	c, err := client.New(frontend.Config{Router: router})
+	if err != nil {
+		t.Fatalf("client.New() error: %v", err)
+	}
+	r := &runner.Runner{}
+	r.Start(router, fe, nodes, nodes)
+	iteration(t, direct{c}, n)
+	r.Stop()
+}
+
+// TestAllAlive_Stream streams a value larger than a gRPC message through
+// a frontend to nodes and back.
+func TestAllAlive_Stream(t *testing.T) {
+	r := &runner.Runner{}
+	r.Start(router, fe, nodes, nodes)
+	defer r.Stop()
+
+	c := storage.StorageClient{}
+	key := storage.RecordID(rand.Uint32())
+	value := make([]byte, 5<<20)
+	rand.Read(value)
+	if err := c.PutStream(context.Background(), fe[0], key, bytes.NewReader(value)); err != nil {
+		t.Fatalf("PutStream() error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := c.GetStream(context.Background(), fe[1], key, &buf); err != nil || !bytes.Equal(buf.Bytes(), value) {
+		t.Errorf("GetStream() got %v bytes, %v, want %v bytes", buf.Len(), err, len(value))
+	}
+	if err := c.Del(fe[1], key); err != nil {
+		t.Errorf("Del() error: %v", err)
+	}
+}
+
 func TestOneDead(t *testing.T) {
 	r := &runner.Runner{}
 
@@ -9,6 +9,7 @@ import (
 
 	"node/node"
 	"router/client"
+	"router/router"
 	"storage"
 )
 
@@ -33,8 +34,14 @@ func parseConfig(fname string) (cfg node.Config, err error) {
 	if cfg.Addr == "" {
 		return cfg, fmt.Errorf("Failed to parse config file %q: Addr should be set", fname)
 	}
+	if cfg.Router == "" && len(cfg.Routers) > 0 {
+		cfg.Router = cfg.Routers[0]
+	}
 	if cfg.Router == "" {
-		return cfg, fmt.Errorf("Failed to parse config file %q: Router should be set", fname)
+		return cfg, fmt.Errorf("Failed to parse config file %q: Router or Routers should be set", fname)
+	}
+	if cfg.Token != "" && cfg.ID == "" {
+		return cfg, fmt.Errorf("Failed to parse config file %q: ID should be set along with Token", fname)
 	}
 	if cfg.Heartbeat == 0 {
 		return cfg, fmt.Errorf("Failed to parse config file %q: Hearbeat should be set", fname)
@@ -43,6 +50,14 @@ func parseConfig(fname string) (cfg node.Config, err error) {
 	return cfg, nil
 }
 
+func newClient(cfg node.Config) client.Client {
+	if cfg.ID != "" {
+		id := router.Identity{ID: cfg.ID, Token: cfg.Token}
+		return client.NewIdentified(id, cfg.Routers...)
+	}
+	return client.New(cfg.Routers...)
+}
+
 func main() {
 	if len(os.Args) != 2 {
 		usage()
@@ -54,10 +69,23 @@ func main() {
 		log.Fatal(err)
 	}
 
-	cfg.Client = client.New()
+	cfg.Client = newClient(cfg)
 
 	st := node.New(cfg)
 	st.Heartbeats()
+	storage.WatchConfig(os.Args[1], func() {
+		cfg, err := parseConfig(os.Args[1])
+		if err != nil {
+			log.Printf("Config is not reloaded: %v", err)
+			return
+		}
+		cfg.Client = newClient(cfg)
+		if err := st.Reload(cfg); err != nil {
+			log.Printf("Config is reloaded partially: %v", err)
+			return
+		}
+		log.Printf("Config is reloaded")
+	})
 
 	srv := storage.NewServer(st, string(cfg.Addr))
 	if err := srv.ListenAndServe(); err != nil {
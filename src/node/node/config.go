@@ -0,0 +1,54 @@
+package node
+
+import (
+	yaml "gopkg.in/yaml.v2"
+
+	"storage"
+)
+
+// Config returns the running configuration of the node.
+//
+// Config возвращает текущую конфигурацию node.
+func (node *Node) Config() Config {
+	node.RLock()
+	defer node.RUnlock()
+	return node.cfg
+}
+
+// Reload applies cfg to the running node. The listening address cannot
+// be changed live, a storage.RestartError is returned if it differs,
+// other fields are applied anyway.
+//
+// Reload применяет cfg к работающей node. Слушающий адрес нельзя изменить
+// без перезапуска, если он отличается, возвращается storage.RestartError,
+// остальные поля применяются.
+func (node *Node) Reload(cfg Config) error {
+	node.Lock()
+	defer node.Unlock()
+	var restart []string
+	if cfg.Addr != node.cfg.Addr {
+		restart = append(restart, "addr")
+		cfg.Addr = node.cfg.Addr
+	}
+	if cfg.Client == nil {
+		cfg.Client = node.cfg.Client
+	}
+	node.cfg = cfg
+	if len(restart) > 0 {
+		return storage.RestartError{Fields: restart}
+	}
+	return nil
+}
+
+// EffectiveConfig returns the running configuration in YAML.
+// The token is not reported.
+//
+// EffectiveConfig возвращает текущую конфигурацию в YAML.
+// Токен не сообщается.
+func (node *Node) EffectiveConfig() ([]byte, error) {
+	cfg := node.Config()
+	if cfg.Token != "" {
+		cfg.Token = "<redacted>"
+	}
+	return yaml.Marshal(cfg)
+}
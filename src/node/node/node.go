@@ -18,9 +18,18 @@ type Config struct {
 	// Router is an address of Router service.
 	// Router -- адрес Router service.
 	Router storage.ServiceAddr
+	// Routers is a list of routers of the cluster to fail over to.
+	// Routers -- список router кластера, на которые нужно переключаться при отказе.
+	Routers []storage.ServiceAddr
 	// Hearbeat is a time interval between hearbeats.
 	// Hearbeat -- интервал между двумя heartbeats.
 	Heartbeat time.Duration
+	// ID is an identity of the node, see router.Config.Identities.
+	// ID -- идентичность node, см. router.Config.Identities.
+	ID string
+	// Token is a secret the node proves its ID with.
+	// Token -- секрет, которым node подтверждает свой ID.
+	Token string
 
 	// Client specifies client for Router.
 	// Client -- клиент для Router.
@@ -30,16 +39,55 @@ type Config struct {
 // Node is a Node service.
 type Node struct {
 	sync.RWMutex
-	cfg     Config
-	hbch    chan struct{}
-	Storage map[storage.RecordID][]byte
+	cfg      Config
+	hbch     chan struct{}
+	Storage  map[storage.RecordID][]byte
+	streamed map[storage.RecordID]*value
+
+	bytes   uint64
+	rec     *storage.Recorder
+	started time.Time
 }
 
 // New creates a new Node with a given cfg.
 //
 // New создает новый Node с данным cfg.
 func New(cfg Config) *Node {
-	return &Node{cfg: cfg, hbch: make(chan struct{}), Storage: make(map[storage.RecordID][]byte)}
+	return &Node{
+		cfg:      cfg,
+		hbch:     make(chan struct{}),
+		Storage:  make(map[storage.RecordID][]byte),
+		streamed: make(map[storage.RecordID]*value),
+		rec:      storage.NewRecorder(),
+		started:  time.Now(),
+	}
+}
+
+// Stats returns load statistics of the node. Request rates and latencies
+// are computed since the previous call.
+//
+// Stats возвращает статистику нагрузки node. Частота запросов и задержки
+// вычисляются с момента предыдущего вызова.
+func (node *Node) Stats() storage.Stats {
+	node.RLock()
+	st := storage.Stats{
+		Records: uint64(len(node.Storage) + len(node.streamed)),
+		Bytes:   node.bytes,
+		Uptime:  time.Since(node.started),
+		Version: storage.Version,
+	}
+	node.RUnlock()
+	node.rec.Snapshot(&st)
+	return st
+}
+
+func (node *Node) heartbeat() {
+	cfg := node.Config()
+	if c, ok := cfg.Client.(router.StatsReporter); ok {
+		c.HeartbeatStats(cfg.Router, cfg.Addr, node.Stats())
+		return
+	}
+	cfg.Client.Heartbeat(cfg.Router, cfg.Addr)
 }
 
 // Hearbeats runs heartbeats from node to a router
@@ -50,12 +98,12 @@ func New(cfg Config) *Node {
 func (node *Node) Heartbeats() {
 	go func() {
 		for {
-			time.Sleep(node.cfg.Heartbeat)
+			time.Sleep(node.Config().Heartbeat)
 			select {
 			case <-node.hbch:
 				return
 			default:
-				node.cfg.Client.Heartbeat(node.cfg.Router, node.cfg.Addr)
+				node.heartbeat()
 			}
 		}
 	}()
@@ -68,33 +116,56 @@ func (node *Node) Stop() {
 	node.hbch <- struct{}{}
 }
 
+// Ping checks that the node storage is not blocked.
+//
+// Ping проверяет, что хранилище node не заблокировано.
+func (node *Node) Ping() error {
+	node.RLock()
+	defer node.RUnlock()
+	return nil
+}
+
 // Put an item to the node if an item for the given key doesn't exist.
 // Returns the storage.ErrRecordExists error otherwise.
 //
 // Put -- добавить запись в node, если запись для данного ключа
 // не существует. Иначе вернуть ошибку storage.ErrRecordExists.
 func (node *Node) Put(k storage.RecordID, d []byte) error {
+	defer node.record(storage.OpPut, time.Now())
 	node.Lock()
 	defer node.Unlock()
-	if _, ok := node.Storage[k]; ok {
+	if node.exists(k) {
 		return storage.ErrRecordExists
 	}
 	node.Storage[k] = d
+	node.bytes += uint64(len(d))
 	return nil
 }
 
+func (node *Node) record(op storage.Op, start time.Time) {
+	node.rec.Record(op, time.Since(start))
+}
+
 // Del an item from the node if an item exists for the given key.
 // Returns the storage.ErrRecordNotFound error otherwise.
 //
 // Del -- удалить запись из node, если запись для данного ключа
 // существует. Иначе вернуть ошибку storage.ErrRecordNotFound.
 func (node *Node) Del(k storage.RecordID) error {
+	defer node.record(storage.OpDel, time.Now())
 	node.Lock()
 	defer node.Unlock()
-	if _, ok := node.Storage[k]; !ok {
+	if v, ok := node.streamed[k]; ok {
+		delete(node.streamed, k)
+		node.bytes -= uint64(v.size)
+		return nil
+	}
+	d, ok := node.Storage[k]
+	if !ok {
 		return storage.ErrRecordNotFound
 	}
 	delete(node.Storage, k)
+	node.bytes -= uint64(len(d))
 	return nil
 }
 
@@ -104,8 +175,12 @@ func (node *Node) Del(k storage.RecordID) error {
 // Get -- получить запись из node, если запись для данного ключа
 // существует. Иначе вернуть ошибку storage.ErrRecordNotFound.
 func (node *Node) Get(k storage.RecordID) ([]byte, error) {
+	defer node.record(storage.OpGet, time.Now())
 	node.RLock()
 	defer node.RUnlock()
+	if v, ok := node.streamed[k]; ok {
+		return v.bytes(), nil
+	}
 	d, ok := node.Storage[k]
 	if !ok {
 		return nil, storage.ErrRecordNotFound
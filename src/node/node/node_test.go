@@ -1,11 +1,15 @@
 package node
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"os"
 	"reflect"
 	"runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -72,6 +76,94 @@ func TestDel(t *testing.T) {
 	}
 }
 
+func TestPing(t *testing.T) {
+	s := New(cfg)
+	if err := s.Ping(); err != nil {
+		t.Errorf("Ping() error: %v", err)
+	}
+
+	done := make(chan struct{})
+	s.Lock()
+	go func() {
+		s.Ping()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Errorf("Ping() succeeded while the storage is locked")
+	case <-time.After(50 * time.Millisecond):
+	}
+	s.Unlock()
+	<-done
+}
+
+func TestStats(t *testing.T) {
+	s := New(cfg)
+	for i := 0; i < 3; i++ {
+		if err := s.Put(storage.RecordID(i), []byte("data")); err != nil {
+			t.Fatalf("Put() error: %v", err)
+		}
+	}
+	s.Get(0)
+	if err := s.Del(0); err != nil {
+		t.Fatalf("Del() error: %v", err)
+	}
+
+	st := s.Stats()
+	if st.Records != 2 || st.Bytes != 8 {
+		t.Errorf("Stats() got %v records of %v bytes, want 2 records of 8 bytes", st.Records, st.Bytes)
+	}
+	if st.GetRate <= 0 || st.PutRate <= st.DelRate || st.DelRate <= 0 {
+		t.Errorf("Stats() got rates get %v, put %v, del %v", st.GetRate, st.PutRate, st.DelRate)
+	}
+	if st.LatencyP99 < st.LatencyP50 {
+		t.Errorf("Stats() got p99 %v less than p50 %v", st.LatencyP99, st.LatencyP50)
+	}
+	if st.Version != storage.Version {
+		t.Errorf("Stats() got version %q, want %q", st.Version, storage.Version)
+	}
+
+	st = s.Stats()
+	if st.Records != 2 || st.PutRate != 0 || st.LatencyP50 != 0 {
+		t.Errorf("Stats() got %+v, want rates and latencies reset", st)
+	}
+}
+
+type FakeStatsClient struct {
+	FakeClientStopHeartbeat
+	stats []storage.Stats
+}
+
+func (c *FakeStatsClient) HeartbeatStats(router, node storage.ServiceAddr, st storage.Stats) error {
+	c.Lock()
+	defer c.Unlock()
+	c.stats = append(c.stats, st)
+	return nil
+}
+
+func TestHeartbeatStats(t *testing.T) {
+	c := &FakeStatsClient{FakeClientStopHeartbeat: FakeClientStopHeartbeat{t: t}}
+	s := New(Config{
+		Client:    c,
+		Addr:      "test",
+		Heartbeat: 50 * time.Millisecond,
+	})
+	s.Put(1, []byte("data"))
+
+	s.Heartbeats()
+	time.Sleep(200 * time.Millisecond)
+	s.Stop()
+
+	c.Lock()
+	defer c.Unlock()
+	if c.received {
+		t.Errorf("Heartbeat() was used instead of HeartbeatStats()")
+	}
+	if len(c.stats) == 0 || c.stats[0].Records != 1 {
+		t.Errorf("HeartbeatStats() got %+v, want stats of 1 record", c.stats)
+	}
+}
+
 func TestParallelOps(t *testing.T) {
 	s := New(cfg)
 	var keys []storage.RecordID
@@ -236,3 +328,94 @@ func TestMain(m *testing.M) {
 	rand.Seed(time.Now().UnixNano())
 	os.Exit(m.Run())
 }
+
+func TestReload(t *testing.T) {
+	s := New(Config{
+		Client:    &FakeStatsClient{},
+		Addr:      "test",
+		Router:    "router1",
+		Heartbeat: time.Second,
+	})
+	err := s.Reload(Config{
+		Addr:      "other",
+		Router:    "router2",
+		Heartbeat: time.Minute,
+		ID:        "node1",
+		Token:     "secret",
+	})
+	if e, ok := err.(storage.RestartError); !ok || !reflect.DeepEqual(e.Fields, []string{"addr"}) {
+		t.Errorf("Reload() got error %v, want restart of addr", err)
+	}
+	cfg := s.Config()
+	if cfg.Addr != "test" || cfg.Router != "router2" || cfg.Heartbeat != time.Minute || cfg.Client == nil {
+		t.Errorf("Config() after Reload() got %+v", cfg)
+	}
+
+	data, err := s.EffectiveConfig()
+	if err != nil {
+		t.Fatalf("EffectiveConfig() error: %v", err)
+	}
+	if !strings.Contains(string(data), "heartbeat: 1m0s") || strings.Contains(string(data), "secret") {
+		t.Errorf("EffectiveConfig() got %s, want heartbeat and no token", data)
+	}
+}
+
+func TestPutGetStream(t *testing.T) {
+	s := New(cfg)
+	ctx := context.Background()
+	key := storage.RecordID(1)
+	data := make([]byte, 2*storage.ChunkSize+10)
+	rand.Read(data)
+
+	if err := s.GetStream(ctx, key, ioutil.Discard); err != storage.ErrRecordNotFound {
+		t.Fatalf("GetStream(): got error %v, want %v", err, storage.ErrRecordNotFound)
+	}
+	if err := s.PutStream(ctx, key, bytes.NewReader(data)); err != nil {
+		t.Fatalf("PutStream() error: %v", err)
+	}
+	if got := len(s.streamed[key].chunks); got != 3 {
+		t.Errorf("PutStream() stored %v chunks, want 3", got)
+	}
+	if err := s.PutStream(ctx, key, bytes.NewReader(data)); err != storage.ErrRecordExists {
+		t.Errorf("PutStream() got error %v, want %v", err, storage.ErrRecordExists)
+	}
+	if err := s.Put(key, data); err != storage.ErrRecordExists {
+		t.Errorf("Put() got error %v, want %v", err, storage.ErrRecordExists)
+	}
+
+	var buf bytes.Buffer
+	if err := s.GetStream(ctx, key, &buf); err != nil || !bytes.Equal(buf.Bytes(), data) {
+		t.Errorf("GetStream() got %v bytes, %v, want the put value", buf.Len(), err)
+	}
+	if got, err := s.Get(key); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("Get() got %v bytes, %v, want the put value", len(got), err)
+	}
+	if d, err := s.Digest(ctx, key); err != nil || !bytes.Equal(d, storage.DigestOf(data)) {
+		t.Errorf("Digest() got %x, %v, want %x", d, err, storage.DigestOf(data))
+	}
+	if st := s.Stats(); st.Records != 1 || st.Bytes != uint64(len(data)) {
+		t.Errorf("Stats() got %v records of %v bytes, want 1 of %v", st.Records, st.Bytes, len(data))
+	}
+
+	if err := s.Del(key); err != nil {
+		t.Fatalf("Del() error: %v", err)
+	}
+	if err := s.GetStream(ctx, key, ioutil.Discard); err != storage.ErrRecordNotFound {
+		t.Errorf("GetStream() after Del() got error %v, want %v", err, storage.ErrRecordNotFound)
+	}
+	if st := s.Stats(); st.Records != 0 || st.Bytes != 0 {
+		t.Errorf("Stats() after Del() got %v records of %v bytes, want none", st.Records, st.Bytes)
+	}
+
+	// records put whole are streamed as well
+	if err := s.Put(key, data); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	buf.Reset()
+	if err := s.GetStream(ctx, key, &buf); err != nil || !bytes.Equal(buf.Bytes(), data) {
+		t.Errorf("GetStream() of a record put whole got %v bytes, %v", buf.Len(), err)
+	}
+	if d, err := s.Digest(ctx, key); err != nil || !bytes.Equal(d, storage.DigestOf(data)) {
+		t.Errorf("Digest() of a record put whole got %x, %v", d, err)
+	}
+}
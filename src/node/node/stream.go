@@ -0,0 +1,129 @@
+package node
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"storage"
+)
+
+// value is a record put by a stream, kept in chunks as it was received.
+type value struct {
+	chunks [][]byte
+	size   int
+	digest []byte
+}
+
+// bytes returns the value whole.
+func (v *value) bytes() []byte {
+	d := make([]byte, 0, v.size)
+	for _, chunk := range v.chunks {
+		d = append(d, chunk...)
+	}
+	return d
+}
+
+// exists reports whether a record for k is stored. node should be locked.
+func (node *Node) exists(k storage.RecordID) bool {
+	if _, ok := node.streamed[k]; ok {
+		return true
+	}
+	_, ok := node.Storage[k]
+	return ok
+}
+
+// PutStream puts a value read from r like Put. The value is kept
+// in chunks of storage.ChunkSize and never assembled whole.
+//
+// PutStream добавляет значение, прочитанное из r, так же, как Put.
+// Значение хранится частями размера storage.ChunkSize и никогда
+// не собирается целиком.
+func (node *Node) PutStream(ctx context.Context, k storage.RecordID, r io.Reader) error {
+	defer node.record(storage.OpPut, time.Now())
+	node.RLock()
+	exists := node.exists(k)
+	node.RUnlock()
+	if exists {
+		return storage.ErrRecordExists
+	}
+
+	v := &value{}
+	h := storage.NewDigest()
+	for {
+		chunk := make([]byte, storage.ChunkSize)
+		n, err := io.ReadFull(r, chunk)
+		if n > 0 {
+			if n < len(chunk) {
+				chunk = append([]byte(nil), chunk[:n]...)
+			}
+			v.chunks = append(v.chunks, chunk)
+			v.size += n
+			h.Write(chunk)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	v.digest = h.Sum(nil)
+
+	node.Lock()
+	defer node.Unlock()
+	if node.exists(k) {
+		return storage.ErrRecordExists
+	}
+	node.streamed[k] = v
+	node.bytes += uint64(v.size)
+	return nil
+}
+
+// GetStream writes the value for k to w chunk by chunk.
+// Returns the storage.ErrRecordNotFound error if there is no value.
+//
+// GetStream записывает значение для k в w по частям.
+// Возвращает ошибку storage.ErrRecordNotFound, если значения нет.
+func (node *Node) GetStream(ctx context.Context, k storage.RecordID, w io.Writer) error {
+	defer node.record(storage.OpGet, time.Now())
+	node.RLock()
+	v, streamed := node.streamed[k]
+	d, ok := node.Storage[k]
+	node.RUnlock()
+	if !streamed {
+		if !ok {
+			return storage.ErrRecordNotFound
+		}
+		_, err := w.Write(d)
+		return err
+	}
+	// chunks are never changed, so they are written unlocked
+	for _, chunk := range v.chunks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := w.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Digest returns the digest of the value for k, see storage.NewDigest.
+// Returns the storage.ErrRecordNotFound error if there is no value.
+//
+// Digest возвращает дайджест значения для k, см. storage.NewDigest.
+// Возвращает ошибку storage.ErrRecordNotFound, если значения нет.
+func (node *Node) Digest(ctx context.Context, k storage.RecordID) ([]byte, error) {
+	node.RLock()
+	defer node.RUnlock()
+	if v, ok := node.streamed[k]; ok {
+		return v.digest, nil
+	}
+	d, ok := node.Storage[k]
+	if !ok {
+		return nil, storage.ErrRecordNotFound
+	}
+	return storage.DigestOf(d), nil
+}
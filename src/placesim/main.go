@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"router/plan"
+	"router/router"
+	"storage"
+)
+
+func usage() {
+	fmt.Println("placesim -- simulates placement of records and reports what moves")
+	fmt.Println("between two router configurations")
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Println("  placesim -old=<conf.yaml> -new=<conf.yaml> (-keys=<first>-<last> | -sample=<file>) [-json] [-moves]")
+	fmt.Println()
+	fmt.Println("List of available options:")
+	flag.PrintDefaults()
+}
+
+var (
+	oldConf = flag.String("old", "", "current router config (REQUIRED)")
+	newConf = flag.String("new", "", "proposed router config (REQUIRED)")
+	keys    = flag.String("keys", "", "inclusive range of keys, e.g. 0-99999")
+	sample  = flag.String("sample", "", "file with a key per line")
+	asJSON  = flag.Bool("json", false, "print the report in JSON")
+	moves   = flag.Bool("moves", false, "list every changed record in the JSON report")
+	help    = flag.Bool("h", false, "show this help message")
+)
+
+func parseConfig(fname string) (plan.Placement, error) {
+	var p plan.Placement
+	f, err := os.Open(fname)
+	if err != nil {
+		return p, fmt.Errorf("Failed to open config file %q: %v", fname, err)
+	}
+	defer f.Close()
+	var cfg router.Config
+	if err := yaml.NewDecoder(f).Decode(&cfg); err != nil {
+		return p, fmt.Errorf("Failed to parse config file %q: %v", fname, err)
+	}
+	if len(cfg.Nodes) == 0 {
+		return p, fmt.Errorf("Failed to parse config file %q: Nodes should be set", fname)
+	}
+	p.Finder, err = router.NewNodesFinderByName(cfg.Placement, cfg.Hasher)
+	if err != nil {
+		return p, fmt.Errorf("Failed to parse config file %q: %v", fname, err)
+	}
+	p.Topology = router.Topology{
+		Nodes:   cfg.Nodes,
+		Weights: cfg.Weights,
+		Domains: cfg.Domains,
+		IDs:     cfg.IDs,
+	}
+	return p, nil
+}
+
+func parseKey(s string) (storage.RecordID, error) {
+	k, err := strconv.ParseUint(strings.TrimSpace(s), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("key %q should be a uint32 value", s)
+	}
+	return storage.RecordID(k), nil
+}
+
+func parseRange(s string) (plan.Keys, error) {
+	parts := strings.SplitN(s, "-", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("range %q should be <first>-<last>", s)
+	}
+	first, err := parseKey(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	last, err := parseKey(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	if first > last {
+		return nil, fmt.Errorf("range %q is empty", s)
+	}
+	return plan.Range(first, last), nil
+}
+
+func readSample(fname string) (plan.Keys, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var ks []storage.RecordID
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		k, err := parseKey(sc.Text())
+		if err != nil {
+			return nil, err
+		}
+		ks = append(ks, k)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return plan.Slice(ks), nil
+}
+
+func printText(r plan.Report) {
+	shares := make(map[storage.ServiceAddr][2]float64)
+	var nodes []storage.ServiceAddr
+	for i, o := range []plan.Ownership{r.Old, r.New} {
+		for _, s := range o.Nodes {
+			v, ok := shares[s.Node]
+			if !ok {
+				nodes = append(nodes, s.Node)
+			}
+			v[i] = s.Share
+			shares[s.Node] = v
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tOLD\tNEW\tDELTA")
+	for _, node := range nodes {
+		v := shares[node]
+		fmt.Fprintf(w, "%s\t%.2f%%\t%.2f%%\t%+.2f%%\n", node, v[0]*100, v[1]*100, (v[1]-v[0])*100)
+	}
+	w.Flush()
+	fmt.Println()
+	fmt.Printf("Skew: %.3f -> %.3f\n", r.Old.Skew, r.New.Skew)
+	fmt.Printf("Changed: %d of %d keys (%.2f%%)\n", r.Changed, r.Keys, r.ChangedFraction*100)
+
+	if len(r.Transfers) == 0 {
+		return
+	}
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "FROM\tTO\tKEYS")
+	for _, t := range r.Transfers {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", t.From, t.To, t.Keys)
+	}
+	w.Flush()
+}
+
+func main() {
+	flag.Parse()
+	if *help {
+		usage()
+		os.Exit(0)
+	}
+	if *oldConf == "" || *newConf == "" {
+		fmt.Fprintln(os.Stderr, "-old and -new should be set")
+		os.Exit(2)
+	}
+	if (*keys == "") == (*sample == "") {
+		fmt.Fprintln(os.Stderr, "exactly one of -keys and -sample should be set")
+		os.Exit(2)
+	}
+
+	from, err := parseConfig(*oldConf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	to, err := parseConfig(*newConf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	var ks plan.Keys
+	if *keys != "" {
+		ks, err = parseRange(*keys)
+	} else {
+		ks, err = readSample(*sample)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading keys: %v\n", err)
+		os.Exit(2)
+	}
+
+	r := plan.Simulate(from, to, ks, *moves)
+	if !*asJSON {
+		printText(r)
+		return
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		os.Exit(1)
+	}
+}
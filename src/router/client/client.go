@@ -5,10 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 
 	"router/pb"
+	"router/router"
 	"storage"
 )
 
@@ -18,58 +22,251 @@ type Client interface {
 	List(router storage.ServiceAddr) ([]storage.ServiceAddr, error)
 }
 
-type RouterClient struct{}
+// ContextClient is implemented by clients whose requests can be cancelled
+// and given deadlines with a context. Requests made with a context without
+// a deadline time out after storage.Timeout, as do requests made
+// by Client methods.
+type ContextClient interface {
+	HeartbeatContext(ctx context.Context, router, node storage.ServiceAddr) error
+	NodesFindContext(ctx context.Context, router storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, error)
+	// NodesFindEpochContext is like Watcher.NodesFindEpoch
+	// but the request is bound to ctx.
+	NodesFindEpochContext(ctx context.Context, router storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, uint64, error)
+	ListContext(ctx context.Context, router storage.ServiceAddr) ([]storage.ServiceAddr, error)
+}
 
-var defaultClient Client = RouterClient{}
+// Watcher is implemented by clients which can follow topology changes.
+type Watcher interface {
+	// WatchTopology calls cb with the current topology and then with
+	// each its change until ctx is done or the stream breaks.
+	WatchTopology(ctx context.Context, router storage.ServiceAddr, cb func(router.Topology)) error
+	// NodesFindEpoch is like Client.NodesFind but also returns
+	// the topology epoch the nodes were found at.
+	NodesFindEpoch(router storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, uint64, error)
+}
 
-func New() Client {
-	return defaultClient
+// TopologyLister is implemented by clients which can fetch the whole topology
+// including weights of nodes.
+type TopologyLister interface {
+	ListTopology(router storage.ServiceAddr) (router.Topology, error)
 }
 
-func (c RouterClient) do(addr storage.ServiceAddr, cb func(client pb.RouterClient) ([]storage.ServiceAddr, error)) ([]storage.ServiceAddr, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), storage.Timeout)
-	defer cancel()
-	conn, err := grpc.DialContext(ctx, string(addr), grpc.WithInsecure())
+// AliveLister is implemented by clients which can fetch nodes
+// considered alive by the router.
+type AliveLister interface {
+	// ListAlive returns nodes with the router.NodeAlive status
+	// and the topology epoch they were found at.
+	ListAlive(router storage.ServiceAddr) ([]storage.ServiceAddr, uint64, error)
+}
+
+// StatsReporter is implemented by clients which can send load statistics
+// of a node along with heartbeats.
+type StatsReporter interface {
+	HeartbeatStats(router, node storage.ServiceAddr, st storage.Stats) error
+}
+
+// RouterClient sends requests to a router. If a router is unavailable
+// or is not a leader of its cluster, the request is retried against
+// the leader reported by the router and then against the rest of routers.
+type RouterClient struct {
+	routers  []storage.ServiceAddr
+	leader   *leaderCache
+	identity router.Identity
+}
+
+// leaderCache remembers the last router which served a request.
+// A nil leaderCache remembers nothing.
+type leaderCache struct {
+	sync.Mutex
+	addr storage.ServiceAddr
+}
+
+func (l *leaderCache) get() storage.ServiceAddr {
+	if l == nil {
+		return ""
+	}
+	l.Lock()
+	defer l.Unlock()
+	return l.addr
+}
+
+func (l *leaderCache) set(addr storage.ServiceAddr) {
+	if l == nil {
+		return
+	}
+	l.Lock()
+	l.addr = addr
+	l.Unlock()
+}
+
+var defaultClient Client = RouterClient{leader: new(leaderCache)}
+
+// New creates a Client. Given routers are tried in order
+// when the requested router is unavailable.
+func New(routers ...storage.ServiceAddr) Client {
+	if len(routers) == 0 {
+		return defaultClient
+	}
+	return RouterClient{routers: routers, leader: new(leaderCache)}
+}
+
+// NewIdentified is like New but heartbeats sent by the client carry id.
+func NewIdentified(id router.Identity, routers ...storage.ServiceAddr) Client {
+	return RouterClient{routers: routers, leader: new(leaderCache), identity: id}
+}
+
+// notLeaderError is returned by request callbacks when a router
+// is not a leader. leader is an address of the current leader if known.
+type notLeaderError struct {
+	leader storage.ServiceAddr
+}
+
+func (e notLeaderError) Error() string {
+	return storage.ErrNotLeader.Error()
+}
+
+func (c RouterClient) candidates(addr storage.ServiceAddr) []storage.ServiceAddr {
+	leader := c.leader.get()
+	ret := make([]storage.ServiceAddr, 0, len(c.routers)+2)
+	if leader != "" {
+		ret = append(ret, leader)
+	}
+	ret = append(ret, addr)
+	return append(ret, c.routers...)
+}
+
+// do sends a request to the router at addr failing over to other routers
+// until ctx is done.
+func (c RouterClient) do(ctx context.Context, addr storage.ServiceAddr, cb func(ctx context.Context, client pb.RouterClient) ([]storage.ServiceAddr, error)) ([]storage.ServiceAddr, error) {
+	var lastErr error
+	tried := make(map[storage.ServiceAddr]bool)
+	queue := c.candidates(addr)
+	for len(queue) > 0 {
+		router := queue[0]
+		queue = queue[1:]
+		if tried[router] {
+			continue
+		}
+		tried[router] = true
+
+		nodes, err := c.doOne(ctx, router, cb)
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		if e, ok := err.(notLeaderError); ok {
+			if e.leader != "" {
+				queue = append([]storage.ServiceAddr{e.leader}, queue...)
+			}
+			lastErr = storage.ErrNotLeader
+			continue
+		}
+		if err != nil && transportError(err) {
+			lastErr = err
+			continue
+		}
+		c.leader.set(router)
+		return nodes, err
+	}
+	return nil, lastErr
+}
+
+func (c RouterClient) doOne(ctx context.Context, addr storage.ServiceAddr, cb func(ctx context.Context, client pb.RouterClient) ([]storage.ServiceAddr, error)) ([]storage.ServiceAddr, error) {
+	conn, err := storage.DefaultPool.Get(addr)
 	if err != nil {
-		return nil, fmt.Errorf("Error dialing %q: %v", addr, err)
+		return nil, dialError{fmt.Errorf("Error dialing %q: %v", addr, err)}
 	}
-	defer conn.Close()
+	ctx, cancel := storage.WithTimeout(ctx)
+	defer cancel()
 	client := pb.NewRouterClient(conn)
-	return cb(client)
+	return cb(ctx, client)
+}
+
+type dialError struct {
+	error
+}
+
+// transportError reports whether err is caused by an unavailable router
+// rather than returned by a router.
+func transportError(err error) bool {
+	if _, ok := err.(dialError); ok {
+		return true
+	}
+	_, ok := status.FromError(err)
+	return ok
+}
+
+func replyError(st int32, leader, msg string) error {
+	status := storage.StatusCode(st)
+	if status == storage.StatusNotLeader {
+		return notLeaderError{leader: storage.ServiceAddr(leader)}
+	}
+	if err := status.ToError(); err != storage.ErrUnknownStatus {
+		return err
+	}
+	return errors.New(msg)
 }
 
 func (c RouterClient) Heartbeat(router, node storage.ServiceAddr) error {
+	return c.HeartbeatContext(context.Background(), router, node)
+}
+
+// HeartbeatContext is like Heartbeat but the request is bound to ctx.
+func (c RouterClient) HeartbeatContext(ctx context.Context, router, node storage.ServiceAddr) error {
+	return c.heartbeat(ctx, router, pb.HBRequest{Node: string(node)})
+}
+
+// HeartbeatStats sends a heartbeat carrying load statistics of node.
+func (c RouterClient) HeartbeatStats(router, node storage.ServiceAddr, st storage.Stats) error {
+	return c.heartbeat(context.Background(), router, pb.HBRequest{
+		Node: string(node),
+		Stats: &pb.NodeStats{
+			Records:    st.Records,
+			Bytes:      st.Bytes,
+			GetRate:    st.GetRate,
+			PutRate:    st.PutRate,
+			DelRate:    st.DelRate,
+			LatencyP50: int64(st.LatencyP50),
+			LatencyP99: int64(st.LatencyP99),
+			Uptime:     int64(st.Uptime),
+			Version:    st.Version,
+		},
+	})
+}
+
+func (c RouterClient) heartbeat(ctx context.Context, router storage.ServiceAddr, req pb.HBRequest) error {
 	log.Printf("Hearbeat request to %q", router)
-	_, err := c.do(router, func(client pb.RouterClient) ([]storage.ServiceAddr, error) {
-		ctx, cancel := context.WithTimeout(context.Background(), storage.Timeout)
-		defer cancel()
-		req := pb.HBRequest{
-			Node: string(node),
-		}
+	req.NodeId = c.identity.ID
+	req.Token = c.identity.Token
+	_, err := c.do(ctx, router, func(ctx context.Context, client pb.RouterClient) ([]storage.ServiceAddr, error) {
 		reply, err := client.Heartbeat(ctx, &req)
 		if err != nil {
 			return nil, err
 		}
 
-		status := storage.StatusCode(reply.Status)
-
-		if status == storage.StatusOk {
-			return nil, nil
-		}
-
-		if err := status.ToError(); err != storage.ErrUnknownStatus {
-			return nil, err
-		}
-		return nil, errors.New(reply.Error)
+		return nil, replyError(reply.Status, reply.Leader, reply.Error)
 	})
 	return err
 }
 
 func (c RouterClient) NodesFind(router storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, error) {
+	return c.NodesFindContext(context.Background(), router, k)
+}
+
+// NodesFindContext is like NodesFind but the request is bound to ctx.
+func (c RouterClient) NodesFindContext(ctx context.Context, router storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, error) {
+	nodes, _, err := c.NodesFindEpochContext(ctx, router, k)
+	return nodes, err
+}
+
+func (c RouterClient) NodesFindEpoch(router storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, uint64, error) {
+	return c.NodesFindEpochContext(context.Background(), router, k)
+}
+
+// NodesFindEpochContext is like NodesFindEpoch but the request is bound to ctx.
+func (c RouterClient) NodesFindEpochContext(ctx context.Context, router storage.ServiceAddr, k storage.RecordID) ([]storage.ServiceAddr, uint64, error) {
 	log.Printf("NodesFind request: key = %v", k)
-	return c.do(router, func(client pb.RouterClient) ([]storage.ServiceAddr, error) {
-		ctx, cancel := context.WithTimeout(context.Background(), storage.Timeout)
-		defer cancel()
+	var epoch uint64
+	nodes, err := c.do(ctx, router, func(ctx context.Context, client pb.RouterClient) ([]storage.ServiceAddr, error) {
 		req := pb.NFRequest{
 			Key: uint32(k),
 		}
@@ -78,9 +275,8 @@ func (c RouterClient) NodesFind(router storage.ServiceAddr, k storage.RecordID)
 			return nil, err
 		}
 
-		status := storage.StatusCode(reply.Status)
-
-		if status == storage.StatusOk {
+		if storage.StatusCode(reply.Status) == storage.StatusOk {
+			epoch = reply.Epoch
 			nodes := make([]storage.ServiceAddr, 0, len(reply.Nodes))
 			for _, node := range reply.Nodes {
 				nodes = append(nodes, storage.ServiceAddr(node))
@@ -88,26 +284,59 @@ func (c RouterClient) NodesFind(router storage.ServiceAddr, k storage.RecordID)
 			return nodes, nil
 		}
 
-		if err := status.ToError(); err != storage.ErrUnknownStatus {
-			return nil, err
-		}
-		return nil, errors.New(reply.Error)
+		return nil, replyError(reply.Status, reply.Leader, reply.Error)
 	})
+	return nodes, epoch, err
 }
 
 func (c RouterClient) List(router storage.ServiceAddr) ([]storage.ServiceAddr, error) {
+	return c.ListContext(context.Background(), router)
+}
+
+// ListContext is like List but the request is bound to ctx.
+func (c RouterClient) ListContext(ctx context.Context, router storage.ServiceAddr) ([]storage.ServiceAddr, error) {
+	t, err := c.listTopology(ctx, router)
+	return t.Serving().Nodes, err
+}
+
+// ListTopology is like List but returns the whole topology
+// including weights and states of nodes.
+func (c RouterClient) ListTopology(addr storage.ServiceAddr) (router.Topology, error) {
+	return c.listTopology(context.Background(), addr)
+}
+
+func (c RouterClient) listTopology(ctx context.Context, addr storage.ServiceAddr) (router.Topology, error) {
 	log.Printf("List request")
-	return c.do(router, func(client pb.RouterClient) ([]storage.ServiceAddr, error) {
-		ctx, cancel := context.WithTimeout(context.Background(), storage.Timeout)
-		defer cancel()
+	var topology router.Topology
+	_, err := c.do(ctx, addr, func(ctx context.Context, client pb.RouterClient) ([]storage.ServiceAddr, error) {
 		reply, err := client.List(ctx, &pb.Empty{})
 		if err != nil {
 			return nil, err
 		}
 
-		status := storage.StatusCode(reply.Status)
+		if storage.StatusCode(reply.Status) == storage.StatusOk {
+			topology = topologyFromPB(reply.Epoch, reply.Nodes, reply.Weights, reply.Domains, reply.States, reply.Ids)
+			return nil, nil
+		}
+
+		return nil, replyError(reply.Status, reply.Leader, reply.Error)
+	})
+	return topology, err
+}
+
+// ListAlive returns nodes with the router.NodeAlive status
+// and the topology epoch they were found at.
+func (c RouterClient) ListAlive(addr storage.ServiceAddr) ([]storage.ServiceAddr, uint64, error) {
+	log.Printf("ListAlive request")
+	var epoch uint64
+	nodes, err := c.do(context.Background(), addr, func(ctx context.Context, client pb.RouterClient) ([]storage.ServiceAddr, error) {
+		reply, err := client.ListAlive(ctx, &pb.Empty{})
+		if err != nil {
+			return nil, err
+		}
 
-		if status == storage.StatusOk {
+		if storage.StatusCode(reply.Status) == storage.StatusOk {
+			epoch = reply.Epoch
 			nodes := make([]storage.ServiceAddr, 0, len(reply.Nodes))
 			for _, node := range reply.Nodes {
 				nodes = append(nodes, storage.ServiceAddr(node))
@@ -115,9 +344,291 @@ func (c RouterClient) List(router storage.ServiceAddr) ([]storage.ServiceAddr, e
 			return nodes, nil
 		}
 
-		if err := status.ToError(); err != storage.ErrUnknownStatus {
+		return nil, replyError(reply.Status, reply.Leader, reply.Error)
+	})
+	return nodes, epoch, err
+}
+
+func topologyFromPB(epoch uint64, nodes []string, weights map[string]float64, domains map[string]*pb.Domain, states map[string]int32, ids map[string]string) router.Topology {
+	t := router.Topology{
+		Epoch: epoch,
+		Nodes: make([]storage.ServiceAddr, 0, len(nodes)),
+	}
+	for _, node := range nodes {
+		t.Nodes = append(t.Nodes, storage.ServiceAddr(node))
+	}
+	if len(weights) > 0 {
+		t.Weights = make(map[storage.ServiceAddr]float64, len(weights))
+		for node, w := range weights {
+			t.Weights[storage.ServiceAddr(node)] = w
+		}
+	}
+	if len(domains) > 0 {
+		t.Domains = make(map[storage.ServiceAddr]router.Domain, len(domains))
+		for node, d := range domains {
+			t.Domains[storage.ServiceAddr(node)] = router.Domain{Zone: d.GetZone(), Rack: d.GetRack()}
+		}
+	}
+	if len(states) > 0 {
+		t.States = make(map[storage.ServiceAddr]router.NodeState, len(states))
+		for node, st := range states {
+			t.States[storage.ServiceAddr(node)] = router.NodeState(st)
+		}
+	}
+	if len(ids) > 0 {
+		t.IDs = make(map[storage.ServiceAddr]string, len(ids))
+		for node, id := range ids {
+			t.IDs[storage.ServiceAddr(node)] = id
+		}
+	}
+	return t
+}
+
+// ClusterStatus returns states of all nodes served by the leader router.
+func (c RouterClient) ClusterStatus(addr storage.ServiceAddr) ([]router.NodeInfo, error) {
+	log.Printf("ClusterStatus request")
+	var infos []router.NodeInfo
+	_, err := c.do(context.Background(), addr, func(ctx context.Context, client pb.RouterClient) ([]storage.ServiceAddr, error) {
+		reply, err := client.ClusterStatus(ctx, &pb.Empty{})
+		if err != nil {
+			return nil, err
+		}
+
+		if storage.StatusCode(reply.Status) != storage.StatusOk {
+			return nil, replyError(reply.Status, reply.Leader, reply.Error)
+		}
+		infos = make([]router.NodeInfo, 0, len(reply.Nodes))
+		for _, node := range reply.Nodes {
+			info := router.NodeInfo{
+				Node:         storage.ServiceAddr(node.Node),
+				HeartbeatAge: time.Duration(node.HeartbeatAge),
+				Status:       router.NodeStatus(node.NodeStatus),
+				Phi:          node.Phi,
+				State:        router.NodeState(node.State),
+			}
+			if st := node.Stats; st != nil {
+				info.Stats = storage.Stats{
+					Records:    st.Records,
+					Bytes:      st.Bytes,
+					GetRate:    st.GetRate,
+					PutRate:    st.PutRate,
+					DelRate:    st.DelRate,
+					LatencyP50: time.Duration(st.LatencyP50),
+					LatencyP99: time.Duration(st.LatencyP99),
+					Uptime:     time.Duration(st.Uptime),
+					Version:    st.Version,
+				}
+			}
+			infos = append(infos, info)
+		}
+		return nil, nil
+	})
+	return infos, err
+}
+
+// SetNodeState sets the administrative state of node on the leader router.
+func (c RouterClient) SetNodeState(addr, node storage.ServiceAddr, state router.NodeState) error {
+	log.Printf("SetNodeState request: node = %q, state = %v", node, state)
+	_, err := c.do(context.Background(), addr, func(ctx context.Context, client pb.RouterClient) ([]storage.ServiceAddr, error) {
+		req := pb.NodeStateRequest{
+			Node:  string(node),
+			State: int32(state),
+		}
+		reply, err := client.SetNodeState(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, replyError(reply.Status, reply.Leader, reply.Error)
+	})
+	return err
+}
+
+// SetNodeAddr changes the address of node to addr on the leader router.
+func (c RouterClient) SetNodeAddr(router, node, addr storage.ServiceAddr) error {
+	log.Printf("SetNodeAddr request: node = %q, addr = %q", node, addr)
+	_, err := c.do(context.Background(), router, func(ctx context.Context, client pb.RouterClient) ([]storage.ServiceAddr, error) {
+		req := pb.NodeAddrRequest{
+			Node: string(node),
+			Addr: string(addr),
+		}
+		reply, err := client.SetNodeAddr(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, replyError(reply.Status, reply.Leader, reply.Error)
+	})
+	return err
+}
+
+// ListPending returns nodes waiting for approval on the leader router.
+func (c RouterClient) ListPending(addr storage.ServiceAddr) ([]router.PendingNode, error) {
+	log.Printf("ListPending request")
+	var pending []router.PendingNode
+	_, err := c.do(context.Background(), addr, func(ctx context.Context, client pb.RouterClient) ([]storage.ServiceAddr, error) {
+		reply, err := client.ListPending(ctx, &pb.Empty{})
+		if err != nil {
+			return nil, err
+		}
+
+		if storage.StatusCode(reply.Status) != storage.StatusOk {
+			return nil, replyError(reply.Status, reply.Leader, reply.Error)
+		}
+		pending = make([]router.PendingNode, 0, len(reply.Nodes))
+		for _, p := range reply.Nodes {
+			pending = append(pending, router.PendingNode{
+				Node:         storage.ServiceAddr(p.Node),
+				Age:          time.Duration(p.Age),
+				HeartbeatAge: time.Duration(p.HeartbeatAge),
+				ID:           p.NodeId,
+			})
+		}
+		return nil, nil
+	})
+	return pending, err
+}
+
+// ApproveNode approves a pending node on the leader router.
+func (c RouterClient) ApproveNode(addr, node storage.ServiceAddr, rebalance bool) error {
+	log.Printf("ApproveNode request: node = %q, rebalance = %v", node, rebalance)
+	_, err := c.do(context.Background(), addr, func(ctx context.Context, client pb.RouterClient) ([]storage.ServiceAddr, error) {
+		req := pb.ApproveRequest{
+			Node:      string(node),
+			Rebalance: rebalance,
+		}
+		reply, err := client.ApproveNode(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, replyError(reply.Status, reply.Leader, reply.Error)
+	})
+	return err
+}
+
+// RejectNode rejects a pending node on the leader router.
+func (c RouterClient) RejectNode(addr, node storage.ServiceAddr) error {
+	log.Printf("RejectNode request: node = %q", node)
+	_, err := c.do(context.Background(), addr, func(ctx context.Context, client pb.RouterClient) ([]storage.ServiceAddr, error) {
+		reply, err := client.RejectNode(ctx, &pb.RejectRequest{Node: string(node)})
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, replyError(reply.Status, reply.Leader, reply.Error)
+	})
+	return err
+}
+
+// Config returns the effective configuration of the router at addr in YAML.
+// The request is not redirected to the leader.
+func (c RouterClient) Config(addr storage.ServiceAddr) ([]byte, error) {
+	log.Printf("Config request to %q", addr)
+	var config []byte
+	_, err := c.doOne(context.Background(), addr, func(ctx context.Context, client pb.RouterClient) ([]storage.ServiceAddr, error) {
+		reply, err := client.Config(ctx, &pb.Empty{})
+		if err != nil {
+			return nil, err
+		}
+		if err := replyError(reply.Status, "", reply.Error); err != nil {
+			return nil, err
+		}
+		config = reply.Config
+		return nil, nil
+	})
+	return config, err
+}
+
+// WatchTopology follows topology changes of the first available router.
+func (c RouterClient) WatchTopology(ctx context.Context, router storage.ServiceAddr, cb func(router.Topology)) error {
+	log.Printf("WatchTopology request")
+	var lastErr error
+	tried := make(map[storage.ServiceAddr]bool)
+	for _, addr := range c.candidates(router) {
+		if tried[addr] {
+			continue
+		}
+		tried[addr] = true
+
+		received, err := c.watch(ctx, addr, cb)
+		if received || ctx.Err() != nil {
+			return err
+		}
+		lastErr = err
+	}
+	return lastErr
+}
+
+// watch follows topology changes of the router at addr.
+// Returns whether at least one topology was received.
+func (c RouterClient) watch(ctx context.Context, addr storage.ServiceAddr, cb func(router.Topology)) (bool, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, storage.Timeout)
+	defer cancel()
+	conn, err := grpc.DialContext(dialCtx, string(addr), grpc.WithInsecure())
+	if err != nil {
+		return false, fmt.Errorf("Error dialing %q: %v", addr, err)
+	}
+	defer conn.Close()
+
+	stream, err := pb.NewRouterClient(conn).WatchTopology(ctx, &pb.Empty{})
+	if err != nil {
+		return false, err
+	}
+	received := false
+	for {
+		reply, err := stream.Recv()
+		if err != nil {
+			return received, err
+		}
+		if err := replyError(reply.Status, "", reply.Error); err != nil {
+			return received, err
+		}
+		received = true
+		cb(topologyFromPB(reply.Epoch, reply.Nodes, reply.Weights, reply.Domains, reply.States, reply.Ids))
+	}
+}
+
+// RequestVote asks peer to vote for candidate in the given term.
+func (c RouterClient) RequestVote(peer, candidate storage.ServiceAddr, term, epoch uint64) (uint64, bool, error) {
+	var (
+		replyTerm uint64
+		granted   bool
+	)
+	_, err := c.doOne(context.Background(), peer, func(ctx context.Context, client pb.RouterClient) ([]storage.ServiceAddr, error) {
+		req := pb.VoteRequest{
+			Term:      term,
+			Candidate: string(candidate),
+			Epoch:     epoch,
+		}
+		reply, err := client.RequestVote(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+		replyTerm, granted = reply.Term, reply.Granted
+		return nil, replyError(reply.Status, "", reply.Error)
+	})
+	return replyTerm, granted, err
+}
+
+// AppendState sends the leader state to peer.
+func (c RouterClient) AppendState(peer, leader storage.ServiceAddr, term uint64, state []byte) (uint64, bool, error) {
+	var (
+		replyTerm uint64
+		ok        bool
+	)
+	_, err := c.doOne(context.Background(), peer, func(ctx context.Context, client pb.RouterClient) ([]storage.ServiceAddr, error) {
+		req := pb.AppendRequest{
+			Term:   term,
+			Leader: string(leader),
+			State:  state,
+		}
+		reply, err := client.AppendState(ctx, &req)
+		if err != nil {
 			return nil, err
 		}
-		return nil, errors.New(reply.Error)
+		replyTerm, ok = reply.Term, reply.Ok
+		return nil, replyError(reply.Status, "", reply.Error)
 	})
+	return replyTerm, ok, err
 }
@@ -0,0 +1,314 @@
+package cluster
+
+import (
+	"encoding/json"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"router/router"
+	"storage"
+)
+
+// Transport is the common interface to send consensus requests to other routers.
+//
+// Transport -- общий интерфейс для отправки запросов консенсуса другим router.
+type Transport interface {
+	RequestVote(peer, candidate storage.ServiceAddr, term, epoch uint64) (uint64, bool, error)
+	AppendState(peer, leader storage.ServiceAddr, term uint64, state []byte) (uint64, bool, error)
+}
+
+// Replica is a state machine replicated by the Cluster.
+//
+// Replica -- реплицируемое Cluster состояние.
+type Replica interface {
+	State() router.State
+	SetState(st router.State)
+}
+
+// Config stores configuration for a Cluster.
+//
+// Config -- содержит конфигурацию Cluster.
+type Config struct {
+	// Addr is an address of this router.
+	// Addr -- адрес данного router.
+	Addr storage.ServiceAddr
+
+	// Peers is a list of other routers of the cluster.
+	// Peers -- список остальных router кластера.
+	Peers []storage.ServiceAddr
+
+	// ElectionTimeout is a timeout after which an election is started
+	// in absence of messages from a leader.
+	// ElectionTimeout -- если в течении ElectionTimeout не было сообщений
+	// от лидера, то начинаются выборы.
+	ElectionTimeout time.Duration
+
+	// Transport specifies a Transport to use.
+	// Transport -- Transport, который нужно использовать.
+	Transport Transport
+}
+
+// Cluster elects a leader among routers and replicates the leader's state
+// to the followers. It is a simplified Raft where the whole state is sent
+// instead of a log.
+//
+// Cluster выбирает лидера среди router и реплицирует состояние лидера
+// на остальные router. Это упрощенный Raft, в котором вместо лога
+// передается все состояние целиком.
+type Cluster struct {
+	sync.Mutex
+	cfg Config
+	rep Replica
+
+	term     uint64
+	votedFor storage.ServiceAddr
+	leader   storage.ServiceAddr
+
+	// lastContact is a time of the last message from the leader for followers
+	// and a time of the last acknowledgement from the majority for the leader.
+	lastContact time.Time
+	timeout     time.Duration
+
+	stop chan struct{}
+}
+
+// New creates a new Cluster with a given cfg replicating rep.
+// A Cluster without peers is a leader from the start.
+//
+// New создает новый Cluster с данным cfg, реплицирующий rep.
+// Cluster без peers сразу является лидером.
+func New(cfg Config, rep Replica) *Cluster {
+	c := &Cluster{
+		cfg:         cfg,
+		rep:         rep,
+		lastContact: time.Now(),
+		stop:        make(chan struct{}),
+	}
+	c.timeout = c.randomTimeout()
+	if len(cfg.Peers) == 0 {
+		c.leader = cfg.Addr
+	}
+	return c
+}
+
+func (c *Cluster) randomTimeout() time.Duration {
+	return c.cfg.ElectionTimeout + time.Duration(rand.Int63n(int64(c.cfg.ElectionTimeout)))
+}
+
+func (c *Cluster) quorum() int {
+	return (len(c.cfg.Peers)+1)/2 + 1
+}
+
+// Leader returns an address of the current leader, if known,
+// and whether this router is the leader.
+//
+// Leader возвращает адрес текущего лидера, если он известен,
+// и является ли данный router лидером.
+func (c *Cluster) Leader() (storage.ServiceAddr, bool) {
+	c.Lock()
+	defer c.Unlock()
+	return c.leader, c.leader == c.cfg.Addr
+}
+
+// Run starts the election and replication loop.
+//
+// Run запускает цикл выборов и репликации.
+func (c *Cluster) Run() {
+	if len(c.cfg.Peers) == 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(c.cfg.ElectionTimeout / 10)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+			}
+			c.Lock()
+			isLeader := c.leader == c.cfg.Addr
+			expired := time.Since(c.lastContact) > c.timeout
+			if isLeader && time.Since(c.lastContact) > c.cfg.ElectionTimeout {
+				log.Printf("Lost contact with the majority, stepping down in term %v", c.term)
+				c.leader = ""
+				isLeader = false
+			}
+			c.Unlock()
+
+			switch {
+			case isLeader:
+				c.replicate()
+			case expired:
+				c.elect()
+			}
+		}
+	}()
+}
+
+// Stop stops the election and replication loop.
+//
+// Stop останавливает цикл выборов и репликации.
+func (c *Cluster) Stop() {
+	close(c.stop)
+}
+
+// stepDown makes the router a follower in the given term.
+// Must be called with the lock held.
+func (c *Cluster) stepDown(term uint64) {
+	if term > c.term {
+		c.term = term
+		c.votedFor = ""
+	}
+	if c.leader == c.cfg.Addr {
+		c.leader = ""
+	}
+}
+
+// broadcast calls send for every peer concurrently and returns the number of
+// successful calls including this router once either quorum is reached or
+// all peers replied or the election timeout passed.
+func (c *Cluster) broadcast(send func(peer storage.ServiceAddr) (uint64, bool, error)) (int, uint64) {
+	type result struct {
+		term uint64
+		ok   bool
+	}
+	ch := make(chan result, len(c.cfg.Peers))
+	for _, peer := range c.cfg.Peers {
+		go func(peer storage.ServiceAddr) {
+			term, ok, err := send(peer)
+			if err != nil {
+				ok = false
+			}
+			ch <- result{term, ok}
+		}(peer)
+	}
+
+	acks := 1
+	var maxTerm uint64
+	deadline := time.After(c.cfg.ElectionTimeout / 2)
+	for range c.cfg.Peers {
+		select {
+		case res := <-ch:
+			if res.term > maxTerm {
+				maxTerm = res.term
+			}
+			if res.ok {
+				acks++
+			}
+		case <-deadline:
+			return acks, maxTerm
+		}
+		if acks >= c.quorum() {
+			return acks, maxTerm
+		}
+	}
+	return acks, maxTerm
+}
+
+func (c *Cluster) elect() {
+	c.Lock()
+	c.term++
+	term := c.term
+	c.votedFor = c.cfg.Addr
+	c.leader = ""
+	c.lastContact = time.Now()
+	c.timeout = c.randomTimeout()
+	c.Unlock()
+
+	log.Printf("Starting election in term %v", term)
+	epoch := c.rep.State().Epoch
+	votes, maxTerm := c.broadcast(func(peer storage.ServiceAddr) (uint64, bool, error) {
+		return c.cfg.Transport.RequestVote(peer, c.cfg.Addr, term, epoch)
+	})
+
+	c.Lock()
+	defer c.Unlock()
+	if maxTerm > c.term {
+		c.stepDown(maxTerm)
+		return
+	}
+	if c.term != term || votes < c.quorum() {
+		return
+	}
+	log.Printf("Elected as a leader in term %v", term)
+	c.leader = c.cfg.Addr
+	c.lastContact = time.Now()
+}
+
+func (c *Cluster) replicate() {
+	c.Lock()
+	term := c.term
+	c.Unlock()
+
+	state, err := json.Marshal(c.rep.State())
+	if err != nil {
+		log.Printf("Failed to marshal state: %v", err)
+		return
+	}
+	acks, maxTerm := c.broadcast(func(peer storage.ServiceAddr) (uint64, bool, error) {
+		return c.cfg.Transport.AppendState(peer, c.cfg.Addr, term, state)
+	})
+
+	c.Lock()
+	defer c.Unlock()
+	if maxTerm > c.term {
+		log.Printf("Found a newer term %v, stepping down", maxTerm)
+		c.stepDown(maxTerm)
+		return
+	}
+	if c.term == term && acks >= c.quorum() {
+		c.lastContact = time.Now()
+	}
+}
+
+// RequestVote handles a vote request from the candidate.
+// Returns the current term and whether the vote is granted.
+// The vote is not granted to a candidate with an older topology epoch.
+//
+// RequestVote обрабатывает запрос голоса от candidate.
+// Возвращает текущий term и отдан ли голос.
+// Голос не отдается candidate с более старой эпохой топологии.
+func (c *Cluster) RequestVote(term uint64, candidate storage.ServiceAddr, epoch uint64) (uint64, bool) {
+	c.Lock()
+	defer c.Unlock()
+	if term < c.term {
+		return c.term, false
+	}
+	if term > c.term {
+		c.stepDown(term)
+	}
+	if c.votedFor != "" && c.votedFor != candidate {
+		return c.term, false
+	}
+	if epoch < c.rep.State().Epoch {
+		return c.term, false
+	}
+	c.votedFor = candidate
+	c.lastContact = time.Now()
+	return c.term, true
+}
+
+// AppendState handles a state sent by the leader.
+// Returns the current term and whether the state is applied.
+//
+// AppendState обрабатывает состояние, отправленное лидером.
+// Возвращает текущий term и применено ли состояние.
+func (c *Cluster) AppendState(term uint64, leader storage.ServiceAddr, state []byte) (uint64, bool, error) {
+	c.Lock()
+	defer c.Unlock()
+	if term < c.term {
+		return c.term, false, nil
+	}
+	var st router.State
+	if err := json.Unmarshal(state, &st); err != nil {
+		return c.term, false, err
+	}
+	c.stepDown(term)
+	c.leader = leader
+	c.lastContact = time.Now()
+	c.rep.SetState(st)
+	return c.term, true, nil
+}
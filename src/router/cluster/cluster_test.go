@@ -0,0 +1,318 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"router/router"
+	"storage"
+)
+
+const electionTimeout = 100 * time.Millisecond
+
+var errDown = errors.New("router is down")
+
+type FakeTransport struct {
+	sync.Mutex
+	routers map[storage.ServiceAddr]*Cluster
+	down    map[storage.ServiceAddr]bool
+}
+
+func (tr *FakeTransport) get(from, to storage.ServiceAddr) (*Cluster, error) {
+	tr.Lock()
+	defer tr.Unlock()
+	if tr.down[from] || tr.down[to] {
+		return nil, errDown
+	}
+	return tr.routers[to], nil
+}
+
+func (tr *FakeTransport) setDown(addr storage.ServiceAddr, down bool) {
+	tr.Lock()
+	defer tr.Unlock()
+	tr.down[addr] = down
+}
+
+func (tr *FakeTransport) RequestVote(peer, candidate storage.ServiceAddr, term, epoch uint64) (uint64, bool, error) {
+	c, err := tr.get(candidate, peer)
+	if err != nil {
+		return 0, false, err
+	}
+	term, granted := c.RequestVote(term, candidate, epoch)
+	return term, granted, nil
+}
+
+func (tr *FakeTransport) AppendState(peer, leader storage.ServiceAddr, term uint64, state []byte) (uint64, bool, error) {
+	c, err := tr.get(leader, peer)
+	if err != nil {
+		return 0, false, err
+	}
+	return c.AppendState(term, leader, state)
+}
+
+type FakeReplica struct {
+	sync.Mutex
+	st router.State
+}
+
+func (r *FakeReplica) State() router.State {
+	r.Lock()
+	defer r.Unlock()
+	return r.st
+}
+
+func (r *FakeReplica) SetState(st router.State) {
+	r.Lock()
+	defer r.Unlock()
+	r.st = st
+}
+
+func startCluster(t *testing.T, n int) (*FakeTransport, []storage.ServiceAddr, map[storage.ServiceAddr]*FakeReplica) {
+	reps := make(map[storage.ServiceAddr]*FakeReplica)
+	tr, addrs := startReplicas(t, n, func(addr storage.ServiceAddr) Replica {
+		reps[addr] = new(FakeReplica)
+		return reps[addr]
+	})
+	return tr, addrs, reps
+}
+
+// startReplicas starts a cluster of n routers with replicas made by newReplica.
+func startReplicas(t *testing.T, n int, newReplica func(addr storage.ServiceAddr) Replica) (*FakeTransport, []storage.ServiceAddr) {
+	tr := &FakeTransport{
+		routers: make(map[storage.ServiceAddr]*Cluster),
+		down:    make(map[storage.ServiceAddr]bool),
+	}
+	var addrs []storage.ServiceAddr
+	for i := 0; i < n; i++ {
+		addrs = append(addrs, storage.ServiceAddr(fmt.Sprint("router", i)))
+	}
+	for i, addr := range addrs {
+		var peers []storage.ServiceAddr
+		peers = append(peers, addrs[:i]...)
+		peers = append(peers, addrs[i+1:]...)
+		tr.routers[addr] = New(Config{
+			Addr:            addr,
+			Peers:           peers,
+			ElectionTimeout: electionTimeout,
+			Transport:       tr,
+		}, newReplica(addr))
+	}
+	for _, c := range tr.routers {
+		c.Run()
+	}
+	return tr, addrs
+}
+
+func stopCluster(tr *FakeTransport) {
+	for _, c := range tr.routers {
+		c.Stop()
+	}
+}
+
+// waitLeader waits until all alive routers agree on a single leader.
+func waitLeader(t *testing.T, tr *FakeTransport, addrs []storage.ServiceAddr) storage.ServiceAddr {
+	deadline := time.Now().Add(20 * electionTimeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(electionTimeout / 10)
+		var leaders []storage.ServiceAddr
+		agreed := true
+		for _, addr := range addrs {
+			tr.Lock()
+			down := tr.down[addr]
+			tr.Unlock()
+			if down {
+				continue
+			}
+			leader, isLeader := tr.routers[addr].Leader()
+			if isLeader {
+				leaders = append(leaders, addr)
+			}
+			if leader == "" || (len(leaders) > 0 && leader != leaders[0]) {
+				agreed = false
+			}
+		}
+		if len(leaders) == 1 && agreed {
+			return leaders[0]
+		}
+	}
+	t.Fatalf("Leader was not elected in %v", 20*electionTimeout)
+	return ""
+}
+
+func TestStandalone(t *testing.T) {
+	c := New(Config{Addr: "router", ElectionTimeout: electionTimeout}, new(FakeReplica))
+	c.Run()
+	defer c.Stop()
+	if leader, ok := c.Leader(); !ok || leader != "router" {
+		t.Errorf("Leader() got %q, %v, want %q, true", leader, ok, "router")
+	}
+}
+
+func TestElection(t *testing.T) {
+	tr, addrs, _ := startCluster(t, 3)
+	defer stopCluster(tr)
+	leader := waitLeader(t, tr, addrs)
+
+	time.Sleep(5 * electionTimeout)
+	if got := waitLeader(t, tr, addrs); got != leader {
+		t.Errorf("Leader changed without failures: got %q, want %q", got, leader)
+	}
+}
+
+func TestFailover(t *testing.T) {
+	tr, addrs, _ := startCluster(t, 3)
+	defer stopCluster(tr)
+	leader := waitLeader(t, tr, addrs)
+
+	tr.setDown(leader, true)
+	newLeader := waitLeader(t, tr, addrs)
+	if newLeader == leader {
+		t.Fatalf("Leader %q is down but still elected", leader)
+	}
+
+	time.Sleep(2 * electionTimeout)
+	if _, ok := tr.routers[leader].Leader(); ok {
+		t.Errorf("Isolated router %q did not step down", leader)
+	}
+
+	tr.setDown(leader, false)
+	if got := waitLeader(t, tr, addrs); got == "" {
+		t.Errorf("No leader after the old leader returned")
+	}
+}
+
+func TestNoQuorum(t *testing.T) {
+	tr, addrs, _ := startCluster(t, 3)
+	defer stopCluster(tr)
+	leader := waitLeader(t, tr, addrs)
+
+	for _, addr := range addrs {
+		if addr != leader {
+			tr.setDown(addr, true)
+		}
+	}
+	time.Sleep(3 * electionTimeout)
+	for _, addr := range addrs {
+		if _, ok := tr.routers[addr].Leader(); ok {
+			t.Errorf("Router %q is a leader without quorum", addr)
+		}
+	}
+}
+
+func TestReplication(t *testing.T) {
+	tr, addrs, reps := startCluster(t, 3)
+	defer stopCluster(tr)
+	leader := waitLeader(t, tr, addrs)
+
+	reps[leader].SetState(router.State{
+		Topology:   router.Topology{Epoch: 1},
+		Heartbeats: map[storage.ServiceAddr]time.Duration{"node1": time.Second},
+	})
+	time.Sleep(electionTimeout)
+	for _, addr := range addrs {
+		st := reps[addr].State()
+		if st.Epoch != 1 || st.Heartbeats["node1"] != time.Second {
+			t.Errorf("State of %q was not replicated: %+v", addr, st)
+		}
+	}
+}
+
+func TestFailover_Pending(t *testing.T) {
+	routers := make(map[storage.ServiceAddr]*router.Router)
+	tr, addrs := startReplicas(t, 3, func(addr storage.ServiceAddr) Replica {
+		r, err := router.New(router.Config{
+			Addr:          addr,
+			Nodes:         []storage.ServiceAddr{"10.0.0.1:7321", "10.0.0.2:7322", "10.0.0.3:7323"},
+			ForgetTimeout: time.Hour,
+			Identities: map[storage.ServiceAddr]router.Identity{
+				"10.0.0.1:7321": {ID: "node1", Token: "secret1"},
+				"10.0.0.2:7322": {ID: "node2", Token: "secret2"},
+				"10.0.0.3:7323": {ID: "node3", Token: "secret3"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("router.New() error: %v", err)
+		}
+		routers[addr] = r
+		return r
+	})
+	defer stopCluster(tr)
+	leader := waitLeader(t, tr, addrs)
+
+	node4, id4 := storage.ServiceAddr("10.0.0.4:7324"), router.Identity{ID: "node4", Token: "secret4"}
+	node5, id5 := storage.ServiceAddr("10.0.0.5:7325"), router.Identity{ID: "node5", Token: "secret5"}
+	if err := routers[leader].Authenticate(node4, id4, "10.0.0.4"); err != nil {
+		t.Fatalf("Authenticate() error: %v", err)
+	}
+	if err := routers[leader].Authenticate(node5, id5, "10.0.0.5"); err != nil {
+		t.Fatalf("Authenticate() error: %v", err)
+	}
+	if err := routers[leader].RejectNode(node5); err != nil {
+		t.Fatalf("RejectNode() error: %v", err)
+	}
+	time.Sleep(2 * electionTimeout)
+
+	tr.setDown(leader, true)
+	newLeader := waitLeader(t, tr, addrs)
+	if newLeader == leader {
+		t.Fatalf("Leader %q is down but still elected", leader)
+	}
+	r := routers[newLeader]
+	if got := r.Pending(); len(got) != 1 || got[0].Node != node4 || got[0].ID != id4.ID {
+		t.Errorf("Pending() of the new leader got %+v, want %v with ID %v", got, node4, id4.ID)
+	}
+	// the rejected node stays rejected
+	if err := r.Heartbeat(node5); err != storage.ErrUnknownDaemon {
+		t.Errorf("Heartbeat() of a rejected node got error %v, want %v", err, storage.ErrUnknownDaemon)
+	}
+	if got := r.Pending(); len(got) != 1 {
+		t.Errorf("Pending() after a heartbeat of a rejected node got %+v", got)
+	}
+	for _, node := range []storage.ServiceAddr{node4, node5} {
+		if err := r.ApproveNode(node, true); err != nil {
+			t.Errorf("ApproveNode(%v) by the new leader error: %v", node, err)
+		}
+	}
+	// presented identities become identities of approved nodes
+	if err := r.Authenticate(node4, router.Identity{ID: "node4", Token: "wrong"}, "10.0.0.4"); err != storage.ErrUnauthenticated {
+		t.Errorf("Authenticate() with a wrong token got error %v, want %v", err, storage.ErrUnauthenticated)
+	}
+	if err := r.Authenticate(node5, id5, "10.0.0.5"); err != nil {
+		t.Errorf("Authenticate() of an approved rejected node error: %v", err)
+	}
+}
+
+func TestRequestVote(t *testing.T) {
+	rep := new(FakeReplica)
+	rep.SetState(router.State{Topology: router.Topology{Epoch: 2}})
+	c := New(Config{
+		Addr:            "router0",
+		Peers:           []storage.ServiceAddr{"router1", "router2"},
+		ElectionTimeout: electionTimeout,
+	}, rep)
+
+	for _, test := range []struct {
+		name      string
+		term      uint64
+		candidate storage.ServiceAddr
+		epoch     uint64
+		granted   bool
+	}{
+		{name: "stale_epoch", term: 1, candidate: "router1", epoch: 1},
+		{name: "granted", term: 1, candidate: "router1", epoch: 2, granted: true},
+		{name: "granted_again", term: 1, candidate: "router1", epoch: 2, granted: true},
+		{name: "already_voted", term: 1, candidate: "router2", epoch: 2},
+		{name: "new_term", term: 2, candidate: "router2", epoch: 3, granted: true},
+		{name: "old_term", term: 1, candidate: "router1", epoch: 3},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, granted := c.RequestVote(test.term, test.candidate, test.epoch)
+			if granted != test.granted {
+				t.Errorf("RequestVote() got %v, want %v", granted, test.granted)
+			}
+		})
+	}
+}
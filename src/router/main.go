@@ -3,12 +3,16 @@ package main
 import (
 	"fmt"
 	"log"
+	"net"
 	"os"
 
 	yaml "gopkg.in/yaml.v2"
 
+	"router/client"
+	"router/cluster"
 	"router/router"
 	"router/server"
+	"storage"
 )
 
 func usage() {
@@ -34,9 +38,61 @@ func parseConfig(fname string) (cfg router.Config, err error) {
 	if cfg.Nodes == nil {
 		return cfg, fmt.Errorf("Failed to parse config file %q: Nodes should be set", fname)
 	}
+	for node, w := range cfg.Weights {
+		if w <= 0 {
+			return cfg, fmt.Errorf("Failed to parse config file %q: weight of %q should be positive", fname, node)
+		}
+	}
+	if len(cfg.Domains) > 0 {
+		zones := make(map[string]bool)
+		for _, d := range cfg.Domains {
+			zones[d.Zone] = true
+		}
+		if len(zones) < storage.ReplicationFactor {
+			log.Printf("Only %v zones are configured, a zone outage may take out a quorum of replicas", len(zones))
+		}
+	}
+	idents := make(map[string]bool, len(cfg.Identities))
+	for node, id := range cfg.Identities {
+		if id.ID == "" || id.Token == "" {
+			return cfg, fmt.Errorf("Failed to parse config file %q: identity of %q should have ID and Token", fname, node)
+		}
+		if idents[id.ID] {
+			return cfg, fmt.Errorf("Failed to parse config file %q: identity %q is used by several nodes", fname, id.ID)
+		}
+		idents[id.ID] = true
+	}
+	// nodes without IDs are hashed on their addresses, so IDs must not
+	// collide with them either
+	ids := make(map[string]bool, len(cfg.Nodes))
+	for _, node := range cfg.Nodes {
+		id, ok := cfg.IDs[node]
+		if !ok {
+			id = string(node)
+		}
+		if id == "" || ids[id] {
+			return cfg, fmt.Errorf("Failed to parse config file %q: ID %q of %q should be non-empty and unique", fname, id, node)
+		}
+		ids[id] = true
+	}
+	if len(cfg.Identities) > 0 {
+		for _, node := range cfg.Nodes {
+			if _, ok := cfg.Identities[node]; !ok {
+				log.Printf("Node %q has no identity, its heartbeats will be rejected", node)
+			}
+		}
+	}
+	for _, cidr := range cfg.AutoApprove {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return cfg, fmt.Errorf("Failed to parse config file %q: AutoApprove: %v", fname, err)
+		}
+	}
 	if cfg.ForgetTimeout == 0 {
 		return cfg, fmt.Errorf("Failed to parse config file %q: ForgetTimeout should be set and be positive", fname)
 	}
+	if len(cfg.Peers) > 0 && cfg.ElectionTimeout <= 0 {
+		return cfg, fmt.Errorf("Failed to parse config file %q: ElectionTimeout should be set and be positive", fname)
+	}
 
 	return cfg, nil
 }
@@ -52,15 +108,45 @@ func main() {
 		log.Fatal(err)
 	}
 
-	hasher := router.NewMD5Hasher()
-	cfg.NodesFinder = router.NewNodesFinder(hasher)
+	cfg.NodesFinder, err = router.NewNodesFinderByName(cfg.Placement, cfg.Hasher)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg.Prober = storage.StorageClient{}
 
 	r, err := router.New(cfg)
 	if err != nil {
 		log.Fatalf("Failed to create router: %v", err)
 	}
+	r.Probes()
+
+	cl := cluster.New(cluster.Config{
+		Addr:            cfg.Addr,
+		Peers:           cfg.Peers,
+		ElectionTimeout: cfg.ElectionTimeout,
+		Transport:       client.RouterClient{},
+	}, r)
+	storage.WatchConfig(os.Args[1], func() {
+		cfg, err := parseConfig(os.Args[1])
+		if err != nil {
+			log.Printf("Config is not reloaded: %v", err)
+			return
+		}
+		// the topology is replicated from the leader
+		reload := r.Reload
+		if _, ok := cl.Leader(); !ok {
+			log.Printf("Not a leader, changes of nodes are left to the leader")
+			reload = r.ReloadFollower
+		}
+		if err := reload(cfg); err != nil {
+			log.Printf("Config is reloaded partially: %v", err)
+			return
+		}
+		log.Printf("Config is reloaded")
+	})
+	cl.Run()
 
-	srv := server.New(r, string(cfg.Addr))
+	srv := server.NewReplicated(r, cl, string(cfg.Addr))
 
 	if err := srv.ListenAndServe(); err != nil {
 		log.Fatal(err)
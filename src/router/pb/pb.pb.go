@@ -24,17 +24,20 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type HBRequest struct {
-	Node                 string   `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Node                 string     `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Stats                *NodeStats `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
+	NodeId               string     `protobuf:"bytes,3,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Token                string     `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *HBRequest) Reset()         { *m = HBRequest{} }
 func (m *HBRequest) String() string { return proto.CompactTextString(m) }
 func (*HBRequest) ProtoMessage()    {}
 func (*HBRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pb_fb5e298dfa49bbfc, []int{0}
+	return fileDescriptor_pb_534eac11167040e5, []int{0}
 }
 func (m *HBRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_HBRequest.Unmarshal(m, b)
@@ -61,9 +64,133 @@ func (m *HBRequest) GetNode() string {
 	return ""
 }
 
+func (m *HBRequest) GetStats() *NodeStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+func (m *HBRequest) GetNodeId() string {
+	if m != nil {
+		return m.NodeId
+	}
+	return ""
+}
+
+func (m *HBRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type NodeStats struct {
+	Records              uint64   `protobuf:"varint,1,opt,name=records,proto3" json:"records,omitempty"`
+	Bytes                uint64   `protobuf:"varint,2,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	GetRate              float64  `protobuf:"fixed64,3,opt,name=get_rate,json=getRate,proto3" json:"get_rate,omitempty"`
+	PutRate              float64  `protobuf:"fixed64,4,opt,name=put_rate,json=putRate,proto3" json:"put_rate,omitempty"`
+	DelRate              float64  `protobuf:"fixed64,5,opt,name=del_rate,json=delRate,proto3" json:"del_rate,omitempty"`
+	LatencyP50           int64    `protobuf:"varint,6,opt,name=latency_p50,json=latencyP50,proto3" json:"latency_p50,omitempty"`
+	LatencyP99           int64    `protobuf:"varint,7,opt,name=latency_p99,json=latencyP99,proto3" json:"latency_p99,omitempty"`
+	Uptime               int64    `protobuf:"varint,8,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	Version              string   `protobuf:"bytes,9,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeStats) Reset()         { *m = NodeStats{} }
+func (m *NodeStats) String() string { return proto.CompactTextString(m) }
+func (*NodeStats) ProtoMessage()    {}
+func (*NodeStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{1}
+}
+func (m *NodeStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeStats.Unmarshal(m, b)
+}
+func (m *NodeStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeStats.Marshal(b, m, deterministic)
+}
+func (dst *NodeStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeStats.Merge(dst, src)
+}
+func (m *NodeStats) XXX_Size() int {
+	return xxx_messageInfo_NodeStats.Size(m)
+}
+func (m *NodeStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeStats proto.InternalMessageInfo
+
+func (m *NodeStats) GetRecords() uint64 {
+	if m != nil {
+		return m.Records
+	}
+	return 0
+}
+
+func (m *NodeStats) GetBytes() uint64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+func (m *NodeStats) GetGetRate() float64 {
+	if m != nil {
+		return m.GetRate
+	}
+	return 0
+}
+
+func (m *NodeStats) GetPutRate() float64 {
+	if m != nil {
+		return m.PutRate
+	}
+	return 0
+}
+
+func (m *NodeStats) GetDelRate() float64 {
+	if m != nil {
+		return m.DelRate
+	}
+	return 0
+}
+
+func (m *NodeStats) GetLatencyP50() int64 {
+	if m != nil {
+		return m.LatencyP50
+	}
+	return 0
+}
+
+func (m *NodeStats) GetLatencyP99() int64 {
+	if m != nil {
+		return m.LatencyP99
+	}
+	return 0
+}
+
+func (m *NodeStats) GetUptime() int64 {
+	if m != nil {
+		return m.Uptime
+	}
+	return 0
+}
+
+func (m *NodeStats) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
 type HBReply struct {
 	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
 	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Leader               string   `protobuf:"bytes,3,opt,name=leader,proto3" json:"leader,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -73,7 +200,7 @@ func (m *HBReply) Reset()         { *m = HBReply{} }
 func (m *HBReply) String() string { return proto.CompactTextString(m) }
 func (*HBReply) ProtoMessage()    {}
 func (*HBReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pb_fb5e298dfa49bbfc, []int{1}
+	return fileDescriptor_pb_534eac11167040e5, []int{2}
 }
 func (m *HBReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_HBReply.Unmarshal(m, b)
@@ -107,6 +234,13 @@ func (m *HBReply) GetError() string {
 	return ""
 }
 
+func (m *HBReply) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
 type NFRequest struct {
 	Key                  uint32   `protobuf:"varint,1,opt,name=key,proto3" json:"key,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -118,7 +252,7 @@ func (m *NFRequest) Reset()         { *m = NFRequest{} }
 func (m *NFRequest) String() string { return proto.CompactTextString(m) }
 func (*NFRequest) ProtoMessage()    {}
 func (*NFRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pb_fb5e298dfa49bbfc, []int{2}
+	return fileDescriptor_pb_534eac11167040e5, []int{3}
 }
 func (m *NFRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NFRequest.Unmarshal(m, b)
@@ -149,6 +283,8 @@ type NFReply struct {
 	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
 	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	Nodes                []string `protobuf:"bytes,3,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Leader               string   `protobuf:"bytes,4,opt,name=leader,proto3" json:"leader,omitempty"`
+	Epoch                uint64   `protobuf:"varint,5,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -158,7 +294,7 @@ func (m *NFReply) Reset()         { *m = NFReply{} }
 func (m *NFReply) String() string { return proto.CompactTextString(m) }
 func (*NFReply) ProtoMessage()    {}
 func (*NFReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pb_fb5e298dfa49bbfc, []int{3}
+	return fileDescriptor_pb_534eac11167040e5, []int{4}
 }
 func (m *NFReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NFReply.Unmarshal(m, b)
@@ -199,6 +335,20 @@ func (m *NFReply) GetNodes() []string {
 	return nil
 }
 
+func (m *NFReply) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+func (m *NFReply) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
 type Empty struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -209,7 +359,7 @@ func (m *Empty) Reset()         { *m = Empty{} }
 func (m *Empty) String() string { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()    {}
 func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pb_fb5e298dfa49bbfc, []int{4}
+	return fileDescriptor_pb_534eac11167040e5, []int{5}
 }
 func (m *Empty) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Empty.Unmarshal(m, b)
@@ -230,19 +380,25 @@ func (m *Empty) XXX_DiscardUnknown() {
 var xxx_messageInfo_Empty proto.InternalMessageInfo
 
 type ListReply struct {
-	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
-	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
-	Nodes                []string `protobuf:"bytes,3,rep,name=nodes,proto3" json:"nodes,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Status               int32              `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string             `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Nodes                []string           `protobuf:"bytes,3,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Leader               string             `protobuf:"bytes,4,opt,name=leader,proto3" json:"leader,omitempty"`
+	Epoch                uint64             `protobuf:"varint,5,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Weights              map[string]float64 `protobuf:"bytes,6,rep,name=weights,proto3" json:"weights,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	Domains              map[string]*Domain `protobuf:"bytes,7,rep,name=domains,proto3" json:"domains,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	States               map[string]int32   `protobuf:"bytes,8,rep,name=states,proto3" json:"states,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Ids                  map[string]string  `protobuf:"bytes,9,rep,name=ids,proto3" json:"ids,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *ListReply) Reset()         { *m = ListReply{} }
 func (m *ListReply) String() string { return proto.CompactTextString(m) }
 func (*ListReply) ProtoMessage()    {}
 func (*ListReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pb_fb5e298dfa49bbfc, []int{5}
+	return fileDescriptor_pb_534eac11167040e5, []int{6}
 }
 func (m *ListReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListReply.Unmarshal(m, b)
@@ -283,170 +439,1855 @@ func (m *ListReply) GetNodes() []string {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*HBRequest)(nil), "HBRequest")
-	proto.RegisterType((*HBReply)(nil), "HBReply")
-	proto.RegisterType((*NFRequest)(nil), "NFRequest")
-	proto.RegisterType((*NFReply)(nil), "NFReply")
-	proto.RegisterType((*Empty)(nil), "Empty")
-	proto.RegisterType((*ListReply)(nil), "ListReply")
-}
-
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// RouterClient is the client API for Router service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type RouterClient interface {
-	Heartbeat(ctx context.Context, in *HBRequest, opts ...grpc.CallOption) (*HBReply, error)
-	NodesFind(ctx context.Context, in *NFRequest, opts ...grpc.CallOption) (*NFReply, error)
-	List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListReply, error)
+func (m *ListReply) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
 }
 
-type routerClient struct {
-	cc *grpc.ClientConn
+func (m *ListReply) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
 }
 
-func NewRouterClient(cc *grpc.ClientConn) RouterClient {
-	return &routerClient{cc}
+func (m *ListReply) GetWeights() map[string]float64 {
+	if m != nil {
+		return m.Weights
+	}
+	return nil
 }
 
-func (c *routerClient) Heartbeat(ctx context.Context, in *HBRequest, opts ...grpc.CallOption) (*HBReply, error) {
-	out := new(HBReply)
-	err := c.cc.Invoke(ctx, "/Router/Heartbeat", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ListReply) GetDomains() map[string]*Domain {
+	if m != nil {
+		return m.Domains
 	}
-	return out, nil
+	return nil
 }
 
-func (c *routerClient) NodesFind(ctx context.Context, in *NFRequest, opts ...grpc.CallOption) (*NFReply, error) {
-	out := new(NFReply)
-	err := c.cc.Invoke(ctx, "/Router/NodesFind", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ListReply) GetStates() map[string]int32 {
+	if m != nil {
+		return m.States
 	}
-	return out, nil
+	return nil
 }
 
-func (c *routerClient) List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListReply, error) {
-	out := new(ListReply)
-	err := c.cc.Invoke(ctx, "/Router/List", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ListReply) GetIds() map[string]string {
+	if m != nil {
+		return m.Ids
 	}
-	return out, nil
+	return nil
 }
 
-// RouterServer is the server API for Router service.
-type RouterServer interface {
-	Heartbeat(context.Context, *HBRequest) (*HBReply, error)
-	NodesFind(context.Context, *NFRequest) (*NFReply, error)
-	List(context.Context, *Empty) (*ListReply, error)
+type TopologyReply struct {
+	Status               int32              `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string             `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Epoch                uint64             `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Nodes                []string           `protobuf:"bytes,4,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Weights              map[string]float64 `protobuf:"bytes,5,rep,name=weights,proto3" json:"weights,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	Domains              map[string]*Domain `protobuf:"bytes,6,rep,name=domains,proto3" json:"domains,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	States               map[string]int32   `protobuf:"bytes,7,rep,name=states,proto3" json:"states,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Ids                  map[string]string  `protobuf:"bytes,8,rep,name=ids,proto3" json:"ids,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
-func RegisterRouterServer(s *grpc.Server, srv RouterServer) {
-	s.RegisterService(&_Router_serviceDesc, srv)
+func (m *TopologyReply) Reset()         { *m = TopologyReply{} }
+func (m *TopologyReply) String() string { return proto.CompactTextString(m) }
+func (*TopologyReply) ProtoMessage()    {}
+func (*TopologyReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{7}
+}
+func (m *TopologyReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopologyReply.Unmarshal(m, b)
+}
+func (m *TopologyReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopologyReply.Marshal(b, m, deterministic)
+}
+func (dst *TopologyReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopologyReply.Merge(dst, src)
+}
+func (m *TopologyReply) XXX_Size() int {
+	return xxx_messageInfo_TopologyReply.Size(m)
+}
+func (m *TopologyReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopologyReply.DiscardUnknown(m)
 }
 
-func _Router_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(HBRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(RouterServer).Heartbeat(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/Router/Heartbeat",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RouterServer).Heartbeat(ctx, req.(*HBRequest))
+var xxx_messageInfo_TopologyReply proto.InternalMessageInfo
+
+func (m *TopologyReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-func _Router_NodesFind_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NFRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(RouterServer).NodesFind(ctx, in)
+func (m *TopologyReply) GetError() string {
+	if m != nil {
+		return m.Error
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/Router/NodesFind",
+	return ""
+}
+
+func (m *TopologyReply) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RouterServer).NodesFind(ctx, req.(*NFRequest))
+	return 0
+}
+
+func (m *TopologyReply) GetNodes() []string {
+	if m != nil {
+		return m.Nodes
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _Router_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *TopologyReply) GetWeights() map[string]float64 {
+	if m != nil {
+		return m.Weights
 	}
-	if interceptor == nil {
-		return srv.(RouterServer).List(ctx, in)
+	return nil
+}
+
+func (m *TopologyReply) GetDomains() map[string]*Domain {
+	if m != nil {
+		return m.Domains
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/Router/List",
+	return nil
+}
+
+func (m *TopologyReply) GetStates() map[string]int32 {
+	if m != nil {
+		return m.States
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RouterServer).List(ctx, req.(*Empty))
+	return nil
+}
+
+func (m *TopologyReply) GetIds() map[string]string {
+	if m != nil {
+		return m.Ids
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-var _Router_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "Router",
-	HandlerType: (*RouterServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Heartbeat",
-			Handler:    _Router_Heartbeat_Handler,
-		},
-		{
-			MethodName: "NodesFind",
-			Handler:    _Router_NodesFind_Handler,
-		},
-		{
-			MethodName: "List",
-			Handler:    _Router_List_Handler,
+type Domain struct {
+	Zone                 string   `protobuf:"bytes,1,opt,name=zone,proto3" json:"zone,omitempty"`
+	Rack                 string   `protobuf:"bytes,2,opt,name=rack,proto3" json:"rack,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Domain) Reset()         { *m = Domain{} }
+func (m *Domain) String() string { return proto.CompactTextString(m) }
+func (*Domain) ProtoMessage()    {}
+func (*Domain) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{8}
+}
+func (m *Domain) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Domain.Unmarshal(m, b)
+}
+func (m *Domain) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Domain.Marshal(b, m, deterministic)
+}
+func (dst *Domain) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Domain.Merge(dst, src)
+}
+func (m *Domain) XXX_Size() int {
+	return xxx_messageInfo_Domain.Size(m)
+}
+func (m *Domain) XXX_DiscardUnknown() {
+	xxx_messageInfo_Domain.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Domain proto.InternalMessageInfo
+
+func (m *Domain) GetZone() string {
+	if m != nil {
+		return m.Zone
+	}
+	return ""
+}
+
+func (m *Domain) GetRack() string {
+	if m != nil {
+		return m.Rack
+	}
+	return ""
+}
+
+type NodeInfo struct {
+	Node                 string     `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	HeartbeatAge         int64      `protobuf:"varint,2,opt,name=heartbeat_age,json=heartbeatAge,proto3" json:"heartbeat_age,omitempty"`
+	NodeStatus           int32      `protobuf:"varint,3,opt,name=node_status,json=nodeStatus,proto3" json:"node_status,omitempty"`
+	Phi                  float64    `protobuf:"fixed64,4,opt,name=phi,proto3" json:"phi,omitempty"`
+	Stats                *NodeStats `protobuf:"bytes,5,opt,name=stats,proto3" json:"stats,omitempty"`
+	State                int32      `protobuf:"varint,6,opt,name=state,proto3" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *NodeInfo) Reset()         { *m = NodeInfo{} }
+func (m *NodeInfo) String() string { return proto.CompactTextString(m) }
+func (*NodeInfo) ProtoMessage()    {}
+func (*NodeInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{9}
+}
+func (m *NodeInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeInfo.Unmarshal(m, b)
+}
+func (m *NodeInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeInfo.Marshal(b, m, deterministic)
+}
+func (dst *NodeInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeInfo.Merge(dst, src)
+}
+func (m *NodeInfo) XXX_Size() int {
+	return xxx_messageInfo_NodeInfo.Size(m)
+}
+func (m *NodeInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeInfo proto.InternalMessageInfo
+
+func (m *NodeInfo) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *NodeInfo) GetHeartbeatAge() int64 {
+	if m != nil {
+		return m.HeartbeatAge
+	}
+	return 0
+}
+
+func (m *NodeInfo) GetNodeStatus() int32 {
+	if m != nil {
+		return m.NodeStatus
+	}
+	return 0
+}
+
+func (m *NodeInfo) GetPhi() float64 {
+	if m != nil {
+		return m.Phi
+	}
+	return 0
+}
+
+func (m *NodeInfo) GetStats() *NodeStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+func (m *NodeInfo) GetState() int32 {
+	if m != nil {
+		return m.State
+	}
+	return 0
+}
+
+type ClusterStatusReply struct {
+	Status               int32       `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string      `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Leader               string      `protobuf:"bytes,3,opt,name=leader,proto3" json:"leader,omitempty"`
+	Epoch                uint64      `protobuf:"varint,4,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Nodes                []*NodeInfo `protobuf:"bytes,5,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ClusterStatusReply) Reset()         { *m = ClusterStatusReply{} }
+func (m *ClusterStatusReply) String() string { return proto.CompactTextString(m) }
+func (*ClusterStatusReply) ProtoMessage()    {}
+func (*ClusterStatusReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{10}
+}
+func (m *ClusterStatusReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClusterStatusReply.Unmarshal(m, b)
+}
+func (m *ClusterStatusReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClusterStatusReply.Marshal(b, m, deterministic)
+}
+func (dst *ClusterStatusReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterStatusReply.Merge(dst, src)
+}
+func (m *ClusterStatusReply) XXX_Size() int {
+	return xxx_messageInfo_ClusterStatusReply.Size(m)
+}
+func (m *ClusterStatusReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterStatusReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterStatusReply proto.InternalMessageInfo
+
+func (m *ClusterStatusReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *ClusterStatusReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ClusterStatusReply) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+func (m *ClusterStatusReply) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *ClusterStatusReply) GetNodes() []*NodeInfo {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+type NodeStateRequest struct {
+	Node                 string   `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	State                int32    `protobuf:"varint,2,opt,name=state,proto3" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeStateRequest) Reset()         { *m = NodeStateRequest{} }
+func (m *NodeStateRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeStateRequest) ProtoMessage()    {}
+func (*NodeStateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{11}
+}
+func (m *NodeStateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeStateRequest.Unmarshal(m, b)
+}
+func (m *NodeStateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeStateRequest.Marshal(b, m, deterministic)
+}
+func (dst *NodeStateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeStateRequest.Merge(dst, src)
+}
+func (m *NodeStateRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeStateRequest.Size(m)
+}
+func (m *NodeStateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeStateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeStateRequest proto.InternalMessageInfo
+
+func (m *NodeStateRequest) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *NodeStateRequest) GetState() int32 {
+	if m != nil {
+		return m.State
+	}
+	return 0
+}
+
+type NodeStateReply struct {
+	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Leader               string   `protobuf:"bytes,3,opt,name=leader,proto3" json:"leader,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeStateReply) Reset()         { *m = NodeStateReply{} }
+func (m *NodeStateReply) String() string { return proto.CompactTextString(m) }
+func (*NodeStateReply) ProtoMessage()    {}
+func (*NodeStateReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{12}
+}
+func (m *NodeStateReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeStateReply.Unmarshal(m, b)
+}
+func (m *NodeStateReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeStateReply.Marshal(b, m, deterministic)
+}
+func (dst *NodeStateReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeStateReply.Merge(dst, src)
+}
+func (m *NodeStateReply) XXX_Size() int {
+	return xxx_messageInfo_NodeStateReply.Size(m)
+}
+func (m *NodeStateReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeStateReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeStateReply proto.InternalMessageInfo
+
+func (m *NodeStateReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *NodeStateReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *NodeStateReply) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+type NodeAddrRequest struct {
+	Node                 string   `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Addr                 string   `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeAddrRequest) Reset()         { *m = NodeAddrRequest{} }
+func (m *NodeAddrRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeAddrRequest) ProtoMessage()    {}
+func (*NodeAddrRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{13}
+}
+func (m *NodeAddrRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeAddrRequest.Unmarshal(m, b)
+}
+func (m *NodeAddrRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeAddrRequest.Marshal(b, m, deterministic)
+}
+func (dst *NodeAddrRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeAddrRequest.Merge(dst, src)
+}
+func (m *NodeAddrRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeAddrRequest.Size(m)
+}
+func (m *NodeAddrRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeAddrRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeAddrRequest proto.InternalMessageInfo
+
+func (m *NodeAddrRequest) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *NodeAddrRequest) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+type NodeAddrReply struct {
+	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Leader               string   `protobuf:"bytes,3,opt,name=leader,proto3" json:"leader,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeAddrReply) Reset()         { *m = NodeAddrReply{} }
+func (m *NodeAddrReply) String() string { return proto.CompactTextString(m) }
+func (*NodeAddrReply) ProtoMessage()    {}
+func (*NodeAddrReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{14}
+}
+func (m *NodeAddrReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeAddrReply.Unmarshal(m, b)
+}
+func (m *NodeAddrReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeAddrReply.Marshal(b, m, deterministic)
+}
+func (dst *NodeAddrReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeAddrReply.Merge(dst, src)
+}
+func (m *NodeAddrReply) XXX_Size() int {
+	return xxx_messageInfo_NodeAddrReply.Size(m)
+}
+func (m *NodeAddrReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeAddrReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeAddrReply proto.InternalMessageInfo
+
+func (m *NodeAddrReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *NodeAddrReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *NodeAddrReply) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+type ConfigReply struct {
+	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Config               []byte   `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConfigReply) Reset()         { *m = ConfigReply{} }
+func (m *ConfigReply) String() string { return proto.CompactTextString(m) }
+func (*ConfigReply) ProtoMessage()    {}
+func (*ConfigReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{15}
+}
+func (m *ConfigReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConfigReply.Unmarshal(m, b)
+}
+func (m *ConfigReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConfigReply.Marshal(b, m, deterministic)
+}
+func (dst *ConfigReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConfigReply.Merge(dst, src)
+}
+func (m *ConfigReply) XXX_Size() int {
+	return xxx_messageInfo_ConfigReply.Size(m)
+}
+func (m *ConfigReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConfigReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConfigReply proto.InternalMessageInfo
+
+func (m *ConfigReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *ConfigReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ConfigReply) GetConfig() []byte {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+type PendingNode struct {
+	Node                 string   `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Age                  int64    `protobuf:"varint,2,opt,name=age,proto3" json:"age,omitempty"`
+	HeartbeatAge         int64    `protobuf:"varint,3,opt,name=heartbeat_age,json=heartbeatAge,proto3" json:"heartbeat_age,omitempty"`
+	NodeId               string   `protobuf:"bytes,4,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PendingNode) Reset()         { *m = PendingNode{} }
+func (m *PendingNode) String() string { return proto.CompactTextString(m) }
+func (*PendingNode) ProtoMessage()    {}
+func (*PendingNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{16}
+}
+func (m *PendingNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PendingNode.Unmarshal(m, b)
+}
+func (m *PendingNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PendingNode.Marshal(b, m, deterministic)
+}
+func (dst *PendingNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PendingNode.Merge(dst, src)
+}
+func (m *PendingNode) XXX_Size() int {
+	return xxx_messageInfo_PendingNode.Size(m)
+}
+func (m *PendingNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_PendingNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PendingNode proto.InternalMessageInfo
+
+func (m *PendingNode) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *PendingNode) GetAge() int64 {
+	if m != nil {
+		return m.Age
+	}
+	return 0
+}
+
+func (m *PendingNode) GetHeartbeatAge() int64 {
+	if m != nil {
+		return m.HeartbeatAge
+	}
+	return 0
+}
+
+func (m *PendingNode) GetNodeId() string {
+	if m != nil {
+		return m.NodeId
+	}
+	return ""
+}
+
+type PendingReply struct {
+	Status               int32          `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string         `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Leader               string         `protobuf:"bytes,3,opt,name=leader,proto3" json:"leader,omitempty"`
+	Nodes                []*PendingNode `protobuf:"bytes,4,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *PendingReply) Reset()         { *m = PendingReply{} }
+func (m *PendingReply) String() string { return proto.CompactTextString(m) }
+func (*PendingReply) ProtoMessage()    {}
+func (*PendingReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{17}
+}
+func (m *PendingReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PendingReply.Unmarshal(m, b)
+}
+func (m *PendingReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PendingReply.Marshal(b, m, deterministic)
+}
+func (dst *PendingReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PendingReply.Merge(dst, src)
+}
+func (m *PendingReply) XXX_Size() int {
+	return xxx_messageInfo_PendingReply.Size(m)
+}
+func (m *PendingReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_PendingReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PendingReply proto.InternalMessageInfo
+
+func (m *PendingReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *PendingReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *PendingReply) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+func (m *PendingReply) GetNodes() []*PendingNode {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+type ApproveRequest struct {
+	Node                 string   `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Rebalance            bool     `protobuf:"varint,2,opt,name=rebalance,proto3" json:"rebalance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ApproveRequest) Reset()         { *m = ApproveRequest{} }
+func (m *ApproveRequest) String() string { return proto.CompactTextString(m) }
+func (*ApproveRequest) ProtoMessage()    {}
+func (*ApproveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{18}
+}
+func (m *ApproveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApproveRequest.Unmarshal(m, b)
+}
+func (m *ApproveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApproveRequest.Marshal(b, m, deterministic)
+}
+func (dst *ApproveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApproveRequest.Merge(dst, src)
+}
+func (m *ApproveRequest) XXX_Size() int {
+	return xxx_messageInfo_ApproveRequest.Size(m)
+}
+func (m *ApproveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApproveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApproveRequest proto.InternalMessageInfo
+
+func (m *ApproveRequest) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *ApproveRequest) GetRebalance() bool {
+	if m != nil {
+		return m.Rebalance
+	}
+	return false
+}
+
+type ApproveReply struct {
+	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Leader               string   `protobuf:"bytes,3,opt,name=leader,proto3" json:"leader,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ApproveReply) Reset()         { *m = ApproveReply{} }
+func (m *ApproveReply) String() string { return proto.CompactTextString(m) }
+func (*ApproveReply) ProtoMessage()    {}
+func (*ApproveReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{19}
+}
+func (m *ApproveReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApproveReply.Unmarshal(m, b)
+}
+func (m *ApproveReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApproveReply.Marshal(b, m, deterministic)
+}
+func (dst *ApproveReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApproveReply.Merge(dst, src)
+}
+func (m *ApproveReply) XXX_Size() int {
+	return xxx_messageInfo_ApproveReply.Size(m)
+}
+func (m *ApproveReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApproveReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApproveReply proto.InternalMessageInfo
+
+func (m *ApproveReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *ApproveReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ApproveReply) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+type RejectRequest struct {
+	Node                 string   `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RejectRequest) Reset()         { *m = RejectRequest{} }
+func (m *RejectRequest) String() string { return proto.CompactTextString(m) }
+func (*RejectRequest) ProtoMessage()    {}
+func (*RejectRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{20}
+}
+func (m *RejectRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RejectRequest.Unmarshal(m, b)
+}
+func (m *RejectRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RejectRequest.Marshal(b, m, deterministic)
+}
+func (dst *RejectRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RejectRequest.Merge(dst, src)
+}
+func (m *RejectRequest) XXX_Size() int {
+	return xxx_messageInfo_RejectRequest.Size(m)
+}
+func (m *RejectRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RejectRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RejectRequest proto.InternalMessageInfo
+
+func (m *RejectRequest) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+type RejectReply struct {
+	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Leader               string   `protobuf:"bytes,3,opt,name=leader,proto3" json:"leader,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RejectReply) Reset()         { *m = RejectReply{} }
+func (m *RejectReply) String() string { return proto.CompactTextString(m) }
+func (*RejectReply) ProtoMessage()    {}
+func (*RejectReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{21}
+}
+func (m *RejectReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RejectReply.Unmarshal(m, b)
+}
+func (m *RejectReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RejectReply.Marshal(b, m, deterministic)
+}
+func (dst *RejectReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RejectReply.Merge(dst, src)
+}
+func (m *RejectReply) XXX_Size() int {
+	return xxx_messageInfo_RejectReply.Size(m)
+}
+func (m *RejectReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RejectReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RejectReply proto.InternalMessageInfo
+
+func (m *RejectReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *RejectReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *RejectReply) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+type AliveReply struct {
+	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Leader               string   `protobuf:"bytes,3,opt,name=leader,proto3" json:"leader,omitempty"`
+	Epoch                uint64   `protobuf:"varint,4,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Nodes                []string `protobuf:"bytes,5,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AliveReply) Reset()         { *m = AliveReply{} }
+func (m *AliveReply) String() string { return proto.CompactTextString(m) }
+func (*AliveReply) ProtoMessage()    {}
+func (*AliveReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{22}
+}
+func (m *AliveReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AliveReply.Unmarshal(m, b)
+}
+func (m *AliveReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AliveReply.Marshal(b, m, deterministic)
+}
+func (dst *AliveReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AliveReply.Merge(dst, src)
+}
+func (m *AliveReply) XXX_Size() int {
+	return xxx_messageInfo_AliveReply.Size(m)
+}
+func (m *AliveReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_AliveReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AliveReply proto.InternalMessageInfo
+
+func (m *AliveReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *AliveReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *AliveReply) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+func (m *AliveReply) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *AliveReply) GetNodes() []string {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+type VoteRequest struct {
+	Term                 uint64   `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	Candidate            string   `protobuf:"bytes,2,opt,name=candidate,proto3" json:"candidate,omitempty"`
+	Epoch                uint64   `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VoteRequest) Reset()         { *m = VoteRequest{} }
+func (m *VoteRequest) String() string { return proto.CompactTextString(m) }
+func (*VoteRequest) ProtoMessage()    {}
+func (*VoteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{23}
+}
+func (m *VoteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VoteRequest.Unmarshal(m, b)
+}
+func (m *VoteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VoteRequest.Marshal(b, m, deterministic)
+}
+func (dst *VoteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VoteRequest.Merge(dst, src)
+}
+func (m *VoteRequest) XXX_Size() int {
+	return xxx_messageInfo_VoteRequest.Size(m)
+}
+func (m *VoteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VoteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VoteRequest proto.InternalMessageInfo
+
+func (m *VoteRequest) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *VoteRequest) GetCandidate() string {
+	if m != nil {
+		return m.Candidate
+	}
+	return ""
+}
+
+func (m *VoteRequest) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+type VoteReply struct {
+	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Term                 uint64   `protobuf:"varint,3,opt,name=term,proto3" json:"term,omitempty"`
+	Granted              bool     `protobuf:"varint,4,opt,name=granted,proto3" json:"granted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VoteReply) Reset()         { *m = VoteReply{} }
+func (m *VoteReply) String() string { return proto.CompactTextString(m) }
+func (*VoteReply) ProtoMessage()    {}
+func (*VoteReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{24}
+}
+func (m *VoteReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VoteReply.Unmarshal(m, b)
+}
+func (m *VoteReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VoteReply.Marshal(b, m, deterministic)
+}
+func (dst *VoteReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VoteReply.Merge(dst, src)
+}
+func (m *VoteReply) XXX_Size() int {
+	return xxx_messageInfo_VoteReply.Size(m)
+}
+func (m *VoteReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_VoteReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VoteReply proto.InternalMessageInfo
+
+func (m *VoteReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *VoteReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *VoteReply) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *VoteReply) GetGranted() bool {
+	if m != nil {
+		return m.Granted
+	}
+	return false
+}
+
+type AppendRequest struct {
+	Term                 uint64   `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	Leader               string   `protobuf:"bytes,2,opt,name=leader,proto3" json:"leader,omitempty"`
+	State                []byte   `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppendRequest) Reset()         { *m = AppendRequest{} }
+func (m *AppendRequest) String() string { return proto.CompactTextString(m) }
+func (*AppendRequest) ProtoMessage()    {}
+func (*AppendRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{25}
+}
+func (m *AppendRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppendRequest.Unmarshal(m, b)
+}
+func (m *AppendRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppendRequest.Marshal(b, m, deterministic)
+}
+func (dst *AppendRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppendRequest.Merge(dst, src)
+}
+func (m *AppendRequest) XXX_Size() int {
+	return xxx_messageInfo_AppendRequest.Size(m)
+}
+func (m *AppendRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppendRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppendRequest proto.InternalMessageInfo
+
+func (m *AppendRequest) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *AppendRequest) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+func (m *AppendRequest) GetState() []byte {
+	if m != nil {
+		return m.State
+	}
+	return nil
+}
+
+type AppendReply struct {
+	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Term                 uint64   `protobuf:"varint,3,opt,name=term,proto3" json:"term,omitempty"`
+	Ok                   bool     `protobuf:"varint,4,opt,name=ok,proto3" json:"ok,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppendReply) Reset()         { *m = AppendReply{} }
+func (m *AppendReply) String() string { return proto.CompactTextString(m) }
+func (*AppendReply) ProtoMessage()    {}
+func (*AppendReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_534eac11167040e5, []int{26}
+}
+func (m *AppendReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppendReply.Unmarshal(m, b)
+}
+func (m *AppendReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppendReply.Marshal(b, m, deterministic)
+}
+func (dst *AppendReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppendReply.Merge(dst, src)
+}
+func (m *AppendReply) XXX_Size() int {
+	return xxx_messageInfo_AppendReply.Size(m)
+}
+func (m *AppendReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppendReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppendReply proto.InternalMessageInfo
+
+func (m *AppendReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *AppendReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *AppendReply) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *AppendReply) GetOk() bool {
+	if m != nil {
+		return m.Ok
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*HBRequest)(nil), "HBRequest")
+	proto.RegisterType((*NodeStats)(nil), "NodeStats")
+	proto.RegisterType((*HBReply)(nil), "HBReply")
+	proto.RegisterType((*NFRequest)(nil), "NFRequest")
+	proto.RegisterType((*NFReply)(nil), "NFReply")
+	proto.RegisterType((*Empty)(nil), "Empty")
+	proto.RegisterType((*ListReply)(nil), "ListReply")
+	proto.RegisterMapType((map[string]*Domain)(nil), "ListReply.DomainsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "ListReply.IdsEntry")
+	proto.RegisterMapType((map[string]int32)(nil), "ListReply.StatesEntry")
+	proto.RegisterMapType((map[string]float64)(nil), "ListReply.WeightsEntry")
+	proto.RegisterType((*TopologyReply)(nil), "TopologyReply")
+	proto.RegisterMapType((map[string]*Domain)(nil), "TopologyReply.DomainsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "TopologyReply.IdsEntry")
+	proto.RegisterMapType((map[string]int32)(nil), "TopologyReply.StatesEntry")
+	proto.RegisterMapType((map[string]float64)(nil), "TopologyReply.WeightsEntry")
+	proto.RegisterType((*Domain)(nil), "Domain")
+	proto.RegisterType((*NodeInfo)(nil), "NodeInfo")
+	proto.RegisterType((*ClusterStatusReply)(nil), "ClusterStatusReply")
+	proto.RegisterType((*NodeStateRequest)(nil), "NodeStateRequest")
+	proto.RegisterType((*NodeStateReply)(nil), "NodeStateReply")
+	proto.RegisterType((*NodeAddrRequest)(nil), "NodeAddrRequest")
+	proto.RegisterType((*NodeAddrReply)(nil), "NodeAddrReply")
+	proto.RegisterType((*ConfigReply)(nil), "ConfigReply")
+	proto.RegisterType((*PendingNode)(nil), "PendingNode")
+	proto.RegisterType((*PendingReply)(nil), "PendingReply")
+	proto.RegisterType((*ApproveRequest)(nil), "ApproveRequest")
+	proto.RegisterType((*ApproveReply)(nil), "ApproveReply")
+	proto.RegisterType((*RejectRequest)(nil), "RejectRequest")
+	proto.RegisterType((*RejectReply)(nil), "RejectReply")
+	proto.RegisterType((*AliveReply)(nil), "AliveReply")
+	proto.RegisterType((*VoteRequest)(nil), "VoteRequest")
+	proto.RegisterType((*VoteReply)(nil), "VoteReply")
+	proto.RegisterType((*AppendRequest)(nil), "AppendRequest")
+	proto.RegisterType((*AppendReply)(nil), "AppendReply")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// RouterClient is the client API for Router service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RouterClient interface {
+	Heartbeat(ctx context.Context, in *HBRequest, opts ...grpc.CallOption) (*HBReply, error)
+	NodesFind(ctx context.Context, in *NFRequest, opts ...grpc.CallOption) (*NFReply, error)
+	List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListReply, error)
+	WatchTopology(ctx context.Context, in *Empty, opts ...grpc.CallOption) (Router_WatchTopologyClient, error)
+	ClusterStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ClusterStatusReply, error)
+	SetNodeState(ctx context.Context, in *NodeStateRequest, opts ...grpc.CallOption) (*NodeStateReply, error)
+	SetNodeAddr(ctx context.Context, in *NodeAddrRequest, opts ...grpc.CallOption) (*NodeAddrReply, error)
+	Config(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConfigReply, error)
+	ListPending(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PendingReply, error)
+	ApproveNode(ctx context.Context, in *ApproveRequest, opts ...grpc.CallOption) (*ApproveReply, error)
+	RejectNode(ctx context.Context, in *RejectRequest, opts ...grpc.CallOption) (*RejectReply, error)
+	ListAlive(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*AliveReply, error)
+	RequestVote(ctx context.Context, in *VoteRequest, opts ...grpc.CallOption) (*VoteReply, error)
+	AppendState(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*AppendReply, error)
+}
+
+type routerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRouterClient(cc *grpc.ClientConn) RouterClient {
+	return &routerClient{cc}
+}
+
+func (c *routerClient) Heartbeat(ctx context.Context, in *HBRequest, opts ...grpc.CallOption) (*HBReply, error) {
+	out := new(HBReply)
+	err := c.cc.Invoke(ctx, "/Router/Heartbeat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) NodesFind(ctx context.Context, in *NFRequest, opts ...grpc.CallOption) (*NFReply, error) {
+	out := new(NFReply)
+	err := c.cc.Invoke(ctx, "/Router/NodesFind", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListReply, error) {
+	out := new(ListReply)
+	err := c.cc.Invoke(ctx, "/Router/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) WatchTopology(ctx context.Context, in *Empty, opts ...grpc.CallOption) (Router_WatchTopologyClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Router_serviceDesc.Streams[0], "/Router/WatchTopology", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routerWatchTopologyClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Router_WatchTopologyClient interface {
+	Recv() (*TopologyReply, error)
+	grpc.ClientStream
+}
+
+type routerWatchTopologyClient struct {
+	grpc.ClientStream
+}
+
+func (x *routerWatchTopologyClient) Recv() (*TopologyReply, error) {
+	m := new(TopologyReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *routerClient) ClusterStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ClusterStatusReply, error) {
+	out := new(ClusterStatusReply)
+	err := c.cc.Invoke(ctx, "/Router/ClusterStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) SetNodeState(ctx context.Context, in *NodeStateRequest, opts ...grpc.CallOption) (*NodeStateReply, error) {
+	out := new(NodeStateReply)
+	err := c.cc.Invoke(ctx, "/Router/SetNodeState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) SetNodeAddr(ctx context.Context, in *NodeAddrRequest, opts ...grpc.CallOption) (*NodeAddrReply, error) {
+	out := new(NodeAddrReply)
+	err := c.cc.Invoke(ctx, "/Router/SetNodeAddr", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) Config(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConfigReply, error) {
+	out := new(ConfigReply)
+	err := c.cc.Invoke(ctx, "/Router/Config", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ListPending(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PendingReply, error) {
+	out := new(PendingReply)
+	err := c.cc.Invoke(ctx, "/Router/ListPending", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ApproveNode(ctx context.Context, in *ApproveRequest, opts ...grpc.CallOption) (*ApproveReply, error) {
+	out := new(ApproveReply)
+	err := c.cc.Invoke(ctx, "/Router/ApproveNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) RejectNode(ctx context.Context, in *RejectRequest, opts ...grpc.CallOption) (*RejectReply, error) {
+	out := new(RejectReply)
+	err := c.cc.Invoke(ctx, "/Router/RejectNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ListAlive(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*AliveReply, error) {
+	out := new(AliveReply)
+	err := c.cc.Invoke(ctx, "/Router/ListAlive", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) RequestVote(ctx context.Context, in *VoteRequest, opts ...grpc.CallOption) (*VoteReply, error) {
+	out := new(VoteReply)
+	err := c.cc.Invoke(ctx, "/Router/RequestVote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) AppendState(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*AppendReply, error) {
+	out := new(AppendReply)
+	err := c.cc.Invoke(ctx, "/Router/AppendState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RouterServer is the server API for Router service.
+type RouterServer interface {
+	Heartbeat(context.Context, *HBRequest) (*HBReply, error)
+	NodesFind(context.Context, *NFRequest) (*NFReply, error)
+	List(context.Context, *Empty) (*ListReply, error)
+	WatchTopology(*Empty, Router_WatchTopologyServer) error
+	ClusterStatus(context.Context, *Empty) (*ClusterStatusReply, error)
+	SetNodeState(context.Context, *NodeStateRequest) (*NodeStateReply, error)
+	SetNodeAddr(context.Context, *NodeAddrRequest) (*NodeAddrReply, error)
+	Config(context.Context, *Empty) (*ConfigReply, error)
+	ListPending(context.Context, *Empty) (*PendingReply, error)
+	ApproveNode(context.Context, *ApproveRequest) (*ApproveReply, error)
+	RejectNode(context.Context, *RejectRequest) (*RejectReply, error)
+	ListAlive(context.Context, *Empty) (*AliveReply, error)
+	RequestVote(context.Context, *VoteRequest) (*VoteReply, error)
+	AppendState(context.Context, *AppendRequest) (*AppendReply, error)
+}
+
+func RegisterRouterServer(s *grpc.Server, srv RouterServer) {
+	s.RegisterService(&_Router_serviceDesc, srv)
+}
+
+func _Router_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HBRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Router/Heartbeat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).Heartbeat(ctx, req.(*HBRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_NodesFind_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NFRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).NodesFind(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Router/NodesFind",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).NodesFind(ctx, req.(*NFRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Router/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).List(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_WatchTopology_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RouterServer).WatchTopology(m, &routerWatchTopologyServer{stream})
+}
+
+type Router_WatchTopologyServer interface {
+	Send(*TopologyReply) error
+	grpc.ServerStream
+}
+
+type routerWatchTopologyServer struct {
+	grpc.ServerStream
+}
+
+func (x *routerWatchTopologyServer) Send(m *TopologyReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Router_ClusterStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ClusterStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Router/ClusterStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ClusterStatus(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_SetNodeState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).SetNodeState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Router/SetNodeState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).SetNodeState(ctx, req.(*NodeStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_SetNodeAddr_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeAddrRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).SetNodeAddr(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Router/SetNodeAddr",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).SetNodeAddr(ctx, req.(*NodeAddrRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_Config_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).Config(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Router/Config",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).Config(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ListPending_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ListPending(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Router/ListPending",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ListPending(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ApproveNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ApproveNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Router/ApproveNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ApproveNode(ctx, req.(*ApproveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_RejectNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).RejectNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Router/RejectNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).RejectNode(ctx, req.(*RejectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ListAlive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ListAlive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Router/ListAlive",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ListAlive(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_RequestVote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).RequestVote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Router/RequestVote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).RequestVote(ctx, req.(*VoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_AppendState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).AppendState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Router/AppendState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).AppendState(ctx, req.(*AppendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Router_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "Router",
+	HandlerType: (*RouterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Heartbeat",
+			Handler:    _Router_Heartbeat_Handler,
+		},
+		{
+			MethodName: "NodesFind",
+			Handler:    _Router_NodesFind_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Router_List_Handler,
+		},
+		{
+			MethodName: "ClusterStatus",
+			Handler:    _Router_ClusterStatus_Handler,
+		},
+		{
+			MethodName: "SetNodeState",
+			Handler:    _Router_SetNodeState_Handler,
+		},
+		{
+			MethodName: "SetNodeAddr",
+			Handler:    _Router_SetNodeAddr_Handler,
+		},
+		{
+			MethodName: "Config",
+			Handler:    _Router_Config_Handler,
+		},
+		{
+			MethodName: "ListPending",
+			Handler:    _Router_ListPending_Handler,
+		},
+		{
+			MethodName: "ApproveNode",
+			Handler:    _Router_ApproveNode_Handler,
+		},
+		{
+			MethodName: "RejectNode",
+			Handler:    _Router_RejectNode_Handler,
+		},
+		{
+			MethodName: "ListAlive",
+			Handler:    _Router_ListAlive_Handler,
+		},
+		{
+			MethodName: "RequestVote",
+			Handler:    _Router_RequestVote_Handler,
+		},
+		{
+			MethodName: "AppendState",
+			Handler:    _Router_AppendState_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchTopology",
+			Handler:       _Router_WatchTopology_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pb.proto",
 }
 
-func init() { proto.RegisterFile("pb.proto", fileDescriptor_pb_fb5e298dfa49bbfc) }
-
-var fileDescriptor_pb_fb5e298dfa49bbfc = []byte{
-	// 237 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x51, 0x4d, 0x4b, 0xc4, 0x30,
-	0x10, 0xed, 0xda, 0xed, 0x47, 0x06, 0x04, 0x19, 0x44, 0x4a, 0x51, 0x5c, 0xc6, 0x8b, 0xa7, 0x1c,
-	0xf4, 0xe0, 0x5d, 0x70, 0xd9, 0x83, 0xae, 0x90, 0x7f, 0xd0, 0xb2, 0x39, 0x2c, 0x6a, 0x13, 0x93,
-	0xe9, 0xa1, 0xff, 0x5e, 0x26, 0xd6, 0xde, 0x85, 0xbd, 0xcd, 0x9b, 0xbc, 0xf7, 0x78, 0x79, 0x03,
-	0xb5, 0xef, 0xb5, 0x0f, 0x8e, 0x1d, 0xdd, 0x82, 0xda, 0x3d, 0x1b, 0xfb, 0x3d, 0xda, 0xc8, 0x88,
-	0xb0, 0x1e, 0xdc, 0xc1, 0x36, 0xab, 0xcd, 0xea, 0x5e, 0x99, 0x34, 0xd3, 0x13, 0x54, 0x42, 0xf0,
-	0x9f, 0x13, 0x5e, 0x41, 0x19, 0xb9, 0xe3, 0x31, 0x26, 0x42, 0x61, 0x66, 0x84, 0x97, 0x50, 0xd8,
-	0x10, 0x5c, 0x68, 0xce, 0x92, 0xee, 0x17, 0xd0, 0x0d, 0xa8, 0xfd, 0xf6, 0xcf, 0xf9, 0x02, 0xf2,
-	0x0f, 0x3b, 0x25, 0xdd, 0xb9, 0x91, 0x91, 0xde, 0xa0, 0x92, 0xe7, 0x7f, 0xfb, 0xca, 0x56, 0x82,
-	0xc5, 0x26, 0xdf, 0xe4, 0xb2, 0x4d, 0x80, 0x2a, 0x28, 0x5e, 0xbe, 0x3c, 0x4f, 0xf4, 0x0e, 0xea,
-	0xf5, 0x18, 0xf9, 0x64, 0xce, 0x0f, 0x1e, 0x4a, 0xe3, 0x46, 0xb6, 0x01, 0xef, 0x40, 0xed, 0x6c,
-	0x17, 0xb8, 0xb7, 0x1d, 0x23, 0xe8, 0xa5, 0xb7, 0xb6, 0xd6, 0x73, 0x45, 0x94, 0x09, 0x69, 0x2f,
-	0xba, 0xed, 0x71, 0x38, 0x20, 0xe8, 0xa5, 0x82, 0xb6, 0xd6, 0xf3, 0x7f, 0x29, 0xc3, 0x6b, 0x58,
-	0x4b, 0x48, 0x2c, 0x75, 0x0a, 0xdd, 0x82, 0x5e, 0x32, 0x53, 0xd6, 0x97, 0xe9, 0x34, 0x8f, 0x3f,
-	0x01, 0x00, 0x00, 0xff, 0xff, 0xce, 0x93, 0xe4, 0x6f, 0xa6, 0x01, 0x00, 0x00,
+func init() { proto.RegisterFile("pb.proto", fileDescriptor_pb_534eac11167040e5) }
+
+var fileDescriptor_pb_534eac11167040e5 = []byte{
+	// 1243 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x58, 0x5f, 0x6f, 0xdc, 0x44,
+	0x10, 0xf7, 0x9d, 0x7d, 0x7f, 0x3c, 0xf6, 0x5d, 0xc3, 0x36, 0x6a, 0x8d, 0x69, 0xd5, 0xd3, 0x46,
+	0xa0, 0x54, 0xc0, 0x92, 0x1e, 0x04, 0x71, 0x15, 0x2f, 0x69, 0x68, 0xd4, 0x48, 0x28, 0x14, 0xa7,
+	0x7f, 0x1e, 0x23, 0xe7, 0xbc, 0xbd, 0x98, 0x5c, 0x6c, 0xd7, 0xde, 0x0b, 0x1c, 0x48, 0x88, 0xaf,
+	0xc0, 0xd7, 0xe0, 0xa1, 0xdf, 0x88, 0xef, 0x82, 0xf6, 0x8f, 0x7d, 0xeb, 0xe4, 0x12, 0x20, 0x3a,
+	0x89, 0x17, 0xde, 0x76, 0x76, 0xe6, 0xb7, 0x3b, 0xb3, 0xfb, 0x9b, 0x99, 0xb5, 0xa1, 0x9b, 0x1d,
+	0x93, 0x2c, 0x4f, 0x59, 0x8a, 0x33, 0xb0, 0x9f, 0x3d, 0x09, 0xe8, 0xdb, 0x19, 0x2d, 0x18, 0x42,
+	0x60, 0x25, 0x69, 0x44, 0xbd, 0xc6, 0xa0, 0xb1, 0x69, 0x07, 0x62, 0x8c, 0x06, 0xd0, 0x2a, 0x58,
+	0xc8, 0x0a, 0xaf, 0x39, 0x68, 0x6c, 0x3a, 0x43, 0x20, 0x07, 0x69, 0x44, 0x0f, 0xf9, 0x4c, 0x20,
+	0x15, 0xe8, 0x2e, 0x74, 0xb8, 0xe5, 0x51, 0x1c, 0x79, 0xa6, 0x00, 0xb6, 0xb9, 0xb8, 0x1f, 0xa1,
+	0x75, 0x68, 0xb1, 0xf4, 0x94, 0x26, 0x9e, 0x25, 0xa6, 0xa5, 0x80, 0x7f, 0x6b, 0x82, 0x5d, 0xad,
+	0x81, 0x3c, 0xe8, 0xe4, 0x74, 0x9c, 0xe6, 0x51, 0x21, 0x76, 0xb5, 0x82, 0x52, 0xe4, 0xe8, 0xe3,
+	0x39, 0xa3, 0x72, 0x63, 0x2b, 0x90, 0x02, 0x7a, 0x1f, 0xba, 0x13, 0xca, 0x8e, 0xf2, 0x90, 0x51,
+	0xb1, 0x5b, 0x23, 0xe8, 0x4c, 0x28, 0x0b, 0x42, 0x46, 0xb9, 0x2a, 0x9b, 0x29, 0x95, 0x25, 0x55,
+	0xd9, 0xac, 0x52, 0x45, 0x74, 0x2a, 0x55, 0x2d, 0xa9, 0x8a, 0xe8, 0x54, 0xa8, 0x1e, 0x80, 0x33,
+	0x0d, 0x19, 0x4d, 0xc6, 0xf3, 0xa3, 0x6c, 0x7b, 0xcb, 0x6b, 0x0f, 0x1a, 0x9b, 0x66, 0x00, 0x6a,
+	0xea, 0xf9, 0xf6, 0x56, 0xcd, 0x60, 0x34, 0xf2, 0x3a, 0x75, 0x83, 0xd1, 0x08, 0xdd, 0x81, 0xf6,
+	0x2c, 0x63, 0xf1, 0x19, 0xf5, 0xba, 0x42, 0xa7, 0x24, 0x1e, 0xda, 0x39, 0xcd, 0x8b, 0x38, 0x4d,
+	0x3c, 0x5b, 0x1c, 0x40, 0x29, 0xe2, 0xef, 0xa0, 0xc3, 0x0f, 0x3d, 0x9b, 0xce, 0x39, 0x98, 0x9f,
+	0xe2, 0x4c, 0x86, 0xdf, 0x0a, 0x94, 0xc4, 0xa3, 0xa7, 0x79, 0x9e, 0xe6, 0x22, 0x7a, 0x3b, 0x90,
+	0x02, 0xb7, 0x9e, 0xd2, 0x30, 0xa2, 0x79, 0x79, 0xd2, 0x52, 0xc2, 0xf7, 0xc1, 0x3e, 0xd8, 0x2b,
+	0x6f, 0x71, 0x0d, 0xcc, 0x53, 0x3a, 0x17, 0xeb, 0xf5, 0x02, 0x3e, 0xc4, 0xbf, 0x40, 0xe7, 0x60,
+	0xef, 0x26, 0xfb, 0xad, 0x43, 0x8b, 0xdf, 0x65, 0xe1, 0x99, 0x03, 0x93, 0xcf, 0x0a, 0x41, 0xf3,
+	0xc2, 0xd2, 0xbd, 0x10, 0x6b, 0x64, 0xe9, 0xf8, 0x44, 0x1c, 0xb1, 0x15, 0x48, 0x01, 0x77, 0xa0,
+	0xf5, 0xf4, 0x2c, 0x63, 0x73, 0xfc, 0xce, 0x02, 0xfb, 0xdb, 0xb8, 0x60, 0xff, 0x91, 0x23, 0xe8,
+	0x11, 0x74, 0x7e, 0xa4, 0xf1, 0xe4, 0x84, 0x15, 0x5e, 0x7b, 0x60, 0x6e, 0x3a, 0xc3, 0xbb, 0xa4,
+	0x72, 0x87, 0xbc, 0x96, 0x9a, 0xa7, 0x09, 0xcb, 0xe7, 0x41, 0x69, 0xc7, 0x21, 0x51, 0x7a, 0x16,
+	0xc6, 0x49, 0xe1, 0x75, 0x2e, 0x41, 0xbe, 0x91, 0x1a, 0x05, 0x51, 0x76, 0x88, 0xc8, 0xb8, 0x68,
+	0xe1, 0x75, 0x05, 0xe2, 0x8e, 0x86, 0x38, 0x14, 0x0a, 0x09, 0x50, 0x56, 0xe8, 0x43, 0x30, 0xe3,
+	0xa8, 0xf0, 0x6c, 0x61, 0x7c, 0x5b, 0x33, 0xde, 0x8f, 0x94, 0x25, 0xd7, 0xfb, 0x8f, 0xc1, 0xd5,
+	0x5d, 0xd4, 0x2f, 0xd9, 0x16, 0x97, 0xcc, 0x83, 0x3e, 0x0f, 0xa7, 0x33, 0x2a, 0x0e, 0xae, 0x11,
+	0x48, 0xe1, 0x71, 0xf3, 0xab, 0x86, 0xbf, 0x0b, 0xae, 0xee, 0xeb, 0x12, 0xec, 0x7d, 0x1d, 0xeb,
+	0x0c, 0x3b, 0x2a, 0x36, 0x7d, 0x91, 0x11, 0x38, 0x9a, 0xfb, 0x7f, 0xb7, 0x7f, 0x4b, 0x87, 0x7e,
+	0x09, 0xdd, 0xfd, 0xe8, 0x9f, 0xe1, 0x6c, 0x0d, 0x87, 0xff, 0xb0, 0xa0, 0xf7, 0x22, 0xcd, 0xd2,
+	0x69, 0x3a, 0x99, 0xdf, 0x90, 0x34, 0x92, 0x06, 0xa6, 0x4e, 0x83, 0x8a, 0x4a, 0x96, 0x4e, 0xa5,
+	0xed, 0x05, 0x39, 0x5a, 0xe2, 0x2a, 0x3e, 0x20, 0xb5, 0xad, 0xaf, 0x20, 0xc8, 0xf6, 0x82, 0x20,
+	0xed, 0xa5, 0xb0, 0xe5, 0x24, 0x19, 0x56, 0x24, 0x91, 0xb4, 0xf2, 0x2f, 0xa0, 0x96, 0x11, 0xe5,
+	0xa1, 0x24, 0x4a, 0x57, 0xf1, 0xb0, 0x0e, 0xf8, 0x9f, 0x2c, 0x5b, 0xd0, 0x96, 0x7e, 0xf0, 0x2e,
+	0xf6, 0x73, 0x9a, 0x54, 0x5d, 0x8c, 0x8f, 0xf9, 0x5c, 0x1e, 0x8e, 0x4f, 0x15, 0x4c, 0x8c, 0xf1,
+	0xbb, 0x06, 0x74, 0x79, 0x23, 0xda, 0x4f, 0xde, 0xa4, 0x4b, 0x5b, 0xdf, 0x06, 0xf4, 0x4e, 0x68,
+	0x98, 0xb3, 0x63, 0x1a, 0xb2, 0xa3, 0x70, 0x22, 0x37, 0x35, 0x03, 0xb7, 0x9a, 0xdc, 0x99, 0x88,
+	0xfe, 0x21, 0xba, 0x9f, 0xe2, 0xa5, 0x29, 0xe2, 0x81, 0x44, 0x35, 0xb8, 0x59, 0xc1, 0x83, 0xc8,
+	0x4e, 0x62, 0xd5, 0x91, 0xf8, 0x70, 0xd1, 0x52, 0x5b, 0x57, 0xb5, 0xd4, 0x75, 0x69, 0x41, 0x45,
+	0x3b, 0x6a, 0xc9, 0x59, 0x8a, 0x7f, 0x6f, 0x00, 0xda, 0x9d, 0xce, 0x0a, 0x46, 0x73, 0xb9, 0xf6,
+	0x0a, 0x5b, 0xc8, 0x22, 0x59, 0x2c, 0x3d, 0x59, 0x1e, 0x94, 0xc9, 0x22, 0x93, 0xc2, 0x26, 0xe5,
+	0x81, 0xa9, 0xbc, 0xc1, 0x5f, 0xc3, 0x5a, 0xe9, 0x3d, 0xbd, 0xee, 0x19, 0x51, 0x45, 0xd4, 0xd4,
+	0x23, 0x7a, 0x05, 0x7d, 0x0d, 0xbd, 0xba, 0x7e, 0x38, 0x82, 0x5b, 0x7c, 0xdd, 0x9d, 0x28, 0xca,
+	0xaf, 0x73, 0x0a, 0x81, 0x15, 0x46, 0x51, 0xb9, 0xa6, 0x18, 0xe3, 0x97, 0xd0, 0x5b, 0x40, 0x57,
+	0xe7, 0xd1, 0x21, 0x38, 0xbb, 0x69, 0xf2, 0x26, 0x9e, 0xdc, 0x70, 0xd1, 0xb1, 0x00, 0x8b, 0x45,
+	0xdd, 0x40, 0x49, 0xf8, 0x2d, 0x38, 0xcf, 0x69, 0x12, 0xc5, 0xc9, 0xe4, 0x40, 0x85, 0x73, 0x29,
+	0xc4, 0x35, 0x30, 0x17, 0xcc, 0xe5, 0xc3, 0xcb, 0xac, 0x36, 0x97, 0xb0, 0x5a, 0x7b, 0xd3, 0x59,
+	0xfa, 0x9b, 0x0e, 0xff, 0x04, 0xae, 0xda, 0x72, 0x95, 0xe4, 0xc3, 0x7a, 0x4d, 0x76, 0x86, 0x2e,
+	0xd1, 0xc2, 0x2a, 0x99, 0xf6, 0x04, 0xfa, 0x3b, 0x59, 0x96, 0xa7, 0xe7, 0xd7, 0xf2, 0xec, 0x1e,
+	0xd8, 0x39, 0x3d, 0x0e, 0xa7, 0x61, 0x32, 0x96, 0x51, 0x77, 0x83, 0xc5, 0x04, 0x7e, 0x01, 0x6e,
+	0xb5, 0xc6, 0xea, 0xee, 0x76, 0x03, 0x7a, 0x01, 0xfd, 0x81, 0x8e, 0xd9, 0x35, 0x8e, 0x71, 0x02,
+	0x94, 0x46, 0xab, 0xdb, 0xf9, 0x57, 0x80, 0x9d, 0x69, 0xbc, 0xd2, 0x68, 0xae, 0x28, 0x04, 0xeb,
+	0x7a, 0x21, 0x28, 0xbb, 0x26, 0x7e, 0x09, 0xce, 0xab, 0xb4, 0x96, 0xf8, 0x8c, 0xe6, 0x67, 0xea,
+	0x25, 0x2f, 0xc6, 0xfc, 0x42, 0xc6, 0x61, 0x12, 0xc5, 0x51, 0x99, 0xfc, 0x76, 0xb0, 0x98, 0x58,
+	0xde, 0xa2, 0xf1, 0x04, 0x6c, 0xb9, 0xec, 0xbf, 0x8f, 0xaa, 0x74, 0xc1, 0xd4, 0x5c, 0xf0, 0xa0,
+	0x33, 0xc9, 0xc3, 0x84, 0x51, 0x49, 0xe6, 0x6e, 0x50, 0x8a, 0xf8, 0x7b, 0xe8, 0xed, 0x64, 0x19,
+	0x4d, 0xa2, 0xeb, 0x22, 0x58, 0x1c, 0x54, 0xf3, 0xe2, 0x41, 0xc9, 0x92, 0x26, 0x93, 0x52, 0x0a,
+	0xf8, 0x08, 0x9c, 0x72, 0xc9, 0xd5, 0x78, 0xdf, 0x87, 0x66, 0x7a, 0xaa, 0x1c, 0x6f, 0xa6, 0xa7,
+	0xc3, 0x3f, 0x2d, 0x68, 0x07, 0xe9, 0x8c, 0xd1, 0x1c, 0x6d, 0x80, 0xfd, 0xac, 0xcc, 0x5a, 0x04,
+	0xa4, 0xfa, 0x90, 0xf3, 0xbb, 0x44, 0x7d, 0x5f, 0x60, 0x83, 0x1b, 0xf1, 0x34, 0x2a, 0xf6, 0xe2,
+	0x24, 0x42, 0x40, 0xaa, 0xef, 0x04, 0xbf, 0x4b, 0xd4, 0x47, 0x01, 0x36, 0xd0, 0x3d, 0xb0, 0xf8,
+	0xcb, 0x13, 0xb5, 0x89, 0x78, 0xab, 0xfb, 0xb0, 0x78, 0x88, 0x62, 0x03, 0x7d, 0x0c, 0xbd, 0xd7,
+	0x21, 0x1b, 0x9f, 0x94, 0x6f, 0x8e, 0xca, 0xac, 0x5f, 0x7f, 0x86, 0x60, 0x63, 0xab, 0x81, 0x08,
+	0xf4, 0x6a, 0x4d, 0xaa, 0x32, 0xbe, 0x4d, 0x2e, 0x37, 0x2f, 0x6c, 0xa0, 0x2f, 0xc0, 0x3d, 0xa4,
+	0xac, 0x6a, 0x03, 0xe8, 0x3d, 0x72, 0xb1, 0xa1, 0xf8, 0xb7, 0x48, 0xbd, 0x4b, 0x60, 0x03, 0x3d,
+	0x02, 0x47, 0xa1, 0x78, 0xa5, 0x46, 0x6b, 0xe4, 0x42, 0xbd, 0xf7, 0xfb, 0xa4, 0x56, 0xc6, 0xb1,
+	0x81, 0x06, 0xd0, 0x96, 0x25, 0xb8, 0xf2, 0xc8, 0x25, 0x5a, 0x4d, 0xc6, 0x06, 0xfa, 0x08, 0x1c,
+	0x1e, 0xb6, 0x2a, 0x3e, 0x95, 0x59, 0x8f, 0xe8, 0x25, 0x0f, 0x1b, 0xe8, 0x33, 0x70, 0x54, 0x19,
+	0x11, 0x75, 0xf7, 0x16, 0xa9, 0x17, 0x26, 0xbf, 0x47, 0xf4, 0x2a, 0x83, 0x0d, 0xf4, 0x09, 0x80,
+	0x4c, 0x7e, 0x61, 0xdf, 0x27, 0xb5, 0x72, 0xe1, 0xbb, 0x44, 0xab, 0x0c, 0xd8, 0x40, 0x58, 0x7e,
+	0x27, 0x89, 0xcc, 0xae, 0x9c, 0x70, 0xc8, 0x22, 0xd3, 0xb1, 0x81, 0x1e, 0x82, 0xa3, 0xe0, 0x3c,
+	0x53, 0x90, 0x4b, 0xb4, 0x3c, 0xf4, 0x81, 0x54, 0xe9, 0x83, 0x0d, 0xf4, 0x69, 0xc9, 0x48, 0x79,
+	0xbe, 0x7d, 0x52, 0xa3, 0xbc, 0xef, 0x12, 0x8d, 0xaf, 0xd8, 0x38, 0x6e, 0x8b, 0x1f, 0x03, 0x9f,
+	0xff, 0x35, 0x00, 0x26, 0x35, 0xa7, 0x3d, 0x24, 0x10, 0x00, 0x00,
 }
@@ -0,0 +1,244 @@
+// Package plan simulates placement of records and computes which records
+// move when the set of nodes or the placement changes.
+//
+// Пакет plan моделирует размещение записей и вычисляет, какие записи
+// перемещаются при изменении множества node или стратегии размещения.
+package plan
+
+import (
+	"sort"
+
+	"router/router"
+	"storage"
+)
+
+// Placement is a NodesFinder together with a topology it places records on.
+//
+// Placement -- NodesFinder вместе с топологией, на которой он размещает записи.
+type Placement struct {
+	Finder   router.NodesFinder
+	Topology router.Topology
+}
+
+func (p Placement) find(k storage.RecordID) []storage.ServiceAddr {
+	return p.Finder.NodesFindTopology(k, p.Topology)
+}
+
+// NodeShare is an ownership share of a node.
+//
+// NodeShare -- доля записей, которыми владеет node.
+type NodeShare struct {
+	Node storage.ServiceAddr `json:"node"`
+	// Replicas is a number of records the node stores a replica of.
+	// Replicas -- количество записей, реплику которых хранит node.
+	Replicas uint64 `json:"replicas"`
+	// Primaries is a number of records the node is ranked first for.
+	// Primaries -- количество записей, для которых node первая по рангу.
+	Primaries uint64 `json:"primaries"`
+	// Share is a fraction of all replicas stored by the node.
+	// Share -- доля всех реплик, хранимых node.
+	Share float64 `json:"share"`
+}
+
+// Ownership describes how records are spread over nodes.
+//
+// Ownership описывает распределение записей по node.
+type Ownership struct {
+	Nodes []NodeShare `json:"nodes"`
+	// Skew is the largest share divided by the mean share.
+	// 1 means perfect balance.
+	// Skew -- наибольшая доля, деленная на среднюю.
+	// 1 означает идеальный баланс.
+	Skew float64 `json:"skew"`
+}
+
+// Move is a record whose set of replicas changes.
+//
+// Move -- запись, множество реплик которой меняется.
+type Move struct {
+	Key storage.RecordID      `json:"key"`
+	Old []storage.ServiceAddr `json:"old"`
+	New []storage.ServiceAddr `json:"new"`
+}
+
+// Transfer is a number of replicas to copy from one node to another.
+// Each new replica is copied from the first old replica of the record.
+//
+// Transfer -- количество реплик, которые нужно скопировать с одной node
+// на другую. Каждая новая реплика копируется с первой старой реплики записи.
+type Transfer struct {
+	From storage.ServiceAddr `json:"from"`
+	To   storage.ServiceAddr `json:"to"`
+	Keys uint64              `json:"keys"`
+}
+
+// Report is a result of a simulation.
+//
+// Report -- результат моделирования.
+type Report struct {
+	Keys uint64    `json:"keys"`
+	Old  Ownership `json:"old"`
+	New  Ownership `json:"new"`
+	// Changed is a number of records whose set of replicas changes.
+	// Changed -- количество записей, множество реплик которых меняется.
+	Changed         uint64     `json:"changed"`
+	ChangedFraction float64    `json:"changed_fraction"`
+	Transfers       []Transfer `json:"transfers"`
+	// Moves lists changed records if requested.
+	// Moves -- список изменившихся записей, если он был запрошен.
+	Moves []Move `json:"moves,omitempty"`
+}
+
+// Keys calls yield for each key to simulate.
+//
+// Keys вызывает yield для каждого моделируемого ключа.
+type Keys func(yield func(k storage.RecordID))
+
+// Range returns Keys from first to last inclusive.
+//
+// Range возвращает Keys от first до last включительно.
+func Range(first, last storage.RecordID) Keys {
+	return func(yield func(k storage.RecordID)) {
+		for k := first; ; k++ {
+			yield(k)
+			if k == last {
+				return
+			}
+		}
+	}
+}
+
+// Slice returns Keys of keys.
+//
+// Slice возвращает Keys из keys.
+func Slice(keys []storage.RecordID) Keys {
+	return func(yield func(k storage.RecordID)) {
+		for _, k := range keys {
+			yield(k)
+		}
+	}
+}
+
+type counter struct {
+	replicas  map[storage.ServiceAddr]uint64
+	primaries map[storage.ServiceAddr]uint64
+	total     uint64
+}
+
+func newCounter(t router.Topology) *counter {
+	c := &counter{
+		replicas:  make(map[storage.ServiceAddr]uint64, len(t.Nodes)),
+		primaries: make(map[storage.ServiceAddr]uint64, len(t.Nodes)),
+	}
+	for _, node := range t.Nodes {
+		c.replicas[node] = 0
+		c.primaries[node] = 0
+	}
+	return c
+}
+
+func (c *counter) add(nodes []storage.ServiceAddr) {
+	for i, node := range nodes {
+		if i == 0 {
+			c.primaries[node]++
+		}
+		c.replicas[node]++
+		c.total++
+	}
+}
+
+func (c *counter) ownership() Ownership {
+	var o Ownership
+	var max float64
+	for node, n := range c.replicas {
+		s := NodeShare{Node: node, Replicas: n, Primaries: c.primaries[node]}
+		if c.total > 0 {
+			s.Share = float64(n) / float64(c.total)
+		}
+		if s.Share > max {
+			max = s.Share
+		}
+		o.Nodes = append(o.Nodes, s)
+	}
+	sort.Slice(o.Nodes, func(i, j int) bool {
+		return o.Nodes[i].Node < o.Nodes[j].Node
+	})
+	if len(o.Nodes) > 0 {
+		o.Skew = max * float64(len(o.Nodes))
+	}
+	return o
+}
+
+func sameNodes(a, b []storage.ServiceAddr) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, node := range a {
+		if !contains(b, node) {
+			return false
+		}
+	}
+	return true
+}
+
+// Simulate places keys with both from and to placements and reports
+// ownership and changed records. Changed records are listed in Report.Moves
+// only if moves is true.
+//
+// Simulate размещает keys с помощью стратегий размещения from и to
+// и возвращает распределение записей и изменившиеся записи. Изменившиеся
+// записи перечисляются в Report.Moves, только если moves равен true.
+func Simulate(from, to Placement, keys Keys, moves bool) Report {
+	var r Report
+	oldCount, newCount := newCounter(from.Topology), newCounter(to.Topology)
+	type pair struct {
+		from, to storage.ServiceAddr
+	}
+	transfers := make(map[pair]uint64)
+
+	keys(func(k storage.RecordID) {
+		r.Keys++
+		o, n := from.find(k), to.find(k)
+		oldCount.add(o)
+		newCount.add(n)
+		if sameNodes(o, n) {
+			return
+		}
+		r.Changed++
+		if moves {
+			r.Moves = append(r.Moves, Move{Key: k, Old: o, New: n})
+		}
+		if len(o) == 0 {
+			return
+		}
+		for _, node := range n {
+			if !contains(o, node) {
+				transfers[pair{o[0], node}]++
+			}
+		}
+	})
+
+	r.Old, r.New = oldCount.ownership(), newCount.ownership()
+	if r.Keys > 0 {
+		r.ChangedFraction = float64(r.Changed) / float64(r.Keys)
+	}
+	for p, n := range transfers {
+		r.Transfers = append(r.Transfers, Transfer{From: p.from, To: p.to, Keys: n})
+	}
+	sort.Slice(r.Transfers, func(i, j int) bool {
+		if r.Transfers[i].From == r.Transfers[j].From {
+			return r.Transfers[i].To < r.Transfers[j].To
+		}
+		return r.Transfers[i].From < r.Transfers[j].From
+	})
+	return r
+}
+
+func contains(nodes []storage.ServiceAddr, node storage.ServiceAddr) bool {
+	for _, n := range nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
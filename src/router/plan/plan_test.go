@@ -0,0 +1,83 @@
+package plan
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"router/router"
+	"storage"
+)
+
+func placement(t *testing.T, n int, weights map[storage.ServiceAddr]float64) Placement {
+	nf, err := router.NewNodesFinderByName("", "")
+	if err != nil {
+		t.Fatalf("NewNodesFinderByName() error: %v", err)
+	}
+	p := Placement{Finder: nf, Topology: router.Topology{Weights: weights}}
+	for i := 0; i < n; i++ {
+		p.Topology.Nodes = append(p.Topology.Nodes, storage.ServiceAddr(fmt.Sprintf("node%d", i)))
+	}
+	return p
+}
+
+func TestSimulate_Same(t *testing.T) {
+	p := placement(t, 5, nil)
+	r := Simulate(p, p, Range(0, 9999), true)
+	if r.Keys != 10000 {
+		t.Errorf("Simulate() got %v keys, want 10000", r.Keys)
+	}
+	if r.Changed != 0 || len(r.Moves) != 0 || len(r.Transfers) != 0 {
+		t.Errorf("Simulate() of the same placement got %v changed keys", r.Changed)
+	}
+	var total float64
+	for _, s := range r.Old.Nodes {
+		total += s.Share
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("Shares sum to %v, want 1", total)
+	}
+	if r.Old.Skew < 1 || r.Old.Skew > 1.1 {
+		t.Errorf("Simulate() got skew %v, want close to 1", r.Old.Skew)
+	}
+}
+
+func TestSimulate_AddNode(t *testing.T) {
+	from, to := placement(t, 5, nil), placement(t, 6, nil)
+	r := Simulate(from, to, Range(0, 9999), true)
+	if r.Changed == 0 || r.Changed != uint64(len(r.Moves)) {
+		t.Fatalf("Simulate() got %v changed keys and %v moves", r.Changed, len(r.Moves))
+	}
+	// a new node takes about a half of keys with 3 replicas of 6 nodes
+	if r.ChangedFraction < 0.4 || r.ChangedFraction > 0.6 {
+		t.Errorf("Simulate() got changed fraction %v, want about 0.5", r.ChangedFraction)
+	}
+
+	var transferred uint64
+	for _, tr := range r.Transfers {
+		if tr.To != "node5" {
+			t.Errorf("Transfer %+v is not to the new node", tr)
+		}
+		transferred += tr.Keys
+	}
+	if transferred != r.Changed {
+		t.Errorf("Transfers carry %v keys, want %v", transferred, r.Changed)
+	}
+	for _, s := range r.New.Nodes {
+		if s.Node == "node5" && s.Replicas != r.Changed {
+			t.Errorf("New node got %v replicas, want %v", s.Replicas, r.Changed)
+		}
+	}
+}
+
+func TestSimulate_Weights(t *testing.T) {
+	from := placement(t, 4, nil)
+	to := placement(t, 4, map[storage.ServiceAddr]float64{"node0": 4})
+	r := Simulate(from, to, Slice([]storage.RecordID{1, 2, 3, 5, 8, 13, 21, 34}), false)
+	if r.Keys != 8 || len(r.Moves) != 0 {
+		t.Errorf("Simulate() got %v keys and %v moves, want 8 keys and no moves", r.Keys, len(r.Moves))
+	}
+	if r.New.Skew <= r.Old.Skew {
+		t.Errorf("Skew got %v -> %v, want it to grow", r.Old.Skew, r.New.Skew)
+	}
+}
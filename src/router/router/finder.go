@@ -3,7 +3,6 @@ package router
 import (
 	"crypto/md5"
 	"encoding/binary"
-	"sort"
 
 	"storage"
 )
@@ -15,63 +14,88 @@ type Hasher interface {
 	Hash(k storage.RecordID, node storage.ServiceAddr) uint64
 }
 
-const poolSize = 4096
-
 // MD5 implements Hasher interface computing hash base on md5 checksum.
 //
 // MD5 реализует интерфейс Hasher, вычисляя hash на основе контрольной суммы md5.
-type MD5 struct {
-	pool chan []byte
-}
+type MD5 struct{}
 
 // NewMD5Hasher create new MD5.
 func NewMD5Hasher() *MD5 {
-	return &MD5{
-		pool: make(chan []byte, poolSize),
-	}
+	return &MD5{}
 }
 
 func (h *MD5) Hash(k storage.RecordID, node storage.ServiceAddr) uint64 {
 	keySize := k.BinSize()
 	size := keySize + node.BinSize()
 
+	var arr [64]byte
 	var buf []byte
-	select {
-	case buf = <-h.pool:
-		if cap(buf) < size {
-			buf = make([]byte, size)
-		}
-	default:
+	if size <= len(arr) {
+		buf = arr[:size]
+	} else {
 		buf = make([]byte, size)
 	}
-
-	buf = buf[:size]
 	binary.LittleEndian.PutUint32(buf, uint32(k))
 	copy(buf[keySize:], node)
 	hash := md5.Sum(buf)
 
-	select {
-	case h.pool <- buf:
-	default:
-	}
-
 	return binary.LittleEndian.Uint64(hash[:8])
 }
 
+// Domain is a failure domain of a node. Nodes with empty Zone
+// share the same zone, nodes with empty Rack share the same rack.
+//
+// Domain -- домен отказа node. Node с пустой Zone находятся в одной
+// зоне, node с пустой Rack находятся в одной стойке.
+type Domain struct {
+	// Zone is an availability zone of a node.
+	// Zone -- зона доступности node.
+	Zone string
+	// Rack is a rack of a node within its zone.
+	// Rack -- стойка node внутри зоны.
+	Rack string
+}
+
+// Placement is the common interface of placement strategies.
+//
+// Placement -- общий интерфейс стратегий размещения.
+type Placement interface {
+	// Rank returns all nodes of t ordered by preference to store
+	// a record with key k. It must be deterministic.
+	// Rank возвращает все node из t, упорядоченные по предпочтительности
+	// хранения записи с ключом k. Должен быть детерминированным.
+	Rank(k storage.RecordID, t Topology) []storage.ServiceAddr
+}
+
+// topPlacement is implemented by placements which can find
+// the first n nodes of Rank faster than ranking all nodes.
+type topPlacement interface {
+	Top(k storage.RecordID, t Topology, n int) []storage.ServiceAddr
+}
+
 // NodesFinder contains methods and options to find nodes where
 // record with associated key shoud be stored.
 //
 // NodesFinder содержит методы и опции для нахождения узлов,
 // на которых должна храниться запись с данным ключом.
 type NodesFinder struct {
-	hasher Hasher
+	placement Placement
 }
 
-// NewNodesFinder creates NodesFinder instance with given Hasher.
+// NewNodesFinder creates NodesFinder instance using rendezvous hashing
+// with given Hasher.
 //
-// NewNodesFinder создает NodesFinder с данным Hasher.
+// NewNodesFinder создает NodesFinder, использующий rendezvous hashing
+// с данным Hasher.
 func NewNodesFinder(h Hasher) NodesFinder {
-	return NodesFinder{hasher: h}
+	return NodesFinder{placement: NewHRW(h)}
+}
+
+// NewPlacementFinder creates NodesFinder instance with given Placement.
+//
+// NewPlacementFinder создает NodesFinder с данной Placement.
+func NewPlacementFinder(p Placement) NodesFinder {
+	return NodesFinder{placement: p}
 }
 
 func min(a, b int) int {
@@ -89,26 +113,78 @@ func min(a, b int) int {
 // Возвращается не больше чем storage.ReplicationFactor nodes.
 // Возвращаемые nodes выбираются из передаваемых nodes.
 func (nf NodesFinder) NodesFind(k storage.RecordID, nodes []storage.ServiceAddr) []storage.ServiceAddr {
-	type pair struct {
-		hash uint64
-		Addr storage.ServiceAddr
+	return nf.NodesFindWeighted(k, nodes, nil)
+}
+
+// NodesFindWeighted is like NodesFind but a node gets a share of keys
+// proportional to its weight. Nodes missing in weights have weight 1.
+// All nodes are equal if weights is empty.
+//
+// NodesFindWeighted аналогичен NodesFind, но node получает долю ключей,
+// пропорциональную своему весу. Node, отсутствующие в weights, имеют вес 1.
+// Если weights пуст, то все node равноправны.
+func (nf NodesFinder) NodesFindWeighted(k storage.RecordID, nodes []storage.ServiceAddr, weights map[storage.ServiceAddr]float64) []storage.ServiceAddr {
+	return nf.NodesFindTopology(k, Topology{Nodes: nodes, Weights: weights})
+}
+
+// NodesFindTopology is like NodesFindWeighted but also spreads nodes over
+// failure domains of t: the top-ranked node of an unused zone is chosen
+// first, then the top-ranked node of an unused rack, and only then
+// a domain is repeated.
+//
+// NodesFindTopology аналогичен NodesFindWeighted, но также распределяет
+// nodes по доменам отказа t: сначала выбирается node с наибольшим рангом
+// из незанятой зоны, затем из незанятой стойки, и только потом домен
+// повторяется.
+// Draining nodes of t are placed as other nodes since they still hold
+// records, so the result is the placement for reads and deletes.
+// Joining nodes of t are never returned.
+// Draining node из t размещаются наравне с остальными, так как они еще
+// хранят записи, поэтому результат -- размещение для чтения и удаления.
+// Joining node из t никогда не возвращаются.
+func (nf NodesFinder) NodesFindTopology(k storage.RecordID, t Topology) []storage.ServiceAddr {
+	t = t.placed()
+	if len(t.Domains) == 0 {
+		if p, ok := nf.placement.(topPlacement); ok {
+			return p.Top(k, t, storage.ReplicationFactor)
+		}
 	}
+	ranked := nf.placement.Rank(k, t)
 
-	var hashes []pair
-	for _, node := range nodes {
-		hv := nf.hasher.Hash(k, node)
-		hashes = append(hashes, pair{hv, node})
+	n := min(storage.ReplicationFactor, len(ranked))
+	ret := make([]storage.ServiceAddr, 0, n)
+	if len(t.Domains) == 0 {
+		return append(ret, ranked[:n]...)
 	}
-	sort.Slice(hashes, func(i, j int) bool {
-		if hashes[i].hash == hashes[j].hash {
-			return hashes[i].Addr > hashes[j].Addr
-		}
-		return hashes[i].hash > hashes[j].hash
-	})
 
-	ret := make([]storage.ServiceAddr, 0, storage.ReplicationFactor)
-	for i := 0; i < min(storage.ReplicationFactor, len(hashes)); i++ {
-		ret = append(ret, hashes[i].Addr)
+	taken := make([]bool, len(ranked))
+	zones := make(map[string]bool)
+	racks := make(map[Domain]bool)
+	pass := func(skip func(d Domain) bool) {
+		for i := 0; i < len(ranked) && len(ret) < n; i++ {
+			d := t.Domains[ranked[i]]
+			if taken[i] || skip(d) {
+				continue
+			}
+			taken[i] = true
+			zones[d.Zone] = true
+			racks[d] = true
+			ret = append(ret, ranked[i])
+		}
 	}
+	pass(func(d Domain) bool { return zones[d.Zone] })
+	pass(func(d Domain) bool { return racks[d] })
+	pass(func(d Domain) bool { return false })
 	return ret
 }
+
+// NodesFindServing is like NodesFindTopology but places records as if
+// draining nodes of t were removed too, so the result is the placement
+// for writes.
+//
+// NodesFindServing аналогичен NodesFindTopology, но размещает записи так,
+// как будто draining node из t тоже удалены, поэтому результат --
+// размещение для записи.
+func (nf NodesFinder) NodesFindServing(k storage.RecordID, t Topology) []storage.ServiceAddr {
+	return nf.NodesFindTopology(k, t.Serving())
+}
@@ -1,6 +1,10 @@
 package router
 
 import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
 	"testing"
 
 	"storage"
@@ -39,6 +43,31 @@ func TestNodesFind(t *testing.T) {
 	}
 }
 
+func TestNodesFindServing(t *testing.T) {
+	hrw := NewNodesFinder(FakeHasher{
+		t: t,
+		hashes: map[storage.ServiceAddr]uint64{
+			"node1": 1,
+			"node2": 2,
+			"node3": 3,
+			"node4": 4,
+			"node5": 5,
+		},
+	})
+	topology := Topology{
+		Nodes:  []storage.ServiceAddr{"node1", "node2", "node3", "node4", "node5"},
+		States: map[storage.ServiceAddr]NodeState{"node4": NodeDraining, "node5": NodeJoining},
+	}
+	want := []storage.ServiceAddr{"node2", "node3", "node4"}
+	if got := hrw.NodesFindTopology(1, topology); !equalNodes(got, want) {
+		t.Errorf("NodesFindTopology() got %v, want %v", got, want)
+	}
+	want = []storage.ServiceAddr{"node1", "node2", "node3"}
+	if got := hrw.NodesFindServing(1, topology); !equalNodes(got, want) {
+		t.Errorf("NodesFindServing() got %v, want %v", got, want)
+	}
+}
+
 func TestNodes_SameHashes(t *testing.T) {
 	hrw := NewNodesFinder(FakeHasher{
 		t: t,
@@ -57,3 +86,204 @@ func TestNodes_SameHashes(t *testing.T) {
 		t.Errorf("NodesFind() wrong nodes, got %v, want %v", got, nodes[3:])
 	}
 }
+
+func TestNodesFindWeighted(t *testing.T) {
+	hrw := NewNodesFinder(FakeHasher{
+		t: t,
+		hashes: map[storage.ServiceAddr]uint64{
+			"node1": 4 << 60,
+			"node2": 3 << 60,
+			"node3": 2 << 60,
+			"node4": 1 << 60,
+		},
+	})
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3", "node4"}
+	if got := hrw.NodesFindWeighted(1, nodes, nil); !equalNodes(got, nodes[:3]) {
+		t.Errorf("NodesFindWeighted() without weights got %v, want %v", got, nodes[:3])
+	}
+	weights := map[storage.ServiceAddr]float64{"node4": 100}
+	want := []storage.ServiceAddr{"node4", "node1", "node2"}
+	if got := hrw.NodesFindWeighted(1, nodes, weights); !equalNodes(got, want) {
+		t.Errorf("NodesFindWeighted() got %v, want %v", got, want)
+	}
+}
+
+func TestNodesFindWeighted_Distribution(t *testing.T) {
+	const keys = 100000
+	hrw := NewNodesFinder(NewMD5Hasher())
+	weights := map[storage.ServiceAddr]float64{
+		"node1": 1,
+		"node2": 2,
+		"node3": 4,
+		"node5": 0.5,
+	}
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3", "node4", "node5"}
+	var total float64
+	for _, node := range nodes {
+		w, ok := weights[node]
+		if !ok {
+			w = 1
+		}
+		total += w
+	}
+
+	primary := make(map[storage.ServiceAddr]int)
+	for k := 0; k < keys; k++ {
+		got := hrw.NodesFindWeighted(storage.RecordID(k), nodes, weights)
+		primary[got[0]]++
+	}
+	for _, node := range nodes {
+		w, ok := weights[node]
+		if !ok {
+			w = 1
+		}
+		want := w / total
+		share := float64(primary[node]) / keys
+		if math.Abs(share-want) > 0.01 {
+			t.Errorf("Node %v got %.3f of keys, want %.3f", node, share, want)
+		}
+	}
+}
+
+func TestNodesFindTopology_Domains(t *testing.T) {
+	hrw := NewNodesFinder(FakeHasher{
+		t: t,
+		hashes: map[storage.ServiceAddr]uint64{
+			"node1": 6,
+			"node2": 5,
+			"node3": 4,
+			"node4": 3,
+			"node5": 2,
+			"node6": 1,
+		},
+	})
+	nodes := []storage.ServiceAddr{"node1", "node2", "node3", "node4", "node5", "node6"}
+	tests := []struct {
+		name    string
+		domains map[storage.ServiceAddr]Domain
+		want    []storage.ServiceAddr
+	}{
+		{
+			name: "distinct zones",
+			domains: map[storage.ServiceAddr]Domain{
+				"node1": {Zone: "a"}, "node2": {Zone: "a"}, "node3": {Zone: "b"},
+				"node4": {Zone: "b"}, "node5": {Zone: "c"}, "node6": {Zone: "c"},
+			},
+			want: []storage.ServiceAddr{"node1", "node3", "node5"},
+		},
+		{
+			name: "distinct racks",
+			domains: map[storage.ServiceAddr]Domain{
+				"node1": {"a", "1"}, "node2": {"a", "1"}, "node3": {"a", "2"},
+				"node4": {"b", "1"}, "node5": {"b", "1"}, "node6": {"b", "2"},
+			},
+			want: []storage.ServiceAddr{"node1", "node4", "node3"},
+		},
+		{
+			name: "repeated domain",
+			domains: map[storage.ServiceAddr]Domain{
+				"node1": {"a", "1"}, "node2": {"a", "1"}, "node3": {"a", "1"},
+				"node4": {"a", "1"}, "node5": {"a", "1"}, "node6": {"b", "1"},
+			},
+			want: []storage.ServiceAddr{"node1", "node6", "node2"},
+		},
+	}
+	for _, test := range tests {
+		got := hrw.NodesFindTopology(1, Topology{Nodes: nodes, Domains: test.domains})
+		if !equalNodes(got, test.want) {
+			t.Errorf("%s: NodesFindTopology() got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestNodesFindTopology_ZoneOutage(t *testing.T) {
+	hrw := NewNodesFinder(NewMD5Hasher())
+	topology := Topology{Domains: make(map[storage.ServiceAddr]Domain)}
+	for i := 0; i < 9; i++ {
+		node := storage.ServiceAddr(fmt.Sprintf("node%d", i))
+		topology.Nodes = append(topology.Nodes, node)
+		topology.Domains[node] = Domain{Zone: fmt.Sprintf("zone%d", i%3)}
+	}
+	for k := 0; k < 1000; k++ {
+		got := hrw.NodesFindTopology(storage.RecordID(k), topology)
+		zones := make(map[string]int)
+		for _, node := range got {
+			zones[topology.Domains[node].Zone]++
+		}
+		for zone, n := range zones {
+			if len(got)-n < storage.MinRedundancy {
+				t.Fatalf("Key %v: outage of %v leaves %v of replicas %v", k, zone, len(got)-n, got)
+			}
+		}
+	}
+}
+
+// nodesFindSort is the reference implementation of NodesFind
+// hashing the key against every node and sorting all of them.
+func nodesFindSort(h Hasher, k storage.RecordID, nodes []storage.ServiceAddr) []storage.ServiceAddr {
+	type pair struct {
+		hash uint64
+		Addr storage.ServiceAddr
+	}
+
+	var hashes []pair
+	for _, node := range nodes {
+		hashes = append(hashes, pair{h.Hash(k, node), node})
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		if hashes[i].hash == hashes[j].hash {
+			return hashes[i].Addr > hashes[j].Addr
+		}
+		return hashes[i].hash > hashes[j].hash
+	})
+
+	ret := make([]storage.ServiceAddr, 0, storage.ReplicationFactor)
+	for i := 0; i < min(storage.ReplicationFactor, len(hashes)); i++ {
+		ret = append(ret, hashes[i].Addr)
+	}
+	return ret
+}
+
+func TestNodesFind_Reference(t *testing.T) {
+	nodes := testNodes(100)
+	// few distinct hashes to exercise the tie-break by address
+	ties := FakeHasher{t: t, hashes: make(map[storage.ServiceAddr]uint64)}
+	for i, node := range nodes {
+		ties.hashes[node] = uint64(i % 4)
+	}
+	for _, h := range []Hasher{NewMD5Hasher(), FNV{}, ties} {
+		nf := NewNodesFinder(h)
+		for _, n := range []int{1, 2, 3, 10, 100} {
+			for k := storage.RecordID(0); k < 100; k++ {
+				got := nf.NodesFind(k, nodes[:n])
+				if want := nodesFindSort(h, k, nodes[:n]); !reflect.DeepEqual(got, want) {
+					t.Fatalf("NodesFind(%v) of %v nodes got %v, want %v", k, n, got, want)
+				}
+			}
+		}
+	}
+}
+
+func benchmarkNodesFind(b *testing.B, find func(k storage.RecordID, nodes []storage.ServiceAddr) []storage.ServiceAddr) {
+	for _, n := range []int{10, 100, 1000} {
+		nodes := testNodes(n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				find(storage.RecordID(i), nodes)
+			}
+		})
+	}
+}
+
+func BenchmarkNodesFind(b *testing.B) {
+	nf := NewNodesFinder(NewMD5Hasher())
+	benchmarkNodesFind(b, nf.NodesFind)
+}
+
+func BenchmarkNodesFind_Sort(b *testing.B) {
+	h := NewMD5Hasher()
+	benchmarkNodesFind(b, func(k storage.RecordID, nodes []storage.ServiceAddr) []storage.ServiceAddr {
+		return nodesFindSort(h, k, nodes)
+	})
+}
@@ -0,0 +1,117 @@
+package router
+
+import (
+	"encoding/binary"
+
+	"storage"
+)
+
+// FNV implements Hasher interface computing 64-bit FNV-1a hash of k and node.
+// It is much faster than MD5 but mixes bits worse.
+//
+// FNV реализует интерфейс Hasher, вычисляя 64-битный хеш FNV-1a от k и node.
+// Он намного быстрее MD5, но хуже перемешивает биты.
+type FNV struct{}
+
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+func (FNV) Hash(k storage.RecordID, node storage.ServiceAddr) uint64 {
+	h := uint64(fnvOffset)
+	for i := uint(0); i < 32; i += 8 {
+		h ^= uint64(byte(k >> i))
+		h *= fnvPrime
+	}
+	for i := 0; i < len(node); i++ {
+		h ^= uint64(node[i])
+		h *= fnvPrime
+	}
+	return h
+}
+
+// XXHash implements Hasher interface computing 64-bit xxHash of k and node.
+//
+// XXHash реализует интерфейс Hasher, вычисляя 64-битный xxHash от k и node.
+type XXHash struct{}
+
+func (XXHash) Hash(k storage.RecordID, node storage.ServiceAddr) uint64 {
+	var arr [64]byte
+	buf := arr[:0]
+	buf = append(buf, byte(k), byte(k>>8), byte(k>>16), byte(k>>24))
+	buf = append(buf, node...)
+	return xxh64(buf)
+}
+
+const (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+func rotl(x uint64, r uint) uint64 {
+	return x<<r | x>>(64-r)
+}
+
+func xxRound(acc, input uint64) uint64 {
+	acc += input * xxPrime2
+	acc = rotl(acc, 31)
+	return acc * xxPrime1
+}
+
+func xxMerge(acc, val uint64) uint64 {
+	acc ^= xxRound(0, val)
+	return acc*xxPrime1 + xxPrime4
+}
+
+// xxh64 computes xxHash64 of b with zero seed.
+func xxh64(b []byte) uint64 {
+	n := len(b)
+	var h uint64
+	if n >= 32 {
+		var seed uint64
+		v1 := seed + xxPrime1 + xxPrime2
+		v2 := seed + xxPrime2
+		v3 := seed
+		v4 := seed - xxPrime1
+		for ; len(b) >= 32; b = b[32:] {
+			v1 = xxRound(v1, binary.LittleEndian.Uint64(b[0:]))
+			v2 = xxRound(v2, binary.LittleEndian.Uint64(b[8:]))
+			v3 = xxRound(v3, binary.LittleEndian.Uint64(b[16:]))
+			v4 = xxRound(v4, binary.LittleEndian.Uint64(b[24:]))
+		}
+		h = rotl(v1, 1) + rotl(v2, 7) +
+			rotl(v3, 12) + rotl(v4, 18)
+		h = xxMerge(h, v1)
+		h = xxMerge(h, v2)
+		h = xxMerge(h, v3)
+		h = xxMerge(h, v4)
+	} else {
+		h = xxPrime5
+	}
+	h += uint64(n)
+
+	for ; len(b) >= 8; b = b[8:] {
+		h ^= xxRound(0, binary.LittleEndian.Uint64(b))
+		h = rotl(h, 27)*xxPrime1 + xxPrime4
+	}
+	if len(b) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(b)) * xxPrime1
+		h = rotl(h, 23)*xxPrime2 + xxPrime3
+		b = b[4:]
+	}
+	for _, c := range b {
+		h ^= uint64(c) * xxPrime5
+		h = rotl(h, 11) * xxPrime1
+	}
+
+	h ^= h >> 33
+	h *= xxPrime2
+	h ^= h >> 29
+	h *= xxPrime3
+	h ^= h >> 32
+	return h
+}
@@ -0,0 +1,43 @@
+package router
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"testing"
+
+	"storage"
+)
+
+func TestXXH64(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint64
+	}{
+		{"", 0xef46db3751d8e999},
+		{"a", 0xd24ec4f1a98c6e5b},
+		{"abc", 0x44bc2cf5ad770999},
+		{"Nobody inspects the spammish repetition", 0xfbcea83c8a378bf1},
+	}
+	for _, test := range tests {
+		if got := xxh64([]byte(test.in)); got != test.want {
+			t.Errorf("xxh64(%q) got %#x, want %#x", test.in, got, test.want)
+		}
+	}
+}
+
+func TestHashers(t *testing.T) {
+	k := storage.RecordID(0x01020304)
+	node := storage.ServiceAddr("127.0.0.1:7320")
+	buf := make([]byte, 4+len(node))
+	binary.LittleEndian.PutUint32(buf, uint32(k))
+	copy(buf[4:], node)
+
+	h := fnv.New64a()
+	h.Write(buf)
+	if got, want := (FNV{}).Hash(k, node), h.Sum64(); got != want {
+		t.Errorf("FNV.Hash() got %#x, want %#x", got, want)
+	}
+	if got, want := (XXHash{}).Hash(k, node), xxh64(buf); got != want {
+		t.Errorf("XXHash.Hash() got %#x, want %#x", got, want)
+	}
+}
@@ -0,0 +1,109 @@
+package router
+
+import (
+	"crypto/subtle"
+	"net"
+
+	"storage"
+)
+
+// Identity is an identity of a node: its ID and a secret token
+// the node proves it with.
+//
+// Identity -- идентичность node: ее ID и секретный токен,
+// которым node ее подтверждает.
+type Identity struct {
+	// ID is a name of the node.
+	// ID -- имя node.
+	ID string
+	// Token is a secret shared by the node and routers.
+	// Token -- секрет, общий для node и router.
+	Token string
+}
+
+// lookupHost resolves host names of node addresses.
+var lookupHost = net.LookupHost
+
+// Authenticate checks that a heartbeat of node carrying id was sent from
+// the host from. The check passes if Config.Identities is empty.
+// Identities follow address changes made with SetNodeAddr.
+// A node which is neither served nor has an identity passes with any id
+// not used by other nodes. It is registered as pending approval, see
+// Pending, and id becomes its identity once it is approved.
+// Returns storage.ErrUnauthenticated error if id is not the identity of
+// node or from is not the host of the node address.
+//
+// Authenticate проверяет, что heartbeat node, содержащий id, был послан
+// с хоста from. Проверка проходит, если Config.Identities пусто.
+// Идентичности следуют за изменениями адресов через SetNodeAddr.
+// Node, которая не обслуживается и не имеет идентичности, проходит
+// проверку с любым id, не используемым другими node. Она регистрируется
+// как ожидающая одобрения, см. Pending, и id становится ее
+// идентичностью после одобрения.
+// Возвращает ошибку storage.ErrUnauthenticated, если id не является
+// идентичностью node или from не является хостом из адреса node.
+func (r *Router) Authenticate(node storage.ServiceAddr, id Identity, from string) error {
+	r.RLock()
+	enabled := len(r.idents) > 0
+	want, ok := r.idents[node]
+	_, served := r.lastHB[node]
+	used := r.identUsed(id.ID)
+	r.RUnlock()
+	if !enabled {
+		return nil
+	}
+	if !ok && !served {
+		if id.ID == "" || id.Token == "" || used || !sameHost(node, from) {
+			return storage.ErrUnauthenticated
+		}
+		r.Lock()
+		r.register(node, id, from)
+		r.Unlock()
+		return nil
+	}
+	if !ok || id.ID != want.ID ||
+		subtle.ConstantTimeCompare([]byte(id.Token), []byte(want.Token)) != 1 {
+		return storage.ErrUnauthenticated
+	}
+	if !sameHost(node, from) {
+		return storage.ErrUnauthenticated
+	}
+	return nil
+}
+
+// identUsed reports whether id is the ID of an identity of a node.
+// Must be called with the lock held.
+func (r *Router) identUsed(id string) bool {
+	for _, ident := range r.idents {
+		if ident.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// sameHost reports whether the host of the node address resolves to
+// the IP address from.
+func sameHost(node storage.ServiceAddr, from string) bool {
+	ip := net.ParseIP(from)
+	if ip == nil {
+		return false
+	}
+	host, _, err := net.SplitHostPort(string(node))
+	if err != nil {
+		return false
+	}
+	if nodeIP := net.ParseIP(host); nodeIP != nil {
+		return nodeIP.Equal(ip)
+	}
+	addrs, err := lookupHost(host)
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if a := net.ParseIP(addr); a != nil && a.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package router
+
+import (
+	"fmt"
+
+	"storage"
+)
+
+// key returns the value placement hashes node on: its stable ID
+// or its address if the node has no ID.
+func (t Topology) key(node storage.ServiceAddr) storage.ServiceAddr {
+	if id, ok := t.IDs[node]; ok {
+		return storage.ServiceAddr(id)
+	}
+	return node
+}
+
+func copyIDs(ids map[storage.ServiceAddr]string) map[storage.ServiceAddr]string {
+	if len(ids) == 0 {
+		return nil
+	}
+	ret := make(map[storage.ServiceAddr]string, len(ids))
+	for node, id := range ids {
+		ret[node] = id
+	}
+	return ret
+}
+
+func copyIdentities(idents map[storage.ServiceAddr]Identity) map[storage.ServiceAddr]Identity {
+	if len(idents) == 0 {
+		return nil
+	}
+	ret := make(map[storage.ServiceAddr]Identity, len(idents))
+	for node, id := range idents {
+		ret[node] = id
+	}
+	return ret
+}
+
+// SetNodeAddr changes the address of node to addr and increments
+// the topology epoch. The node keeps its ID, so its keys do not move.
+// A node without an ID gets its old address as the ID.
+// Returns storage.ErrUnknownDaemon error if node is not served by the Router.
+//
+// SetNodeAddr меняет адрес node на addr и увеличивает эпоху. Node
+// сохраняет свой ID, поэтому ее ключи не перемещаются. Node без ID
+// получает в качестве ID свой старый адрес.
+// Возвращает ошибку storage.ErrUnknownDaemon если node не
+// обслуживается Router.
+func (r *Router) SetNodeAddr(node, addr storage.ServiceAddr) error {
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.lastHB[node]; !ok {
+		return storage.ErrUnknownDaemon
+	}
+	if node == addr {
+		return nil
+	}
+	if _, ok := r.lastHB[addr]; ok {
+		return fmt.Errorf("Address %q is already served", addr)
+	}
+	if _, ok := r.ids[node]; !ok {
+		if r.ids == nil {
+			r.ids = make(map[storage.ServiceAddr]string)
+		}
+		r.ids[node] = string(node)
+	}
+	r.rename(node, addr)
+	r.epoch++
+	r.notify()
+	return nil
+}
+
+// rename moves everything known about the node at old to addr.
+// Must be called with the lock held.
+func (r *Router) rename(old, addr storage.ServiceAddr) {
+	for i, node := range r.nodes {
+		if node == old {
+			r.nodes[i] = addr
+		}
+	}
+	if v, ok := r.weights[old]; ok {
+		delete(r.weights, old)
+		r.weights[addr] = v
+	}
+	if v, ok := r.domains[old]; ok {
+		delete(r.domains, old)
+		r.domains[addr] = v
+	}
+	if v, ok := r.states[old]; ok {
+		delete(r.states, old)
+		r.states[addr] = v
+	}
+	if v, ok := r.ids[old]; ok {
+		delete(r.ids, old)
+		r.ids[addr] = v
+	}
+	if v, ok := r.idents[old]; ok {
+		delete(r.idents, old)
+		r.idents[addr] = v
+	}
+	if v, ok := r.lastHB[old]; ok {
+		delete(r.lastHB, old)
+		r.lastHB[addr] = v
+	}
+	if v, ok := r.phi[old]; ok {
+		delete(r.phi, old)
+		r.phi[addr] = v
+	}
+	if v, ok := r.probes[old]; ok {
+		delete(r.probes, old)
+		r.probes[addr] = v
+	}
+	if v, ok := r.stats[old]; ok {
+		delete(r.stats, old)
+		r.stats[addr] = v
+	}
+}
+
+// readdress renames nodes whose IDs have new addresses in t, so that
+// node state which is not replicated follows address changes made by
+// the leader.
+// Must be called with the lock held.
+func (r *Router) readdress(t Topology) {
+	addrs := make(map[string]storage.ServiceAddr, len(t.IDs))
+	for node, id := range t.IDs {
+		addrs[id] = node
+	}
+	for _, node := range append([]storage.ServiceAddr(nil), r.nodes...) {
+		id, ok := r.ids[node]
+		if !ok {
+			// a node without an ID got its address as the ID when moved
+			id = string(node)
+		}
+		addr, ok := addrs[id]
+		if !ok || addr == node {
+			continue
+		}
+		if _, ok := r.lastHB[addr]; ok {
+			continue
+		}
+		r.rename(node, addr)
+	}
+}
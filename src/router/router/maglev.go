@@ -0,0 +1,100 @@
+package router
+
+import (
+	"sort"
+
+	"storage"
+)
+
+// DefaultMaglevSize is a default size of a Maglev lookup table.
+// It must be a prime number much larger than the number of nodes.
+//
+// DefaultMaglevSize -- размер таблицы Maglev по умолчанию. Должен быть
+// простым числом, много большим количества node.
+const DefaultMaglevSize = 65537
+
+// Maglev is a Placement based on Maglev hashing. Nodes fill a lookup table
+// in turns, each following its own permutation of table entries. A key is
+// served by nodes met walking the table from the hash of the key.
+//
+// Maglev -- Placement на основе Maglev hashing. Node по очереди заполняют
+// таблицу, каждая в порядке своей перестановки ячеек. Ключ обслуживается
+// node, которые встречаются при обходе таблицы от хеша ключа.
+type Maglev struct {
+	hasher Hasher
+	size   uint64
+	cache  *tableCache
+}
+
+// NewMaglev creates Maglev with given Hasher and lookup table size.
+//
+// NewMaglev создает Maglev с данным Hasher и размером таблицы.
+func NewMaglev(h Hasher, size uint64) Maglev {
+	if size == 0 {
+		size = DefaultMaglevSize
+	}
+	return Maglev{hasher: h, size: size, cache: new(tableCache)}
+}
+
+func (p Maglev) build(t Topology) []storage.ServiceAddr {
+	nodes := append([]storage.ServiceAddr(nil), t.Nodes...)
+	sort.Slice(nodes, func(i, j int) bool { return t.key(nodes[i]) < t.key(nodes[j]) })
+	n := len(nodes)
+	if n == 0 {
+		return nil
+	}
+
+	offset := make([]uint64, n)
+	skip := make([]uint64, n)
+	next := make([]uint64, n)
+	credit := make([]float64, n)
+	var maxWeight float64
+	for i, node := range nodes {
+		key := t.key(node)
+		offset[i] = p.hasher.Hash(0, key) % p.size
+		skip[i] = p.hasher.Hash(1, key)%(p.size-1) + 1
+		if w := weight(t.Weights, node); w > maxWeight {
+			maxWeight = w
+		}
+	}
+
+	table := make([]storage.ServiceAddr, p.size)
+	filled := uint64(0)
+	for filled < p.size {
+		for i, node := range nodes {
+			// a node takes turns in proportion to its weight
+			credit[i] += weight(t.Weights, node) / maxWeight
+			for credit[i] >= 1 && filled < p.size {
+				credit[i]--
+				for {
+					c := (offset[i] + next[i]*skip[i]) % p.size
+					next[i]++
+					if table[c] == "" {
+						table[c] = node
+						filled++
+						break
+					}
+				}
+			}
+		}
+	}
+	return table
+}
+
+func (p Maglev) Rank(k storage.RecordID, t Topology) []storage.ServiceAddr {
+	return p.Top(k, t, len(t.Nodes))
+}
+
+// Top is like Rank but returns only the first n nodes.
+//
+// Top аналогичен Rank, но возвращает только первые n node.
+func (p Maglev) Top(k storage.RecordID, t Topology, n int) []storage.ServiceAddr {
+	table := p.cache.get(t, func() interface{} { return p.build(t) }).([]storage.ServiceAddr)
+	if len(table) == 0 {
+		return nil
+	}
+	start := p.hasher.Hash(k, "") % p.size
+	return distinct(min(n, len(t.Nodes)), len(table), func(i int) storage.ServiceAddr {
+		return table[(start+uint64(i))%p.size]
+	})
+}
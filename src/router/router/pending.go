@@ -0,0 +1,228 @@
+package router
+
+import (
+	"log"
+	"net"
+	"sort"
+	"time"
+
+	"storage"
+)
+
+// MaxPending is a maximum number of nodes pending approval. Heartbeats of
+// other unknown nodes are not registered.
+//
+// MaxPending -- максимальное количество node, ожидающих одобрения.
+// Heartbeats остальных неизвестных node не регистрируются.
+const MaxPending = 1024
+
+// PendingNode is an unknown node which sends heartbeats and waits
+// for approval.
+//
+// PendingNode -- неизвестная node, которая посылает heartbeats
+// и ожидает одобрения.
+type PendingNode struct {
+	// Node is an address of the node.
+	// Node -- адрес node.
+	Node storage.ServiceAddr
+
+	// Age is time passed since the first heartbeat of the node.
+	// Age -- время, прошедшее с первого heartbeat node.
+	Age time.Duration
+
+	// HeartbeatAge is time passed since the last heartbeat of the node.
+	// HeartbeatAge -- время, прошедшее с последнего heartbeat node.
+	HeartbeatAge time.Duration
+
+	// ID is the ID of the identity presented by the node, see
+	// Router.Authenticate. It is empty if no identity was presented.
+	// ID -- ID идентичности, предъявленной node, см. Router.Authenticate.
+	// Пуст, если идентичность не предъявлялась.
+	ID string
+}
+
+type pendingNode struct {
+	first, last time.Time
+	ident       Identity
+}
+
+// register registers an unknown node whose heartbeat was sent from
+// the IP address from as pending approval unless it was rejected.
+// The identity id presented by the node, if set, replaces the one
+// presented before. Returns whether the node was approved automatically.
+// Must be called with the lock held.
+func (r *Router) register(node storage.ServiceAddr, id Identity, from string) bool {
+	if _, ok := r.rejected[node]; ok {
+		if id.ID != "" {
+			r.rejected[node] = id
+		}
+		return false
+	}
+	p, ok := r.pending[node]
+	if id.ID != "" {
+		p.ident = id
+	}
+	tNow := r.clock.Now()
+	if autoApproved(r.cfg.AutoApprove, node, from) {
+		log.Printf("Node %q is approved automatically", node)
+		r.approve(node, r.cfg.AutoRebalance, p.ident)
+		return true
+	}
+	if !ok {
+		if len(r.pending) >= MaxPending {
+			return false
+		}
+		log.Printf("Node %q is pending approval", node)
+		p.first = tNow
+	}
+	p.last = tNow
+	r.pending[node] = p
+	return false
+}
+
+// autoApproved reports whether the IP address from a heartbeat was sent
+// from is in one of the ranges and is the host of the node address.
+// The address a node claims is not trusted on its own.
+func autoApproved(ranges []string, node storage.ServiceAddr, from string) bool {
+	if len(ranges) == 0 {
+		return false
+	}
+	ip := net.ParseIP(from)
+	if ip == nil {
+		return false
+	}
+	in := false
+	for _, cidr := range ranges {
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err == nil && ipnet.Contains(ip) {
+			in = true
+			break
+		}
+	}
+	return in && sameHost(node, from)
+}
+
+// Pending returns nodes waiting for approval.
+//
+// Pending возвращает node, ожидающие одобрения.
+func (r *Router) Pending() []PendingNode {
+	r.RLock()
+	defer r.RUnlock()
+	tNow := r.clock.Now()
+	ret := make([]PendingNode, 0, len(r.pending))
+	for node, p := range r.pending {
+		ret = append(ret, PendingNode{
+			Node:         node,
+			Age:          tNow.Sub(p.first),
+			HeartbeatAge: tNow.Sub(p.last),
+			ID:           p.ident.ID,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Node < ret[j].Node })
+	return ret
+}
+
+// pendingState fills pending and rejected nodes of st at tNow.
+// Must be called with the lock held.
+func (r *Router) pendingState(st *State, tNow time.Time) {
+	st.Pending = make([]PendingNode, 0, len(r.pending))
+	for node, p := range r.pending {
+		st.Pending = append(st.Pending, PendingNode{
+			Node:         node,
+			Age:          tNow.Sub(p.first),
+			HeartbeatAge: tNow.Sub(p.last),
+			ID:           p.ident.ID,
+		})
+		if p.ident.ID != "" {
+			if st.PendingIdentities == nil {
+				st.PendingIdentities = make(map[storage.ServiceAddr]Identity)
+			}
+			st.PendingIdentities[node] = p.ident
+		}
+	}
+	st.Rejected = copyIdentities(r.rejected)
+}
+
+// setPendingState replaces pending and rejected nodes with the ones
+// of st taken at tNow. Must be called with the lock held.
+func (r *Router) setPendingState(st State, tNow time.Time) {
+	r.pending = make(map[storage.ServiceAddr]pendingNode, len(st.Pending))
+	for _, p := range st.Pending {
+		r.pending[p.Node] = pendingNode{
+			first: tNow.Add(-p.Age),
+			last:  tNow.Add(-p.HeartbeatAge),
+			ident: st.PendingIdentities[p.Node],
+		}
+	}
+	r.rejected = make(map[storage.ServiceAddr]Identity, len(st.Rejected))
+	for node, id := range st.Rejected {
+		r.rejected[node] = id
+	}
+}
+
+// ApproveNode adds a pending or rejected node to the served nodes and
+// increments the topology epoch. If rebalance is set, the node takes its
+// share of keys right away, otherwise it joins in the NodeJoining state
+// and gets keys only when it is made active with SetNodeState, e.g. after
+// its data is copied. The identity presented by the node becomes its
+// identity. Returns storage.ErrUnknownDaemon error if node is neither
+// pending nor rejected.
+//
+// ApproveNode добавляет ожидающую или отклоненную node к обслуживаемым
+// и увеличивает эпоху. Если rebalance задан, node сразу получает свою
+// долю ключей, иначе она добавляется в состоянии NodeJoining и получает
+// ключи, только когда становится активной через SetNodeState, например,
+// после копирования на нее данных. Предъявленная node идентичность
+// становится ее идентичностью. Возвращает ошибку
+// storage.ErrUnknownDaemon если node не ожидает одобрения и не отклонена.
+func (r *Router) ApproveNode(node storage.ServiceAddr, rebalance bool) error {
+	r.Lock()
+	defer r.Unlock()
+	p, pending := r.pending[node]
+	ident, rejected := r.rejected[node]
+	if !pending && !rejected {
+		return storage.ErrUnknownDaemon
+	}
+	if pending {
+		ident = p.ident
+	}
+	r.approve(node, rebalance, ident)
+	return nil
+}
+
+// approve serves node with the identity id, if set.
+// Must be called with the lock held.
+func (r *Router) approve(node storage.ServiceAddr, rebalance bool, id Identity) {
+	delete(r.pending, node)
+	delete(r.rejected, node)
+	if id.ID != "" {
+		if r.idents == nil {
+			r.idents = make(map[storage.ServiceAddr]Identity)
+		}
+		r.idents[node] = id
+	}
+	if !rebalance {
+		r.states[node] = NodeJoining
+	}
+	nodes := append(append([]storage.ServiceAddr(nil), r.nodes...), node)
+	r.setNodes(nodes, r.epoch+1)
+}
+
+// RejectNode rejects a pending node. Heartbeats of a rejected node are
+// ignored until it is approved. Returns storage.ErrUnknownDaemon error
+// if node is not pending.
+//
+// RejectNode отклоняет ожидающую node. Heartbeats отклоненной node
+// игнорируются, пока она не будет одобрена. Возвращает ошибку
+// storage.ErrUnknownDaemon если node не ожидает одобрения.
+func (r *Router) RejectNode(node storage.ServiceAddr) error {
+	r.Lock()
+	defer r.Unlock()
+	p, ok := r.pending[node]
+	if !ok {
+		return storage.ErrUnknownDaemon
+	}
+	delete(r.pending, node)
+	r.rejected[node] = p.ident
+	return nil
+}
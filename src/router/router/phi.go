@@ -0,0 +1,114 @@
+package router
+
+import (
+	"math"
+	"time"
+)
+
+// Clock is the common interface to get current time.
+//
+// Clock -- общий интерфейс для получения текущего времени.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+const (
+	// DefaultPhiWindow is a default number of heartbeat intervals
+	// remembered by a PhiDetector.
+	DefaultPhiWindow = 100
+	// DefaultPhiMinStdDev is a default lower bound of a standard deviation
+	// of heartbeat intervals used by a PhiDetector.
+	DefaultPhiMinStdDev = 100 * time.Millisecond
+
+	// phiMinSamples is a number of intervals needed to compute phi.
+	phiMinSamples = 3
+)
+
+// PhiDetector is a phi-accrual failure detector. It learns the distribution
+// of intervals between heartbeats of a node and reports phi -- a suspicion
+// level that the node is dead given time passed since the last heartbeat.
+// Phi of 1 means a 10% chance of a mistake, phi of 2 -- 1%, phi of 3 -- 0.1%
+// and so on.
+//
+// PhiDetector -- phi-accrual детектор отказов. Он изучает распределение
+// интервалов между heartbeats node и вычисляет phi -- уровень подозрения,
+// что node недоступна, исходя из времени, прошедшего с последнего heartbeat.
+// Phi равное 1 означает 10% вероятность ошибки, 2 -- 1%, 3 -- 0.1% и т.д.
+type PhiDetector struct {
+	minStdDev float64
+	intervals []float64
+	next      int
+	sum       float64
+	sumSq     float64
+	last      time.Time
+}
+
+// NewPhiDetector creates a PhiDetector remembering window last intervals.
+//
+// NewPhiDetector создает PhiDetector, запоминающий window последних интервалов.
+func NewPhiDetector(window int, minStdDev time.Duration) *PhiDetector {
+	if window <= 0 {
+		window = DefaultPhiWindow
+	}
+	if minStdDev <= 0 {
+		minStdDev = DefaultPhiMinStdDev
+	}
+	return &PhiDetector{
+		minStdDev: float64(minStdDev),
+		intervals: make([]float64, 0, window),
+	}
+}
+
+// Heartbeat registers a heartbeat received at t.
+//
+// Heartbeat регистрирует heartbeat, полученный в момент t.
+func (d *PhiDetector) Heartbeat(t time.Time) {
+	if d.last.IsZero() {
+		d.last = t
+		return
+	}
+	interval := float64(t.Sub(d.last))
+	d.last = t
+	if len(d.intervals) < cap(d.intervals) {
+		d.intervals = append(d.intervals, interval)
+	} else {
+		old := d.intervals[d.next]
+		d.sum -= old
+		d.sumSq -= old * old
+		d.intervals[d.next] = interval
+		d.next = (d.next + 1) % len(d.intervals)
+	}
+	d.sum += interval
+	d.sumSq += interval * interval
+}
+
+// Phi returns the suspicion level at t. It is 0 until enough
+// heartbeats are received.
+//
+// Phi возвращает уровень подозрения в момент t. Он равен 0, пока
+// не получено достаточно heartbeats.
+func (d *PhiDetector) Phi(t time.Time) float64 {
+	n := float64(len(d.intervals))
+	if len(d.intervals) < phiMinSamples {
+		return 0
+	}
+	mean := d.sum / n
+	stdDev := math.Sqrt(math.Max(d.sumSq/n-mean*mean, 0))
+	if stdDev < d.minStdDev {
+		stdDev = d.minStdDev
+	}
+
+	// logistic approximation of the normal distribution CDF
+	y := (float64(t.Sub(d.last)) - mean) / stdDev
+	e := math.Exp(-y * (1.5976 + 0.070566*y*y))
+	if y > 0 {
+		return -math.Log10(e / (1 + e))
+	}
+	return -math.Log10(1 - 1/(1+e))
+}
@@ -0,0 +1,80 @@
+package router
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type FakeClock struct {
+	sync.Mutex
+	t time.Time
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.t
+}
+
+func (c *FakeClock) Advance(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func TestPhi(t *testing.T) {
+	d := NewPhiDetector(10, 10*time.Millisecond)
+	start := time.Unix(0, 0)
+	if phi := d.Phi(start.Add(time.Hour)); phi != 0 {
+		t.Errorf("Phi() without heartbeats got %v, want 0", phi)
+	}
+
+	tNow := start
+	for i := 0; i < 20; i++ {
+		d.Heartbeat(tNow)
+		tNow = tNow.Add(100 * time.Millisecond)
+	}
+	last := tNow.Add(-100 * time.Millisecond)
+
+	prev := -1.0
+	for _, test := range []struct {
+		after time.Duration
+		less  float64
+		more  float64
+	}{
+		{after: 50 * time.Millisecond, less: 0.5},
+		{after: 100 * time.Millisecond, less: 1},
+		{after: 130 * time.Millisecond, more: 1},
+		{after: 200 * time.Millisecond, more: 8},
+		{after: time.Second, more: 8},
+	} {
+		phi := d.Phi(last.Add(test.after))
+		if test.less > 0 && phi >= test.less {
+			t.Errorf("Phi() after %v got %v, want less than %v", test.after, phi, test.less)
+		}
+		if phi < test.more {
+			t.Errorf("Phi() after %v got %v, want at least %v", test.after, phi, test.more)
+		}
+		if phi < prev {
+			t.Errorf("Phi() after %v got %v, less than %v before", test.after, phi, prev)
+		}
+		prev = phi
+	}
+}
+
+func TestPhi_Jitter(t *testing.T) {
+	d := NewPhiDetector(100, time.Millisecond)
+	tNow := time.Unix(0, 0)
+	for i := 0; i < 100; i++ {
+		d.Heartbeat(tNow)
+		tNow = tNow.Add(time.Duration(50+100*(i%2)) * time.Millisecond)
+	}
+	last := tNow.Add(-150 * time.Millisecond)
+	if phi := d.Phi(last.Add(150 * time.Millisecond)); phi >= 1 {
+		t.Errorf("Phi() within usual jitter got %v, want less than 1", phi)
+	}
+	if phi := d.Phi(last.Add(time.Second)); phi < 8 {
+		t.Errorf("Phi() far beyond usual jitter got %v, want at least 8", phi)
+	}
+}
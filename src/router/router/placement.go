@@ -0,0 +1,362 @@
+package router
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"storage"
+)
+
+// Names of placement strategies.
+//
+// Имена стратегий размещения.
+const (
+	PlacementHRW    = "hrw"
+	PlacementRing   = "ring"
+	PlacementJump   = "jump"
+	PlacementMaglev = "maglev"
+)
+
+// Names of hashers.
+//
+// Имена hashers.
+const (
+	HasherMD5 = "md5"
+	HasherFNV = "fnv"
+	HasherXX  = "xxhash"
+)
+
+// NewHasher returns a Hasher by name. MD5 is used if name is empty.
+//
+// NewHasher возвращает Hasher по имени. Если name пусто, используется MD5.
+func NewHasher(name string) (Hasher, error) {
+	switch name {
+	case "", HasherMD5:
+		return NewMD5Hasher(), nil
+	case HasherFNV:
+		return FNV{}, nil
+	case HasherXX:
+		return XXHash{}, nil
+	default:
+		return nil, fmt.Errorf("Unknown hasher %q", name)
+	}
+}
+
+// NewPlacement returns a Placement by name using h. Rendezvous hashing
+// is used if name is empty.
+//
+// NewPlacement возвращает Placement по имени, использующую h. Если name
+// пусто, используется rendezvous hashing.
+func NewPlacement(name string, h Hasher) (Placement, error) {
+	switch name {
+	case "", PlacementHRW:
+		return NewHRW(h), nil
+	case PlacementRing:
+		return NewRing(h, DefaultVirtualNodes), nil
+	case PlacementJump:
+		return NewJump(h), nil
+	case PlacementMaglev:
+		return NewMaglev(h, DefaultMaglevSize), nil
+	default:
+		return nil, fmt.Errorf("Unknown placement %q", name)
+	}
+}
+
+// NewNodesFinderByName creates NodesFinder with placement and hasher
+// given by names. Empty names give rendezvous hashing over MD5, which is
+// guaranteed to place records the same way as earlier versions.
+//
+// NewNodesFinderByName создает NodesFinder со стратегией размещения
+// и hasher, заданными по именам. Пустые имена дают rendezvous hashing
+// на основе MD5, который гарантированно размещает записи так же,
+// как предыдущие версии.
+func NewNodesFinderByName(placement, hasher string) (NodesFinder, error) {
+	h, err := NewHasher(hasher)
+	if err != nil {
+		return NodesFinder{}, err
+	}
+	p, err := NewPlacement(placement, h)
+	if err != nil {
+		return NodesFinder{}, err
+	}
+	return NewPlacementFinder(p), nil
+}
+
+// weight returns the weight of node, which is 1 unless set.
+func weight(weights map[storage.ServiceAddr]float64, node storage.ServiceAddr) float64 {
+	if w := weights[node]; w > 0 {
+		return w
+	}
+	return 1
+}
+
+// HRW is a rendezvous (highest random weight) hashing Placement.
+// Each node gets a score for a key, nodes with higher scores are preferred.
+//
+// HRW -- Placement на основе rendezvous hashing. Каждая node получает
+// оценку для ключа, предпочтительнее node с большей оценкой.
+type HRW struct {
+	hasher Hasher
+	cache  *tableCache
+}
+
+// NewHRW creates HRW with given Hasher.
+//
+// NewHRW создает HRW с данным Hasher.
+func NewHRW(h Hasher) HRW {
+	return HRW{hasher: h, cache: new(tableCache)}
+}
+
+// hrwNode is a node with its precomputed state.
+type hrwNode struct {
+	addr storage.ServiceAddr
+	// key is the value the node is hashed on, see Topology.IDs.
+	key storage.ServiceAddr
+	// weight is 0 if the topology has no weights.
+	weight float64
+}
+
+func (p HRW) build(t Topology) []hrwNode {
+	nodes := make([]hrwNode, 0, len(t.Nodes))
+	for _, node := range t.Nodes {
+		n := hrwNode{addr: node, key: t.key(node)}
+		if len(t.Weights) > 0 {
+			n.weight = weight(t.Weights, node)
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// hrwScore is a score of a node for some key.
+type hrwScore struct {
+	score float64
+	hash  uint64
+	key   storage.ServiceAddr
+	addr  storage.ServiceAddr
+}
+
+func (p HRW) score(k storage.RecordID, n *hrwNode) hrwScore {
+	s := hrwScore{hash: p.hasher.Hash(k, n.key), key: n.key, addr: n.addr}
+	if n.weight > 0 {
+		s.score = weightedScore(s.hash, n.weight)
+	}
+	return s
+}
+
+// before reports whether a node with score s is preferred to one with o.
+// Ties are broken by hashed keys.
+func (s *hrwScore) before(o *hrwScore) bool {
+	if s.score != o.score {
+		return s.score > o.score
+	}
+	if s.hash == o.hash {
+		return s.key > o.key
+	}
+	return s.hash > o.hash
+}
+
+func (p HRW) nodes(t Topology) []hrwNode {
+	return p.cache.get(t, func() interface{} { return p.build(t) }).([]hrwNode)
+}
+
+func (p HRW) Rank(k storage.RecordID, t Topology) []storage.ServiceAddr {
+	nodes := p.nodes(t)
+	scores := make([]hrwScore, 0, len(nodes))
+	for i := range nodes {
+		scores = append(scores, p.score(k, &nodes[i]))
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].before(&scores[j])
+	})
+
+	ret := make([]storage.ServiceAddr, 0, len(scores))
+	for _, s := range scores {
+		ret = append(ret, s.addr)
+	}
+	return ret
+}
+
+// Top is like Rank but returns only the first n nodes. It selects them
+// in a single pass without sorting all nodes.
+//
+// Top аналогичен Rank, но возвращает только первые n node. Они выбираются
+// за один проход без сортировки всех node.
+func (p HRW) Top(k storage.RecordID, t Topology, n int) []storage.ServiceAddr {
+	nodes := p.nodes(t)
+	n = min(n, len(nodes))
+	var arr [storage.ReplicationFactor]hrwScore
+	top := arr[:0]
+	if n > len(arr) {
+		top = make([]hrwScore, 0, n)
+	}
+	for i := 0; i < len(nodes) && n > 0; i++ {
+		s := p.score(k, &nodes[i])
+		if len(top) == n {
+			if !s.before(&top[n-1]) {
+				continue
+			}
+			top = top[:n-1]
+		}
+		j := len(top)
+		top = append(top, s)
+		for ; j > 0 && s.before(&top[j-1]); j-- {
+			top[j] = top[j-1]
+		}
+		top[j] = s
+	}
+
+	ret := make([]storage.ServiceAddr, len(top))
+	for i := range top {
+		ret[i] = top[i].addr
+	}
+	return ret
+}
+
+// weightedScore returns a score of weighted rendezvous hashing, i.e.
+// -w / ln(h), where the hash h is mapped to (0, 1).
+// A node wins a key with probability proportional to its weight.
+func weightedScore(hash uint64, w float64) float64 {
+	// 53 upper bits fit into float64 exactly
+	x := (float64(hash>>11) + 0.5) / (1 << 53)
+	return -w / math.Log(x)
+}
+
+// Jump is a jump consistent hash Placement. A key is mapped to a position
+// in the list of nodes, the following nodes are the next preferred ones.
+// Nodes should be added only to the end of the list, otherwise most keys
+// move. Weights are ignored.
+//
+// Jump -- Placement на основе jump consistent hash. Ключ отображается
+// в позицию в списке node, следующие за ней node -- следующие
+// по предпочтительности. Node следует добавлять только в конец списка,
+// иначе большинство ключей переместится. Веса игнорируются.
+type Jump struct {
+	hasher Hasher
+}
+
+// NewJump creates Jump with given Hasher.
+//
+// NewJump создает Jump с данным Hasher.
+func NewJump(h Hasher) Jump {
+	return Jump{hasher: h}
+}
+
+func (p Jump) Rank(k storage.RecordID, t Topology) []storage.ServiceAddr {
+	return p.Top(k, t, len(t.Nodes))
+}
+
+// Top is like Rank but returns only the first n nodes.
+//
+// Top аналогичен Rank, но возвращает только первые n node.
+func (p Jump) Top(k storage.RecordID, t Topology, n int) []storage.ServiceAddr {
+	size := len(t.Nodes)
+	if size == 0 {
+		return nil
+	}
+	n = min(n, size)
+	b := jumpHash(p.hasher.Hash(k, ""), size)
+	ret := make([]storage.ServiceAddr, 0, n)
+	for i := 0; i < n; i++ {
+		ret = append(ret, t.Nodes[(b+i)%size])
+	}
+	return ret
+}
+
+// jumpHash maps key to one of n buckets, see "A Fast, Minimal Memory,
+// Consistent Hash Algorithm" by Lamping and Veach.
+func jumpHash(key uint64, n int) int {
+	var b, j int64 = -1, 0
+	for j < int64(n) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// tableCache remembers a table built for the last seen nodes, weights and IDs.
+type tableCache struct {
+	sync.RWMutex
+	nodes   []storage.ServiceAddr
+	weights map[storage.ServiceAddr]float64
+	ids     map[storage.ServiceAddr]string
+	table   interface{}
+}
+
+// same reports whether nodes, weights and IDs of t are the remembered ones.
+// Must be called with the lock held.
+func (c *tableCache) same(t Topology) bool {
+	if len(c.nodes) != len(t.Nodes) || len(c.weights) != len(t.Weights) || len(c.ids) != len(t.IDs) {
+		return false
+	}
+	for i, node := range t.Nodes {
+		if c.nodes[i] != node {
+			return false
+		}
+	}
+	for node, w := range t.Weights {
+		if cw, ok := c.weights[node]; !ok || cw != w {
+			return false
+		}
+	}
+	for node, id := range t.IDs {
+		if cid, ok := c.ids[node]; !ok || cid != id {
+			return false
+		}
+	}
+	return true
+}
+
+// get returns the table for t building it if nodes, weights or IDs of t changed.
+func (c *tableCache) get(t Topology, build func() interface{}) interface{} {
+	c.RLock()
+	if c.table != nil && c.same(t) {
+		defer c.RUnlock()
+		return c.table
+	}
+	c.RUnlock()
+
+	c.Lock()
+	defer c.Unlock()
+	if c.table == nil || !c.same(t) {
+		c.nodes = append([]storage.ServiceAddr(nil), t.Nodes...)
+		c.weights = copyWeights(t.Weights)
+		c.ids = copyIDs(t.IDs)
+		c.table = build()
+	}
+	return c.table
+}
+
+// distinct returns first n distinct nodes returned by at for 0 <= i < size.
+func distinct(n, size int, at func(i int) storage.ServiceAddr) []storage.ServiceAddr {
+	ret := make([]storage.ServiceAddr, 0, n)
+	var seen map[storage.ServiceAddr]bool
+	if n > storage.ReplicationFactor {
+		seen = make(map[storage.ServiceAddr]bool, n)
+	}
+	for i := 0; i < size && len(ret) < n; i++ {
+		node := at(i)
+		if seen != nil {
+			if seen[node] {
+				continue
+			}
+			seen[node] = true
+		} else if contains(ret, node) {
+			continue
+		}
+		ret = append(ret, node)
+	}
+	return ret
+}
+
+func contains(nodes []storage.ServiceAddr, node storage.ServiceAddr) bool {
+	for _, n := range nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package router
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"storage"
+)
+
+func testNodes(n int) []storage.ServiceAddr {
+	var nodes []storage.ServiceAddr
+	for i := 0; i < n; i++ {
+		nodes = append(nodes, storage.ServiceAddr(fmt.Sprintf("127.0.0.1:%d", 7320+i)))
+	}
+	return nodes
+}
+
+// TestNodesFinderByName_Default checks that the default placement
+// still stores records on the same nodes as earlier versions.
+func TestNodesFinderByName_Default(t *testing.T) {
+	nf, err := NewNodesFinderByName("", "")
+	if err != nil {
+		t.Fatalf("NewNodesFinderByName() error: %v", err)
+	}
+	nodes := testNodes(6)
+	golden := map[storage.RecordID][]storage.ServiceAddr{
+		0:          {"127.0.0.1:7323", "127.0.0.1:7321", "127.0.0.1:7322"},
+		1:          {"127.0.0.1:7322", "127.0.0.1:7321", "127.0.0.1:7325"},
+		2:          {"127.0.0.1:7324", "127.0.0.1:7322", "127.0.0.1:7320"},
+		42:         {"127.0.0.1:7322", "127.0.0.1:7321", "127.0.0.1:7320"},
+		1000:       {"127.0.0.1:7323", "127.0.0.1:7322", "127.0.0.1:7324"},
+		4294967295: {"127.0.0.1:7320", "127.0.0.1:7321", "127.0.0.1:7322"},
+	}
+	for k, want := range golden {
+		if got := nf.NodesFind(k, nodes); !reflect.DeepEqual(got, want) {
+			t.Errorf("NodesFind(%v) got %v, want %v", k, got, want)
+		}
+	}
+
+	md5 := NewNodesFinder(NewMD5Hasher())
+	for k := storage.RecordID(0); k < 1000; k++ {
+		if got, want := nf.NodesFind(k, nodes), md5.NodesFind(k, nodes); !reflect.DeepEqual(got, want) {
+			t.Fatalf("NodesFind(%v) got %v, want %v", k, got, want)
+		}
+	}
+}
+
+func TestNodesFinderByName_Unknown(t *testing.T) {
+	if _, err := NewNodesFinderByName("random", ""); err == nil {
+		t.Errorf("NewNodesFinderByName() with unknown placement succeeded")
+	}
+	if _, err := NewNodesFinderByName("", "crc"); err == nil {
+		t.Errorf("NewNodesFinderByName() with unknown hasher succeeded")
+	}
+}
+
+var placements = []string{PlacementHRW, PlacementRing, PlacementJump, PlacementMaglev}
+var hashers = []string{HasherMD5, HasherFNV, HasherXX}
+
+func TestPlacement_Rank(t *testing.T) {
+	nodes := testNodes(10)
+	for _, p := range placements {
+		for _, h := range hashers {
+			nf, err := NewNodesFinderByName(p, h)
+			if err != nil {
+				t.Fatalf("NewNodesFinderByName(%q, %q) error: %v", p, h, err)
+			}
+			for k := storage.RecordID(0); k < 100; k++ {
+				got := nf.NodesFind(k, nodes)
+				if len(got) != storage.ReplicationFactor {
+					t.Fatalf("%v/%v: NodesFind(%v) got %v", p, h, k, got)
+				}
+				seen := make(map[storage.ServiceAddr]bool)
+				for _, node := range got {
+					if seen[node] {
+						t.Fatalf("%v/%v: NodesFind(%v) got duplicate nodes %v", p, h, k, got)
+					}
+					seen[node] = true
+				}
+				if again := nf.NodesFind(k, nodes); !reflect.DeepEqual(got, again) {
+					t.Fatalf("%v/%v: NodesFind(%v) is not deterministic: %v and %v", p, h, k, got, again)
+				}
+			}
+		}
+	}
+}
+
+func TestPlacement_Distribution(t *testing.T) {
+	const keys = 50000
+	nodes := testNodes(5)
+	weights := map[storage.ServiceAddr]float64{nodes[0]: 3}
+	for _, p := range placements {
+		nf, err := NewNodesFinderByName(p, HasherXX)
+		if err != nil {
+			t.Fatalf("NewNodesFinderByName(%q) error: %v", p, err)
+		}
+		for _, weighted := range []bool{false, true} {
+			if weighted && p == PlacementJump {
+				continue
+			}
+			w := weights
+			if !weighted {
+				w = nil
+			}
+			primary := make(map[storage.ServiceAddr]int)
+			for k := 0; k < keys; k++ {
+				primary[nf.NodesFindWeighted(storage.RecordID(k), nodes, w)[0]]++
+			}
+			var total float64
+			for _, node := range nodes {
+				total += weight(w, node)
+			}
+			for _, node := range nodes {
+				want := weight(w, node) / total
+				share := float64(primary[node]) / keys
+				if math.Abs(share-want) > 0.05 {
+					t.Errorf("%v (weighted %v): node %v got %.3f of keys, want %.3f", p, weighted, node, share, want)
+				}
+			}
+		}
+	}
+}
+
+func TestPlacement_AddNode(t *testing.T) {
+	const keys = 10000
+	nodes := testNodes(10)
+	for _, p := range placements {
+		nf, err := NewNodesFinderByName(p, HasherXX)
+		if err != nil {
+			t.Fatalf("NewNodesFinderByName(%q) error: %v", p, err)
+		}
+		before := make([]storage.ServiceAddr, keys)
+		for k := range before {
+			before[k] = nf.NodesFind(storage.RecordID(k), nodes[:9])[0]
+		}
+		moved := 0
+		for k := range before {
+			after := nf.NodesFind(storage.RecordID(k), nodes)[0]
+			if before[k] == after {
+				continue
+			}
+			// Maglev moves a few keys between old nodes as well
+			if after != nodes[9] && p != PlacementMaglev {
+				t.Fatalf("%v: key %v moved from %v to %v, not to the new node", p, k, before[k], after)
+			}
+			moved++
+		}
+		// about a tenth of keys should move to the new node
+		if share := float64(moved) / keys; share > 0.15 {
+			t.Errorf("%v: %.3f of keys moved after adding a node", p, share)
+		}
+	}
+}
+
+// TestPlacement_IDs checks that nodes with IDs equal to their addresses
+// are placed as before and that keys follow a node to its new address.
+func TestPlacement_IDs(t *testing.T) {
+	const keys = 1000
+	nodes := testNodes(6)
+	ids := make(map[storage.ServiceAddr]string)
+	for _, node := range nodes {
+		ids[node] = string(node)
+	}
+	moved := append([]storage.ServiceAddr(nil), nodes...)
+	moved[2] = "10.0.0.1:7000"
+	movedIDs := make(map[storage.ServiceAddr]string)
+	for i, node := range moved {
+		movedIDs[node] = string(nodes[i])
+	}
+	for _, p := range placements {
+		nf, err := NewNodesFinderByName(p, HasherMD5)
+		if err != nil {
+			t.Fatalf("NewNodesFinderByName(%q) error: %v", p, err)
+		}
+		// topologies are not interleaved to keep tables of placements cached
+		want := make([][]storage.ServiceAddr, keys)
+		for k := range want {
+			want[k] = nf.NodesFindTopology(storage.RecordID(k), Topology{Nodes: nodes})
+		}
+		for k := range want {
+			if got := nf.NodesFindTopology(storage.RecordID(k), Topology{Nodes: nodes, IDs: ids}); !reflect.DeepEqual(got, want[k]) {
+				t.Fatalf("%v: NodesFindTopology(%v) with IDs got %v, want %v", p, k, got, want[k])
+			}
+		}
+		for k := range want {
+			for i, node := range want[k] {
+				if node == nodes[2] {
+					want[k][i] = moved[2]
+				}
+			}
+			if got := nf.NodesFindTopology(storage.RecordID(k), Topology{Nodes: moved, IDs: movedIDs}); !reflect.DeepEqual(got, want[k]) {
+				t.Fatalf("%v: NodesFindTopology(%v) after address change got %v, want %v", p, k, got, want[k])
+			}
+		}
+	}
+}
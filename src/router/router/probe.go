@@ -0,0 +1,162 @@
+package router
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"storage"
+)
+
+// Prober is the common interface to check whether a node is able to serve requests.
+//
+// Prober -- общий интерфейс для проверки, способна ли node обслуживать запросы.
+type Prober interface {
+	Ping(node storage.ServiceAddr) error
+}
+
+// NodeStatus is a liveness verdict for a node.
+//
+// NodeStatus -- вердикт о доступности node.
+type NodeStatus int
+
+const (
+	// NodeAlive means that the node sends heartbeats and passes probes.
+	// NodeAlive -- node посылает heartbeats и проходит проверки.
+	NodeAlive NodeStatus = iota
+	// NodeDegraded means that the node either sends heartbeats or passes probes.
+	// NodeDegraded -- node либо посылает heartbeats, либо проходит проверки.
+	NodeDegraded
+	// NodeDead means that the node neither sends heartbeats nor passes probes.
+	// NodeDead -- node не посылает heartbeats и не проходит проверки.
+	NodeDead
+)
+
+func (s NodeStatus) String() string {
+	switch s {
+	case NodeAlive:
+		return "alive"
+	case NodeDegraded:
+		return "degraded"
+	case NodeDead:
+		return "dead"
+	default:
+		return "unknown"
+	}
+}
+
+var errProbeTimeout = errors.New("Probe timed out")
+
+// probeResult is a result of the last probe of a node.
+type probeResult struct {
+	done bool
+	ok   bool
+}
+
+// status returns the status of node at tNow.
+// Must be called with the lock held.
+func (r *Router) status(node storage.ServiceAddr, tNow time.Time) NodeStatus {
+	hb := r.alive(node, tNow)
+	probe := r.probes[node]
+	switch {
+	case !probe.done:
+		if hb {
+			return NodeAlive
+		}
+		return NodeDead
+	case hb && probe.ok:
+		return NodeAlive
+	case hb || probe.ok:
+		return NodeDegraded
+	default:
+		return NodeDead
+	}
+}
+
+// Status returns the liveness verdict for node combining heartbeats and probes.
+// Returns storage.ErrUnknownDaemon error if node is not served by the Router.
+//
+// Status возвращает вердикт о доступности node на основе heartbeats и проверок.
+// Возвращает ошибку storage.ErrUnknownDaemon если node не
+// обслуживается Router.
+func (r *Router) Status(node storage.ServiceAddr) (NodeStatus, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if _, ok := r.lastHB[node]; !ok {
+		return NodeDead, storage.ErrUnknownDaemon
+	}
+	return r.status(node, r.clock.Now()), nil
+}
+
+// Probes runs probes of all nodes each time interval set by cfg.ProbeInterval
+// using cfg.Prober. Does nothing if either of them is not set.
+//
+// Probes запускает проверки всех node через каждый интервал времени,
+// заданный в cfg.ProbeInterval, используя cfg.Prober. Ничего не делает,
+// если один из них не задан.
+func (r *Router) Probes() {
+	if r.cfg.Prober == nil || r.cfg.ProbeInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(r.cfg.ProbeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.probe()
+			}
+		}
+	}()
+}
+
+// Stop stops probes.
+//
+// Stop останавливает проверки.
+func (r *Router) Stop() {
+	close(r.stop)
+}
+
+// probe probes all nodes once. A probe that takes longer than
+// cfg.ProbeTimeout is considered failed.
+func (r *Router) probe() {
+	r.RLock()
+	nodes := append([]storage.ServiceAddr(nil), r.nodes...)
+	prober := r.cfg.Prober
+	timeout := r.cfg.ProbeTimeout
+	if timeout <= 0 {
+		timeout = r.cfg.ProbeInterval
+	}
+	r.RUnlock()
+
+	var wg sync.WaitGroup
+	wg.Add(len(nodes))
+	for _, node := range nodes {
+		go func(node storage.ServiceAddr) {
+			defer wg.Done()
+			ch := make(chan error, 1)
+			go func() {
+				ch <- prober.Ping(node)
+			}()
+			var err error
+			select {
+			case err = <-ch:
+			case <-time.After(timeout):
+				err = errProbeTimeout
+			}
+			if err != nil {
+				log.Printf("Probe of %q failed: %v", node, err)
+			}
+
+			r.Lock()
+			if _, ok := r.probes[node]; ok {
+				r.probes[node] = probeResult{done: true, ok: err == nil}
+			}
+			r.Unlock()
+		}(node)
+	}
+	wg.Wait()
+}
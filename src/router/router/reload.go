@@ -0,0 +1,179 @@
+package router
+
+import (
+	"reflect"
+
+	"storage"
+)
+
+// Config returns the effective configuration of the Router. Nodes,
+// weights, domains and IDs are the current ones, which may differ from
+// the configured ones after SetNodes, SetNodeAddr or SetState.
+//
+// Config возвращает действующую конфигурацию Router. Nodes, веса, домены
+// и ID -- текущие, они могут отличаться от заданных после SetNodes,
+// SetNodeAddr или SetState.
+func (r *Router) Config() Config {
+	r.RLock()
+	defer r.RUnlock()
+	cfg := r.cfg
+	cfg.Nodes = append([]storage.ServiceAddr(nil), r.nodes...)
+	cfg.Weights = copyWeights(r.weights)
+	cfg.Domains = copyDomains(r.domains)
+	cfg.IDs = copyIDs(r.ids)
+	cfg.Identities = copyIdentities(r.idents)
+	return cfg
+}
+
+// Reload applies fields of cfg changed since the previous configuration
+// to the running Router. Nodes added to or removed from the configuration
+// are added to or removed from the current nodes, so nodes approved with
+// ApproveNode and addresses changed with SetNodeAddr are kept. Changes
+// of nodes, weights, domains or IDs increment the topology epoch. The address, peers, election timeout,
+// placement, hasher and probe interval cannot be changed live,
+// a storage.RestartError is returned if they differ, other fields are
+// applied anyway. Returns storage.ErrNotEnoughDaemons error and applies
+// nothing if less then storage.ReplicationFactor nodes is provided or
+// would remain.
+// Routers of a cluster which are not the leader use ReloadFollower.
+//
+// Reload применяет к работающему Router поля cfg, изменившиеся с
+// предыдущей конфигурации. Nodes, добавленные в конфигурацию или удаленные
+// из нее, добавляются к текущим nodes или удаляются из них, поэтому nodes,
+// одобренные ApproveNode, и адреса, измененные SetNodeAddr, сохраняются.
+// Изменение nodes, весов, доменов или ID увеличивает эпоху. Адрес, peers, таймаут выборов, стратегию размещения,
+// hasher и интервал проверок нельзя изменить без перезапуска, если они
+// отличаются, возвращается storage.RestartError, остальные поля
+// применяются. Возвращает ошибку storage.ErrNotEnoughDaemons и ничего
+// не применяет, если передано или осталось бы меньше чем
+// storage.ReplicationFactor nodes.
+// Router кластера, не являющиеся лидером, используют ReloadFollower.
+func (r *Router) Reload(cfg Config) error {
+	if len(cfg.Nodes) < storage.ReplicationFactor {
+		return storage.ErrNotEnoughDaemons
+	}
+	r.Lock()
+	defer r.Unlock()
+	return r.reload(cfg)
+}
+
+// ReloadFollower is like Reload but ignores changes of nodes, weights,
+// domains and IDs, so the topology epoch never changes. Followers of
+// a cluster get the topology from the leader with SetState, so it must
+// be changed only by the leader.
+//
+// ReloadFollower аналогичен Reload, но игнорирует изменения nodes, весов,
+// доменов и ID, поэтому эпоха не меняется. Остальные router кластера
+// получают топологию от лидера через SetState, поэтому изменять ее
+// должен только лидер.
+func (r *Router) ReloadFollower(cfg Config) error {
+	if len(cfg.Nodes) < storage.ReplicationFactor {
+		return storage.ErrNotEnoughDaemons
+	}
+	r.Lock()
+	defer r.Unlock()
+	cfg.Nodes, cfg.Weights = r.cfg.Nodes, r.cfg.Weights
+	cfg.Domains, cfg.IDs = r.cfg.Domains, r.cfg.IDs
+	return r.reload(cfg)
+}
+
+// reload applies cfg, r must be locked.
+func (r *Router) reload(cfg Config) error {
+	old := r.cfg
+	nodes := reloadNodes(r.nodes, old.Nodes, cfg.Nodes)
+	if len(nodes) < storage.ReplicationFactor {
+		return storage.ErrNotEnoughDaemons
+	}
+
+	var restart []string
+	if cfg.Addr != old.Addr {
+		restart = append(restart, "addr")
+	}
+	if !reflect.DeepEqual(cfg.Peers, old.Peers) {
+		restart = append(restart, "peers")
+	}
+	if cfg.ElectionTimeout != old.ElectionTimeout {
+		restart = append(restart, "election_timeout")
+	}
+	if cfg.Placement != old.Placement || cfg.Hasher != old.Hasher {
+		restart = append(restart, "placement")
+	}
+	if cfg.ProbeInterval != old.ProbeInterval {
+		restart = append(restart, "probe_interval")
+	}
+	cfg.Addr, cfg.Peers, cfg.ElectionTimeout = old.Addr, old.Peers, old.ElectionTimeout
+	cfg.Placement, cfg.Hasher, cfg.ProbeInterval = old.Placement, old.Hasher, old.ProbeInterval
+	cfg.NodesFinder, cfg.Prober, cfg.Clock = old.NodesFinder, old.Prober, old.Clock
+
+	changed := false
+	if !reflect.DeepEqual(cfg.Weights, old.Weights) {
+		r.weights = copyWeights(cfg.Weights)
+		changed = true
+	}
+	if !reflect.DeepEqual(cfg.Domains, old.Domains) {
+		r.domains = copyDomains(cfg.Domains)
+		changed = true
+	}
+	if !reflect.DeepEqual(cfg.IDs, old.IDs) {
+		r.ids = copyIDs(cfg.IDs)
+		changed = true
+	}
+	if !reflect.DeepEqual(cfg.Identities, old.Identities) {
+		idents := copyIdentities(cfg.Identities)
+		// identities of approved nodes are not in the config
+		for node, id := range r.idents {
+			if _, ok := old.Identities[node]; ok {
+				continue
+			}
+			if _, ok := idents[node]; !ok {
+				if idents == nil {
+					idents = make(map[storage.ServiceAddr]Identity)
+				}
+				idents[node] = id
+			}
+		}
+		r.idents = idents
+	}
+	r.cfg = cfg
+	switch {
+	case !reflect.DeepEqual(nodes, r.nodes):
+		r.setNodes(nodes, r.epoch+1)
+	case changed:
+		r.epoch++
+		r.notify()
+	}
+
+	if len(restart) > 0 {
+		return storage.RestartError{Fields: restart}
+	}
+	return nil
+}
+
+// reloadNodes returns current nodes with nodes added to the configured
+// ones since old added and nodes removed since old removed.
+func reloadNodes(current, old, configured []storage.ServiceAddr) []storage.ServiceAddr {
+	was := make(map[storage.ServiceAddr]bool, len(old))
+	for _, node := range old {
+		was[node] = true
+	}
+	is := make(map[storage.ServiceAddr]bool, len(configured))
+	for _, node := range configured {
+		is[node] = true
+	}
+	nodes := make([]storage.ServiceAddr, 0, len(current)+len(configured))
+	has := make(map[storage.ServiceAddr]bool, len(current))
+	for _, node := range current {
+		if was[node] && !is[node] {
+			continue
+		}
+		nodes = append(nodes, node)
+		has[node] = true
+	}
+	for _, node := range configured {
+		if !was[node] && !has[node] {
+			nodes = append(nodes, node)
+			has[node] = true
+		}
+	}
+	return nodes
+}
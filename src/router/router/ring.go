@@ -0,0 +1,85 @@
+package router
+
+import (
+	"math"
+	"sort"
+
+	"storage"
+)
+
+// DefaultVirtualNodes is a default number of points of a node of weight 1
+// on a Ring.
+//
+// DefaultVirtualNodes -- количество точек node веса 1 на Ring
+// по умолчанию.
+const DefaultVirtualNodes = 128
+
+// Ring is a consistent hashing Placement. Each node is placed at several
+// points of a hash ring, a key is served by nodes met walking the ring
+// clockwise from the hash of the key.
+//
+// Ring -- Placement на основе consistent hashing. Каждая node размещается
+// в нескольких точках кольца хешей, ключ обслуживается node, которые
+// встречаются при обходе кольца по часовой стрелке от хеша ключа.
+type Ring struct {
+	hasher Hasher
+	vnodes int
+	cache  *tableCache
+}
+
+type ringPoint struct {
+	hash uint64
+	key  storage.ServiceAddr
+	node storage.ServiceAddr
+}
+
+// NewRing creates Ring with given Hasher placing vnodes points
+// per a node of weight 1.
+//
+// NewRing создает Ring с данным Hasher, размещающий vnodes точек
+// для node веса 1.
+func NewRing(h Hasher, vnodes int) Ring {
+	if vnodes <= 0 {
+		vnodes = DefaultVirtualNodes
+	}
+	return Ring{hasher: h, vnodes: vnodes, cache: new(tableCache)}
+}
+
+func (p Ring) build(t Topology) []ringPoint {
+	var points []ringPoint
+	for _, node := range t.Nodes {
+		n := int(math.Max(1, math.Round(float64(p.vnodes)*weight(t.Weights, node))))
+		key := t.key(node)
+		for i := 0; i < n; i++ {
+			points = append(points, ringPoint{p.hasher.Hash(storage.RecordID(i), key), key, node})
+		}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].hash == points[j].hash {
+			return points[i].key < points[j].key
+		}
+		return points[i].hash < points[j].hash
+	})
+	return points
+}
+
+func (p Ring) Rank(k storage.RecordID, t Topology) []storage.ServiceAddr {
+	return p.Top(k, t, len(t.Nodes))
+}
+
+// Top is like Rank but returns only the first n nodes.
+//
+// Top аналогичен Rank, но возвращает только первые n node.
+func (p Ring) Top(k storage.RecordID, t Topology, n int) []storage.ServiceAddr {
+	points := p.cache.get(t, func() interface{} { return p.build(t) }).([]ringPoint)
+	if len(points) == 0 {
+		return nil
+	}
+	hash := p.hasher.Hash(k, "")
+	start := sort.Search(len(points), func(i int) bool {
+		return points[i].hash >= hash
+	})
+	return distinct(min(n, len(t.Nodes)), len(points), func(i int) storage.ServiceAddr {
+		return points[(start+i)%len(points)].node
+	})
+}
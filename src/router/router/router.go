@@ -19,22 +19,182 @@ type Config struct {
 	// Nodes -- список node обслуживаемых Router.
 	Nodes []storage.ServiceAddr
 
+	// Weights maps a node to its weight. A node gets a share of keys
+	// proportional to its weight. Weights must be positive, nodes missing
+	// in Weights have weight 1.
+	// Weights -- веса node. Node получает долю ключей, пропорциональную
+	// своему весу. Веса должны быть положительными, node, отсутствующие
+	// в Weights, имеют вес 1.
+	Weights map[storage.ServiceAddr]float64
+
+	// Domains maps a node to its failure domain. Replicas of a key are
+	// placed in distinct zones and racks when possible.
+	// Domains -- домены отказа node. Реплики ключа по возможности
+	// размещаются в разных зонах и стойках.
+	Domains map[storage.ServiceAddr]Domain
+
+	// Identities maps a node to its identity. If Identities is not empty,
+	// a heartbeat is accepted only if it carries the identity of the node
+	// and comes from the host of the node address. Unknown nodes present
+	// their identities for approval, see Router.Authenticate.
+	// Identities -- идентичности node. Если Identities не пусто, heartbeat
+	// принимается, только если он содержит идентичность node и пришел
+	// с хоста из адреса node. Неизвестные node предъявляют свои
+	// идентичности для одобрения, см. Router.Authenticate.
+	Identities map[storage.ServiceAddr]Identity
+
+	// AutoApprove is a list of address ranges in CIDR notation. Unknown
+	// nodes sending heartbeats from these ranges are served right away,
+	// others wait for approval, see Router.ApproveNode. The heartbeat must
+	// come from the host of the node address, see Router.HeartbeatFrom.
+	// AutoApprove -- список диапазонов адресов в нотации CIDR. Неизвестные
+	// node, посылающие heartbeats из этих диапазонов, сразу начинают
+	// обслуживаться, остальные ожидают одобрения, см. Router.ApproveNode.
+	// Heartbeat должен приходить с хоста из адреса node, см.
+	// Router.HeartbeatFrom.
+	AutoApprove []string `yaml:"auto_approve"`
+
+	// AutoRebalance makes auto-approved nodes take their share of keys
+	// right away, see Router.ApproveNode.
+	// AutoRebalance -- одобренные автоматически node сразу получают свою
+	// долю ключей, см. Router.ApproveNode.
+	AutoRebalance bool `yaml:"auto_rebalance"`
+
+	// IDs maps a node to its stable ID. Placement hashes on IDs, so
+	// the address of a node can be changed with Router.SetNodeAddr
+	// without moving its keys. Nodes missing in IDs are identified by
+	// their addresses, which keeps placement of existing clusters.
+	// IDs -- постоянные ID node. Размещение вычисляет хеши от ID, поэтому
+	// адрес node можно изменить с помощью Router.SetNodeAddr, не перемещая
+	// ее ключи. Node, отсутствующие в IDs, идентифицируются своими адресами,
+	// что сохраняет размещение в существующих кластерах.
+	IDs map[storage.ServiceAddr]string `yaml:"ids"`
+
+	// Placement is a name of a placement strategy: hrw, ring, jump or maglev.
+	// Rendezvous hashing (hrw) is used if Placement is empty.
+	// Placement -- имя стратегии размещения: hrw, ring, jump или maglev.
+	// Если Placement пусто, используется rendezvous hashing (hrw).
+	Placement string
+
+	// Hasher is a name of a hasher used by the placement: md5, fnv or xxhash.
+	// MD5 is used if Hasher is empty.
+	// Hasher -- имя hasher, используемого стратегией размещения:
+	// md5, fnv или xxhash. Если Hasher пусто, используется MD5.
+	Hasher string
+
 	// ForgetTimeout is a timeout after node is considered to be unavailable
 	// in absence of hearbeats.
 	// ForgetTimeout -- если в течении ForgetTimeout node не посылала heartbeats, то
 	// node считается недоступной.
 	ForgetTimeout time.Duration `yaml:"forget_timeout"`
 
+	// Peers is a list of other routers of the cluster. The Router is
+	// standalone if Peers is empty.
+	// Peers -- список остальных router кластера. Если Peers пуст, то
+	// Router работает самостоятельно.
+	Peers []storage.ServiceAddr
+
+	// ElectionTimeout is a timeout after which a router starts an election
+	// in absence of messages from a leader.
+	// ElectionTimeout -- если в течении ElectionTimeout router не получал
+	// сообщений от лидера, то router начинает выборы.
+	ElectionTimeout time.Duration `yaml:"election_timeout"`
+
+	// PhiThreshold is a suspicion level of a phi-accrual failure detector
+	// after which a node is considered to be unavailable before ForgetTimeout
+	// passes. The detector is disabled if PhiThreshold is 0.
+	// PhiThreshold -- уровень подозрения phi-accrual детектора отказов, после
+	// которого node считается недоступной, не дожидаясь ForgetTimeout.
+	// Детектор отключен, если PhiThreshold равен 0.
+	PhiThreshold float64 `yaml:"phi_threshold"`
+
+	// PhiWindow is a number of last heartbeat intervals the detector learns from.
+	// PhiWindow -- количество последних интервалов между heartbeats,
+	// по которым обучается детектор.
+	PhiWindow int `yaml:"phi_window"`
+
+	// PhiMinStdDev is a lower bound of a standard deviation of heartbeat intervals.
+	// PhiMinStdDev -- нижняя граница стандартного отклонения интервалов
+	// между heartbeats.
+	PhiMinStdDev time.Duration `yaml:"phi_min_std_dev"`
+
+	// ProbeInterval is a time interval between probes of nodes.
+	// Nodes are not probed if ProbeInterval is 0.
+	// ProbeInterval -- интервал между проверками node.
+	// Если ProbeInterval равен 0, то node не проверяются.
+	ProbeInterval time.Duration `yaml:"probe_interval"`
+
+	// ProbeTimeout is a timeout after which a probe is considered failed.
+	// ProbeInterval is used if ProbeTimeout is 0.
+	// ProbeTimeout -- время, после которого проверка считается неудачной.
+	// Если ProbeTimeout равен 0, используется ProbeInterval.
+	ProbeTimeout time.Duration `yaml:"probe_timeout"`
+
 	// NodesFinder specifies a NodesFinder to use.
 	// NodesFinder -- NodesFinder, который нужно использовать в Router.
 	NodesFinder NodesFinder `yaml:"-"`
+
+	// Prober specifies a Prober to use.
+	// Prober -- Prober, который нужно использовать.
+	Prober Prober `yaml:"-"`
+
+	// Clock specifies a Clock to use. Real time is used if Clock is nil.
+	// Clock -- Clock, который нужно использовать. Если Clock равен nil,
+	// используется реальное время.
+	Clock Clock `yaml:"-"`
+}
+
+// Topology is a set of nodes served by the Router at some epoch.
+//
+// Topology -- множество node, обслуживаемых Router, в некоторую эпоху.
+type Topology struct {
+	// Epoch is incremented each time the set of served nodes changes.
+	// Epoch -- увеличивается при каждом изменении множества node.
+	Epoch uint64
+
+	// Nodes is a list of nodes served by the Router.
+	// Nodes -- список node, обслуживаемых Router.
+	Nodes []storage.ServiceAddr
+
+	// Weights maps a node to its weight, see Config.Weights.
+	// Weights -- веса node, см. Config.Weights.
+	Weights map[storage.ServiceAddr]float64
+
+	// Domains maps a node to its failure domain, see Config.Domains.
+	// Domains -- домены отказа node, см. Config.Domains.
+	Domains map[storage.ServiceAddr]Domain
+
+	// States maps a node to its administrative state.
+	// Nodes missing in States are active.
+	// States -- административные состояния node.
+	// Node, отсутствующие в States, активны.
+	States map[storage.ServiceAddr]NodeState
+
+	// IDs maps a node to its stable ID, see Config.IDs.
+	// IDs -- постоянные ID node, см. Config.IDs.
+	IDs map[storage.ServiceAddr]string
 }
 
 // Router is a router service.
 type Router struct {
 	sync.RWMutex
-	cfg    Config
-	lastHB map[storage.ServiceAddr]time.Time
+	cfg      Config
+	nodes    []storage.ServiceAddr
+	weights  map[storage.ServiceAddr]float64
+	domains  map[storage.ServiceAddr]Domain
+	states   map[storage.ServiceAddr]NodeState
+	ids      map[storage.ServiceAddr]string
+	idents   map[storage.ServiceAddr]Identity
+	pending  map[storage.ServiceAddr]pendingNode
+	rejected map[storage.ServiceAddr]Identity
+	epoch    uint64
+	clock    Clock
+	lastHB   map[storage.ServiceAddr]time.Time
+	phi      map[storage.ServiceAddr]*PhiDetector
+	probes   map[storage.ServiceAddr]probeResult
+	stats    map[storage.ServiceAddr]storage.Stats
+	watchers map[chan Topology]struct{}
+	stop     chan struct{}
 }
 
 // New creates a new Router with a given cfg.
@@ -48,56 +208,376 @@ func New(cfg Config) (*Router, error) {
 	if len(cfg.Nodes) < storage.ReplicationFactor {
 		return nil, storage.ErrNotEnoughDaemons
 	}
-	ret := Router{cfg: cfg, lastHB: make(map[storage.ServiceAddr]time.Time)}
-	for _, node := range cfg.Nodes {
-		ret.lastHB[node] = time.Now()
+	ret := Router{
+		cfg:      cfg,
+		weights:  copyWeights(cfg.Weights),
+		domains:  copyDomains(cfg.Domains),
+		ids:      copyIDs(cfg.IDs),
+		idents:   copyIdentities(cfg.Identities),
+		clock:    cfg.Clock,
+		pending:  make(map[storage.ServiceAddr]pendingNode),
+		rejected: make(map[storage.ServiceAddr]Identity),
+		watchers: make(map[chan Topology]struct{}),
+		stop:     make(chan struct{}),
+	}
+	if ret.clock == nil {
+		ret.clock = realClock{}
 	}
+	ret.setNodes(cfg.Nodes, 1)
 	return &ret, nil
 }
 
 // Hearbeat registers node in the router.
 // Returns storage.ErrUnknownDaemon error if node is not served by the Router.
+// Such a node is registered as pending approval, see Pending.
 
 // Hearbeat регистритрует node в router.
 // Возвращает ошибку storage.ErrUnknownDaemon если node не
-// обслуживается Router.
+// обслуживается Router. Такая node регистрируется как ожидающая
+// одобрения, см. Pending.
 func (r *Router) Heartbeat(node storage.ServiceAddr) error {
+	return r.heartbeat(node, "", nil)
+}
+
+// HeartbeatFrom is like Heartbeat but the heartbeat was sent from the IP
+// address from. An unknown node is approved automatically only if from
+// is in Config.AutoApprove and is the host of the node address, nodes
+// are never approved automatically by Heartbeat.
+//
+// HeartbeatFrom аналогичен Heartbeat, но heartbeat был послан с IP адреса
+// from. Неизвестная node одобряется автоматически, только если from
+// входит в Config.AutoApprove и является хостом из адреса node, Heartbeat
+// никогда не одобряет node автоматически.
+func (r *Router) HeartbeatFrom(node storage.ServiceAddr, from string) error {
+	return r.heartbeat(node, from, nil)
+}
+
+// HeartbeatStats is like Heartbeat but also stores load statistics of node.
+//
+// HeartbeatStats аналогичен Heartbeat, но также сохраняет статистику
+// нагрузки node.
+func (r *Router) HeartbeatStats(node storage.ServiceAddr, st storage.Stats) error {
+	return r.heartbeat(node, "", &st)
+}
+
+// HeartbeatStatsFrom is like HeartbeatFrom but also stores load statistics
+// of node.
+//
+// HeartbeatStatsFrom аналогичен HeartbeatFrom, но также сохраняет
+// статистику нагрузки node.
+func (r *Router) HeartbeatStatsFrom(node storage.ServiceAddr, from string, st storage.Stats) error {
+	return r.heartbeat(node, from, &st)
+}
+
+// heartbeat registers a heartbeat of node sent from from, st is stored
+// if it is not nil.
+func (r *Router) heartbeat(node storage.ServiceAddr, from string, st *storage.Stats) error {
 	r.Lock()
 	defer r.Unlock()
-	if _, ok := r.lastHB[node]; ok {
-		r.lastHB[node] = time.Now()
+	if _, ok := r.lastHB[node]; ok || r.register(node, Identity{}, from) {
+		tNow := r.clock.Now()
+		r.lastHB[node] = tNow
+		r.phi[node].Heartbeat(tNow)
+		if st != nil {
+			r.stats[node] = *st
+		}
 		return nil
 	}
 	return storage.ErrUnknownDaemon
 }
 
+// alive reports whether node is considered to be available at tNow
+// judging by heartbeats.
+// Must be called with the lock held.
+func (r *Router) alive(node storage.ServiceAddr, tNow time.Time) bool {
+	if tNow.Sub(r.lastHB[node]) >= r.cfg.ForgetTimeout {
+		return false
+	}
+	return r.cfg.PhiThreshold <= 0 || r.phi[node].Phi(tNow) < r.cfg.PhiThreshold
+}
+
+// Phi returns the suspicion level of the phi-accrual failure detector for node.
+// Returns storage.ErrUnknownDaemon error if node is not served by the Router.
+//
+// Phi возвращает уровень подозрения phi-accrual детектора отказов для node.
+// Возвращает ошибку storage.ErrUnknownDaemon если node не
+// обслуживается Router.
+func (r *Router) Phi(node storage.ServiceAddr) (float64, error) {
+	r.RLock()
+	defer r.RUnlock()
+	d, ok := r.phi[node]
+	if !ok {
+		return 0, storage.ErrUnknownDaemon
+	}
+	return d.Phi(r.clock.Now()), nil
+}
+
 // NodesFind returns a list of available nodes, where record with associated key k
 // should be stored. Returns storage.ErrNotEnoughDaemons error
 // if less then storage.MinRedundancy can be returned.
+// Only nodes with NodeAlive status which accept writes are considered available.
+// Draining and joining nodes get no new records.
 //
 // NodesFind возвращает cписок достпуных node, на которых должна храниться
 // запись с ключом k. Возвращает ошибку storage.ErrNotEnoughDaemons
 // если меньше, чем storage.MinRedundancy найдено.
+// Доступными считаются только node со статусом NodeAlive, принимающие запись.
+// Draining и joining node не получают новых записей.
 func (r *Router) NodesFind(k storage.RecordID) ([]storage.ServiceAddr, error) {
-	temp := r.cfg.NodesFinder.NodesFind(k, r.cfg.Nodes)
-	ret := make([]storage.ServiceAddr, 0, len(temp))
-	tNow := time.Now()
+	nodes, _, err := r.NodesFindEpoch(k)
+	return nodes, err
+}
+
+// NodesFindEpoch is like NodesFind but also returns the topology epoch
+// the nodes were found at.
+//
+// NodesFindEpoch аналогичен NodesFind, но также возвращает эпоху,
+// в которую были найдены nodes.
+func (r *Router) NodesFindEpoch(k storage.RecordID) ([]storage.ServiceAddr, uint64, error) {
 	r.RLock()
+	defer r.RUnlock()
+	temp := r.cfg.NodesFinder.NodesFindServing(k, Topology{
+		Nodes:   r.nodes,
+		Weights: r.weights,
+		Domains: r.domains,
+		States:  r.states,
+		IDs:     r.ids,
+	})
+	ret := make([]storage.ServiceAddr, 0, len(temp))
+	tNow := r.clock.Now()
 	for _, node := range temp {
-		if tNow.Sub(r.lastHB[node]) < r.cfg.ForgetTimeout {
+		if r.status(node, tNow) == NodeAlive && r.states[node].Writable() {
 			ret = append(ret, node)
 		}
 	}
-	r.RUnlock()
 	if len(ret) < storage.MinRedundancy {
-		return nil, storage.ErrNotEnoughDaemons
+		return nil, r.epoch, storage.ErrNotEnoughDaemons
+	}
+	return ret, r.epoch, nil
+}
+
+// SetNodes replaces the set of served nodes and increments the topology epoch.
+// Returns storage.ErrNotEnoughDaemons error if less then storage.ReplicationFactor
+// nodes is provided.
+//
+// SetNodes заменяет множество обслуживаемых node и увеличивает эпоху.
+// Возвращает ошибку storage.ErrNotEnoughDaemons если передано
+// меньше чем storage.ReplicationFactor nodes.
+func (r *Router) SetNodes(nodes []storage.ServiceAddr) error {
+	if len(nodes) < storage.ReplicationFactor {
+		return storage.ErrNotEnoughDaemons
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.setNodes(nodes, r.epoch+1)
+	return nil
+}
+
+// setNodes must be called with the lock held.
+func (r *Router) setNodes(nodes []storage.ServiceAddr, epoch uint64) {
+	lastHB := make(map[storage.ServiceAddr]time.Time, len(nodes))
+	phi := make(map[storage.ServiceAddr]*PhiDetector, len(nodes))
+	probes := make(map[storage.ServiceAddr]probeResult, len(nodes))
+	stats := make(map[storage.ServiceAddr]storage.Stats, len(nodes))
+	states := make(map[storage.ServiceAddr]NodeState)
+	for _, node := range nodes {
+		t, ok := r.lastHB[node]
+		if !ok {
+			t = r.clock.Now()
+		}
+		lastHB[node] = t
+		d, ok := r.phi[node]
+		if !ok {
+			d = NewPhiDetector(r.cfg.PhiWindow, r.cfg.PhiMinStdDev)
+		}
+		phi[node] = d
+		probes[node] = r.probes[node]
+		if st, ok := r.stats[node]; ok {
+			stats[node] = st
+		}
+		if st, ok := r.states[node]; ok {
+			states[node] = st
+		}
+	}
+	r.nodes = append([]storage.ServiceAddr(nil), nodes...)
+	r.lastHB = lastHB
+	r.phi = phi
+	r.probes = probes
+	r.stats = stats
+	r.states = states
+	r.epoch = epoch
+	r.notify()
+}
+
+// notify sends the current topology to watchers dropping
+// the previous one if it was not received yet.
+// Must be called with the lock held.
+func (r *Router) notify() {
+	t := r.topology()
+	for ch := range r.watchers {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- t
+	}
+}
+
+func (r *Router) topology() Topology {
+	return Topology{
+		Epoch:   r.epoch,
+		Nodes:   append([]storage.ServiceAddr(nil), r.nodes...),
+		Weights: copyWeights(r.weights),
+		Domains: copyDomains(r.domains),
+		States:  copyStates(r.states),
+		IDs:     copyIDs(r.ids),
+	}
+}
+
+func copyDomains(domains map[storage.ServiceAddr]Domain) map[storage.ServiceAddr]Domain {
+	if len(domains) == 0 {
+		return nil
+	}
+	ret := make(map[storage.ServiceAddr]Domain, len(domains))
+	for node, d := range domains {
+		ret[node] = d
+	}
+	return ret
+}
+
+func copyWeights(weights map[storage.ServiceAddr]float64) map[storage.ServiceAddr]float64 {
+	if len(weights) == 0 {
+		return nil
+	}
+	ret := make(map[storage.ServiceAddr]float64, len(weights))
+	for node, w := range weights {
+		ret[node] = w
+	}
+	return ret
+}
+
+// Topology returns the current topology.
+//
+// Topology возвращает текущую топологию.
+func (r *Router) Topology() Topology {
+	r.RLock()
+	defer r.RUnlock()
+	return r.topology()
+}
+
+// Watch returns a channel receiving the current topology and then
+// the topology after each change. Intermediate changes may be skipped
+// if the receiver is slow. The returned function stops watching.
+//
+// Watch возвращает канал, в который приходит текущая топология, а затем
+// топология после каждого изменения. Промежуточные изменения могут быть
+// пропущены, если получатель не успевает. Возвращаемая функция прекращает
+// наблюдение.
+func (r *Router) Watch() (<-chan Topology, func()) {
+	ch := make(chan Topology, 1)
+	r.Lock()
+	r.watchers[ch] = struct{}{}
+	ch <- r.topology()
+	r.Unlock()
+	return ch, func() {
+		r.Lock()
+		delete(r.watchers, ch)
+		r.Unlock()
 	}
-	return ret, nil
 }
 
-// List returns a list of all nodes served by Router.
+// State is a part of the Router state replicated among routers of the cluster.
+//
+// State -- часть состояния Router, реплицируемая между router кластера.
+type State struct {
+	Topology
+
+	// Heartbeats maps a node to time passed since its last heartbeat.
+	// Heartbeats -- время, прошедшее с последнего heartbeat каждой node.
+	Heartbeats map[storage.ServiceAddr]time.Duration
+
+	// Identities maps a node to its identity including identities
+	// of approved nodes, see Router.ApproveNode.
+	// Identities -- идентичности node, включая идентичности одобренных
+	// node, см. Router.ApproveNode.
+	Identities map[storage.ServiceAddr]Identity
+
+	// Pending lists nodes waiting for approval, see Router.Pending.
+	// Pending -- node, ожидающие одобрения, см. Router.Pending.
+	Pending []PendingNode
+
+	// PendingIdentities maps a pending node to the identity it presented.
+	// PendingIdentities -- идентичности, предъявленные ожидающими node.
+	PendingIdentities map[storage.ServiceAddr]Identity
+
+	// Rejected maps a rejected node to the identity it presented,
+	// see Router.RejectNode.
+	// Rejected -- идентичности, предъявленные отклоненными node,
+	// см. Router.RejectNode.
+	Rejected map[storage.ServiceAddr]Identity
+}
+
+// State returns a snapshot of the replicated Router state.
 //
-// List возвращает cписок всех node, обслуживаемых Router.
+// State возвращает снимок реплицируемого состояния Router.
+func (r *Router) State() State {
+	r.RLock()
+	defer r.RUnlock()
+	tNow := r.clock.Now()
+	st := State{
+		Topology:   r.topology(),
+		Heartbeats: make(map[storage.ServiceAddr]time.Duration, len(r.lastHB)),
+		Identities: copyIdentities(r.idents),
+	}
+	for node, t := range r.lastHB {
+		st.Heartbeats[node] = tNow.Sub(t)
+	}
+	r.pendingState(&st, tNow)
+	return st
+}
+
+// SetState replaces the replicated Router state with st received from a leader.
+// Heartbeat history of failure detectors is not replicated, so they are reset:
+// history a follower gathered earlier is stale, and a newly elected leader
+// learns intervals between heartbeats anew.
+//
+// SetState заменяет реплицируемое состояние Router на st, полученное от лидера.
+// История heartbeats детекторов отказов не реплицируется, поэтому детекторы
+// сбрасываются: история, собранная ранее последователем, устарела, и новый
+// лидер изучает интервалы между heartbeats заново.
+func (r *Router) SetState(st State) {
+	r.Lock()
+	defer r.Unlock()
+	if st.Epoch != r.epoch && len(st.Nodes) > 0 {
+		r.readdress(st.Topology)
+		r.weights = copyWeights(st.Weights)
+		r.domains = copyDomains(st.Domains)
+		r.states = copyStates(st.States)
+		r.ids = copyIDs(st.IDs)
+		r.setNodes(st.Nodes, st.Epoch)
+	}
+	tNow := r.clock.Now()
+	if len(st.Nodes) > 0 {
+		r.idents = copyIdentities(st.Identities)
+		r.setPendingState(st, tNow)
+	}
+	for node, age := range st.Heartbeats {
+		if _, ok := r.lastHB[node]; ok {
+			r.lastHB[node] = tNow.Add(-age)
+		}
+	}
+	for node := range r.phi {
+		r.phi[node] = NewPhiDetector(r.cfg.PhiWindow, r.cfg.PhiMinStdDev)
+	}
+}
+
+// List returns a list of nodes served by Router records are placed on,
+// i.e. all nodes except draining and joining ones.
+//
+// List возвращает cписок node, обслуживаемых Router, на которых
+// размещаются записи, т.е. всех node, кроме draining и joining.
 func (r *Router) List() []storage.ServiceAddr {
-	return r.cfg.Nodes
+	r.RLock()
+	defer r.RUnlock()
+	return r.topology().Serving().Nodes
 }
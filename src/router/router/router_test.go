@@ -1,10 +1,13 @@
 package router
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"reflect"
 	"runtime"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -213,3 +216,738 @@ func TestRouterNodesFind_SameHashes(t *testing.T) {
 		}
 	}
 }
+
+func TestState(t *testing.T) {
+	r, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	r.SetState(State{
+		Heartbeats: map[storage.ServiceAddr]time.Duration{
+			"node1":   0,
+			"node2":   2 * cfg.ForgetTimeout,
+			"node3":   2 * cfg.ForgetTimeout,
+			"unknown": 0,
+		},
+	})
+
+	st := r.State()
+	if _, ok := st.Heartbeats["unknown"]; ok {
+		t.Errorf("State() contains unknown node")
+	}
+	if _, err := r.NodesFind(1); err != storage.ErrNotEnoughDaemons {
+		t.Errorf("NodesFind() got error %v, want %v", err, storage.ErrNotEnoughDaemons)
+	}
+}
+
+func TestSetNodes(t *testing.T) {
+	r, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	ch, cancel := r.Watch()
+	defer cancel()
+	if got := <-ch; got.Epoch != 1 || !equalNodes(got.Nodes, cfg.Nodes) {
+		t.Fatalf("Watch() got %+v, want epoch 1 and nodes %v", got, cfg.Nodes)
+	}
+
+	if err := r.SetNodes(cfg.Nodes[:2]); err != storage.ErrNotEnoughDaemons {
+		t.Errorf("SetNodes() got error %v, want %v", err, storage.ErrNotEnoughDaemons)
+	}
+
+	nodes := []storage.ServiceAddr{"node2", "node3", "node4"}
+	if err := r.SetNodes(nodes); err != nil {
+		t.Fatalf("SetNodes() error: %v", err)
+	}
+	if got := <-ch; got.Epoch != 2 || !equalNodes(got.Nodes, nodes) {
+		t.Errorf("Watch() got %+v, want epoch 2 and nodes %v", got, nodes)
+	}
+	if got := r.List(); !equalNodes(got, nodes) {
+		t.Errorf("List() got %v, want %v", got, nodes)
+	}
+	if err := r.Heartbeat("node1"); err != storage.ErrUnknownDaemon {
+		t.Errorf("Heartbeat() got error %v, want %v", err, storage.ErrUnknownDaemon)
+	}
+	if err := r.Heartbeat("node4"); err != nil {
+		t.Errorf("Heartbeat() error: %v", err)
+	}
+
+	st := r.State()
+	r2, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	r2.SetState(st)
+	if got := r2.Topology(); got.Epoch != 2 || !equalNodes(got.Nodes, nodes) {
+		t.Errorf("Topology() after SetState() got %+v, want epoch 2 and nodes %v", got, nodes)
+	}
+}
+
+func TestRouterNodesFind_Phi(t *testing.T) {
+	clock := &FakeClock{t: time.Unix(0, 0)}
+	c := cfg
+	c.Nodes = []storage.ServiceAddr{"node1", "node2", "node3"}
+	c.ForgetTimeout = time.Hour
+	c.PhiThreshold = 8
+	c.PhiMinStdDev = 10 * time.Millisecond
+	c.Clock = clock
+	r, err := New(c)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		registerNodes(t, r, c.Nodes, 0)
+		clock.Advance(100 * time.Millisecond)
+	}
+	if nodes, err := r.NodesFind(1); err != nil || len(nodes) != 3 {
+		t.Fatalf("NodesFind() got %v, %v, want 3 nodes", nodes, err)
+	}
+
+	for i := 0; i < 10; i++ {
+		registerNodes(t, r, c.Nodes[:1], 0)
+		clock.Advance(100 * time.Millisecond)
+	}
+	phi, err := r.Phi("node2")
+	if err != nil {
+		t.Fatalf("Phi() error: %v", err)
+	}
+	if phi < c.PhiThreshold {
+		t.Errorf("Phi() got %v, want at least %v", phi, c.PhiThreshold)
+	}
+	if _, err := r.NodesFind(1); err != storage.ErrNotEnoughDaemons {
+		t.Errorf("NodesFind() got error %v, want %v", err, storage.ErrNotEnoughDaemons)
+	}
+	if _, err := r.Phi("unknown"); err != storage.ErrUnknownDaemon {
+		t.Errorf("Phi() got error %v, want %v", err, storage.ErrUnknownDaemon)
+	}
+
+	// the leader has got heartbeats of node2 the follower has not
+	r.SetState(State{Topology: r.Topology(), Heartbeats: map[storage.ServiceAddr]time.Duration{"node2": 0}})
+	if phi, err := r.Phi("node2"); err != nil || phi != 0 {
+		t.Errorf("Phi() after SetState() got %v, %v, want 0", phi, err)
+	}
+	if nodes, err := r.NodesFind(1); err != nil || len(nodes) != 3 {
+		t.Errorf("NodesFind() after SetState() got %v, %v, want 3 nodes", nodes, err)
+	}
+	// the first interval after SetState() is not learned
+	clock.Advance(100 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		registerNodes(t, r, c.Nodes, 0)
+		clock.Advance(100 * time.Millisecond)
+	}
+	if phi, err := r.Phi("node2"); err != nil || phi >= 1 {
+		t.Errorf("Phi() with regular heartbeats after SetState() got %v, %v, want less than 1", phi, err)
+	}
+}
+
+type FakeProber struct {
+	sync.Mutex
+	errors map[storage.ServiceAddr]error
+	block  chan struct{}
+}
+
+func (p *FakeProber) Ping(node storage.ServiceAddr) error {
+	p.Lock()
+	err := p.errors[node]
+	p.Unlock()
+	if err == errBlock {
+		<-p.block
+	}
+	return err
+}
+
+var errBlock = errors.New("blocked")
+
+func TestStatus(t *testing.T) {
+	clock := &FakeClock{t: time.Unix(0, 0)}
+	prober := &FakeProber{
+		errors: make(map[storage.ServiceAddr]error),
+		block:  make(chan struct{}),
+	}
+	defer close(prober.block)
+	c := cfg
+	c.Nodes = []storage.ServiceAddr{"node1", "node2", "node3", "node4"}
+	c.NodesFinder = NewNodesFinder(FakeHasher{
+		t:      t,
+		hashes: map[storage.ServiceAddr]uint64{"node1": 1, "node2": 2, "node3": 3, "node4": 4},
+	})
+	c.ForgetTimeout = time.Second
+	c.Clock = clock
+	c.Prober = prober
+	c.ProbeInterval = time.Hour
+	c.ProbeTimeout = 50 * time.Millisecond
+	r, err := New(c)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	for _, node := range c.Nodes {
+		if st, err := r.Status(node); err != nil || st != NodeAlive {
+			t.Errorf("Status(%v) before probes got %v, %v, want %v", node, st, err, NodeAlive)
+		}
+	}
+	if _, err := r.Status("unknown"); err != storage.ErrUnknownDaemon {
+		t.Errorf("Status() got error %v, want %v", err, storage.ErrUnknownDaemon)
+	}
+
+	// node1 is healthy, node2 is wedged, node3 does not send heartbeats,
+	// node4 neither sends heartbeats nor passes probes.
+	prober.errors["node2"] = errBlock
+	prober.errors["node4"] = errors.New("connection refused")
+	clock.Advance(2 * c.ForgetTimeout)
+	registerNodes(t, r, c.Nodes[:2], 0)
+	r.probe()
+
+	for node, want := range map[storage.ServiceAddr]NodeStatus{
+		"node1": NodeAlive,
+		"node2": NodeDegraded,
+		"node3": NodeDegraded,
+		"node4": NodeDead,
+	} {
+		if st, err := r.Status(node); err != nil || st != want {
+			t.Errorf("Status(%v) got %v, %v, want %v", node, st, err, want)
+		}
+	}
+	if _, err := r.NodesFind(1); err != storage.ErrNotEnoughDaemons {
+		t.Errorf("NodesFind() got error %v, want %v", err, storage.ErrNotEnoughDaemons)
+	}
+}
+
+func TestClusterStatus(t *testing.T) {
+	clock := &FakeClock{t: time.Unix(0, 0)}
+	c := cfg
+	c.Nodes = []storage.ServiceAddr{"node1", "node2", "node3"}
+	c.ForgetTimeout = time.Second
+	c.Clock = clock
+	r, err := New(c)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	clock.Advance(2 * c.ForgetTimeout)
+	st := storage.Stats{Records: 10, Bytes: 100, PutRate: 1.5, Version: "v1"}
+	if err := r.HeartbeatStats("node1", st); err != nil {
+		t.Fatalf("HeartbeatStats() error: %v", err)
+	}
+	registerNodes(t, r, c.Nodes[1:2], 0)
+	if err := r.HeartbeatStats("unknown", st); err != storage.ErrUnknownDaemon {
+		t.Errorf("HeartbeatStats() got error %v, want %v", err, storage.ErrUnknownDaemon)
+	}
+	clock.Advance(100 * time.Millisecond)
+
+	want := []NodeInfo{
+		{Node: "node1", HeartbeatAge: 100 * time.Millisecond, Status: NodeAlive, Stats: st},
+		{Node: "node2", HeartbeatAge: 100 * time.Millisecond, Status: NodeAlive},
+		{Node: "node3", HeartbeatAge: 2*c.ForgetTimeout + 100*time.Millisecond, Status: NodeDead},
+	}
+	if got := r.ClusterStatus(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ClusterStatus() got %+v, want %+v", got, want)
+	}
+}
+
+func TestAlive(t *testing.T) {
+	clock := &FakeClock{t: time.Unix(0, 0)}
+	c := cfg
+	c.Nodes = []storage.ServiceAddr{"node1", "node2", "node3"}
+	c.ForgetTimeout = time.Second
+	c.Clock = clock
+	r, err := New(c)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	clock.Advance(2 * c.ForgetTimeout)
+	registerNodes(t, r, c.Nodes[:2], 0)
+	nodes, epoch := r.Alive()
+	if want := c.Nodes[:2]; !reflect.DeepEqual(nodes, want) {
+		t.Errorf("Alive() got %v, want %v", nodes, want)
+	}
+	if want := r.Topology().Epoch; epoch != want {
+		t.Errorf("Alive() got epoch %v, want %v", epoch, want)
+	}
+}
+
+func TestSetNodeState(t *testing.T) {
+	c := cfg
+	c.Nodes = []storage.ServiceAddr{"node1", "node2", "node3", "node4"}
+	c.NodesFinder = NewNodesFinder(FakeHasher{
+		t: t,
+		hashes: map[storage.ServiceAddr]uint64{
+			"node1": 1,
+			"node2": 2,
+			"node3": 3,
+			"node4": 4,
+		}})
+	c.ForgetTimeout = time.Hour
+	r, err := New(c)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	registerNodes(t, r, c.Nodes, 0)
+
+	if err := r.SetNodeState("unknown", NodeDraining); err != storage.ErrUnknownDaemon {
+		t.Errorf("SetNodeState() got error %v, want %v", err, storage.ErrUnknownDaemon)
+	}
+
+	if err := r.SetNodeState("node4", NodeDraining); err != nil {
+		t.Fatalf("SetNodeState() error: %v", err)
+	}
+	if got := r.Topology(); got.Epoch != 2 || got.States["node4"] != NodeDraining {
+		t.Errorf("Topology() got %+v, want epoch 2 and draining node4", got)
+	}
+	want := []storage.ServiceAddr{"node3", "node2", "node1"}
+	if got, err := r.NodesFind(1); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("NodesFind() with draining node4 got %v, %v, want %v", got, err, want)
+	}
+	if got := r.List(); !equalNodes(got, want) {
+		t.Errorf("List() got %v, want %v", got, want)
+	}
+
+	if err := r.SetNodeState("node3", NodeReadOnly); err != nil {
+		t.Fatalf("SetNodeState() error: %v", err)
+	}
+	want = []storage.ServiceAddr{"node2", "node1"}
+	if got, err := r.NodesFind(1); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("NodesFind() with read-only node3 got %v, %v, want %v", got, err, want)
+	}
+	if err := r.SetNodeState("node2", NodeMaintenance); err != nil {
+		t.Fatalf("SetNodeState() error: %v", err)
+	}
+	if _, err := r.NodesFind(1); err != storage.ErrNotEnoughDaemons {
+		t.Errorf("NodesFind() got error %v, want %v", err, storage.ErrNotEnoughDaemons)
+	}
+
+	r2, err := New(c)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	r2.SetState(r.State())
+	if st, err := r2.NodeState("node3"); err != nil || st != NodeReadOnly {
+		t.Errorf("NodeState() after SetState() got %v, %v, want %v", st, err, NodeReadOnly)
+	}
+
+	if err := r.SetNodeState("node2", NodeActive); err != nil {
+		t.Fatalf("SetNodeState() error: %v", err)
+	}
+	for _, info := range r.ClusterStatus() {
+		if st, _ := r.NodeState(info.Node); info.State != st {
+			t.Errorf("ClusterStatus() got state %v of %v, want %v", info.State, info.Node, st)
+		}
+	}
+	if st, err := r.NodeState("node2"); err != nil || st != NodeActive {
+		t.Errorf("NodeState() got %v, %v, want %v", st, err, NodeActive)
+	}
+
+	if err := r.SetNodeState("node4", NodeMaintenance); err != ErrNodeNotEmpty {
+		t.Errorf("SetNodeState() of draining node4 without stats got error %v, want %v", err, ErrNodeNotEmpty)
+	}
+	if err := r.HeartbeatStats("node4", storage.Stats{Records: 1}); err != nil {
+		t.Fatalf("HeartbeatStats() error: %v", err)
+	}
+	if err := r.SetNodeState("node4", NodeMaintenance); err != ErrNodeNotEmpty {
+		t.Errorf("SetNodeState() of non-empty draining node4 got error %v, want %v", err, ErrNodeNotEmpty)
+	}
+	if err := r.HeartbeatStats("node4", storage.Stats{}); err != nil {
+		t.Fatalf("HeartbeatStats() error: %v", err)
+	}
+	if err := r.SetNodeState("node4", NodeMaintenance); err != nil {
+		t.Errorf("SetNodeState() of empty draining node4 error: %v", err)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	c := cfg
+	r, err := New(c)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if err := r.Authenticate("node1", Identity{}, ""); err != nil {
+		t.Errorf("Authenticate() without identities error: %v", err)
+	}
+
+	lookupHost = func(host string) ([]string, error) {
+		if host == "node.local" {
+			return []string{"10.0.0.2", "fe80::1"}, nil
+		}
+		return nil, fmt.Errorf("unknown host %v", host)
+	}
+	defer func() { lookupHost = net.LookupHost }()
+
+	c.Nodes = []storage.ServiceAddr{"10.0.0.1:7321", "node.local:7322", "10.0.0.3:7323"}
+	c.Identities = map[storage.ServiceAddr]Identity{
+		"10.0.0.1:7321":   {ID: "node1", Token: "secret1"},
+		"node.local:7322": {ID: "node2", Token: "secret2"},
+	}
+	r, err = New(c)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	for _, test := range []struct {
+		node storage.ServiceAddr
+		id   Identity
+		from string
+		err  error
+	}{
+		{"10.0.0.1:7321", Identity{"node1", "secret1"}, "10.0.0.1", nil},
+		{"node.local:7322", Identity{"node2", "secret2"}, "10.0.0.2", nil},
+		{"node.local:7322", Identity{"node2", "secret2"}, "fe80::1", nil},
+		{"10.0.0.1:7321", Identity{"node1", "wrong"}, "10.0.0.1", storage.ErrUnauthenticated},
+		{"10.0.0.1:7321", Identity{"node2", "secret2"}, "10.0.0.1", storage.ErrUnauthenticated},
+		{"10.0.0.1:7321", Identity{"node1", "secret1"}, "10.0.0.9", storage.ErrUnauthenticated},
+		{"10.0.0.1:7321", Identity{"node1", "secret1"}, "", storage.ErrUnauthenticated},
+		{"node.local:7322", Identity{"node2", "secret2"}, "10.0.0.1", storage.ErrUnauthenticated},
+		{"10.0.0.3:7323", Identity{}, "10.0.0.3", storage.ErrUnauthenticated},
+	} {
+		if err := r.Authenticate(test.node, test.id, test.from); err != test.err {
+			t.Errorf("Authenticate(%v, %v, %v) got error %v, want %v", test.node, test.id, test.from, err, test.err)
+		}
+	}
+}
+
+func TestAuthenticate_Pending(t *testing.T) {
+	c := cfg
+	c.Clock = &FakeClock{t: time.Unix(0, 0)}
+	c.ForgetTimeout = time.Hour
+	c.Nodes = []storage.ServiceAddr{"10.0.0.1:7321", "10.0.0.2:7322", "10.0.0.3:7323"}
+	c.Identities = map[storage.ServiceAddr]Identity{
+		"10.0.0.1:7321": {ID: "node1", Token: "secret1"},
+		"10.0.0.2:7322": {ID: "node2", Token: "secret2"},
+		"10.0.0.3:7323": {ID: "node3", Token: "secret3"},
+	}
+	r, err := New(c)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	r2, err := New(c)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	for _, test := range []struct {
+		node storage.ServiceAddr
+		id   Identity
+		from string
+		err  error
+	}{
+		{"10.0.0.4:7324", Identity{}, "10.0.0.4", storage.ErrUnauthenticated},
+		{"10.0.0.4:7324", Identity{"node4", ""}, "10.0.0.4", storage.ErrUnauthenticated},
+		{"10.0.0.4:7324", Identity{"node1", "secret4"}, "10.0.0.4", storage.ErrUnauthenticated},
+		{"10.0.0.4:7324", Identity{"node4", "secret4"}, "10.0.0.9", storage.ErrUnauthenticated},
+		{"10.0.0.4:7324", Identity{"node4", "secret4"}, "10.0.0.4", nil},
+		{"10.0.0.5:7325", Identity{"node5", "secret5"}, "10.0.0.5", nil},
+	} {
+		if err := r.Authenticate(test.node, test.id, test.from); err != test.err {
+			t.Errorf("Authenticate(%v, %v, %v) got error %v, want %v", test.node, test.id, test.from, err, test.err)
+		}
+	}
+	if err := r.Heartbeat("10.0.0.4:7324"); err != storage.ErrUnknownDaemon {
+		t.Errorf("Heartbeat() of a pending node got error %v, want %v", err, storage.ErrUnknownDaemon)
+	}
+	want := []PendingNode{
+		{Node: "10.0.0.4:7324", ID: "node4"},
+		{Node: "10.0.0.5:7325", ID: "node5"},
+	}
+	if got := r.Pending(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Pending() got %+v, want %+v", got, want)
+	}
+
+	if err := r.ApproveNode("10.0.0.4:7324", true); err != nil {
+		t.Fatalf("ApproveNode() error: %v", err)
+	}
+	if err := r.Authenticate("10.0.0.4:7324", Identity{"node4", "wrong"}, "10.0.0.4"); err != storage.ErrUnauthenticated {
+		t.Errorf("Authenticate() of an approved node with a wrong token got error %v, want %v", err, storage.ErrUnauthenticated)
+	}
+	if err := r.Authenticate("10.0.0.4:7324", Identity{"node4", "secret4"}, "10.0.0.4"); err != nil {
+		t.Errorf("Authenticate() of an approved node error: %v", err)
+	}
+
+	// a rejected node keeps the identity it presents
+	if err := r.RejectNode("10.0.0.5:7325"); err != nil {
+		t.Fatalf("RejectNode() error: %v", err)
+	}
+	if err := r.Authenticate("10.0.0.5:7325", Identity{"node5", "other5"}, "10.0.0.5"); err != nil {
+		t.Errorf("Authenticate() of a rejected node error: %v", err)
+	}
+	if err := r.ApproveNode("10.0.0.5:7325", true); err != nil {
+		t.Fatalf("ApproveNode() error: %v", err)
+	}
+	if err := r.Authenticate("10.0.0.5:7325", Identity{"node5", "other5"}, "10.0.0.5"); err != nil {
+		t.Errorf("Authenticate() of an approved rejected node error: %v", err)
+	}
+
+	// approved identities survive reloads and are replicated
+	c.ForgetTimeout = 2 * time.Hour
+	c.Identities = map[storage.ServiceAddr]Identity{
+		"10.0.0.1:7321": {ID: "node1", Token: "secret1"},
+		"10.0.0.2:7322": {ID: "node2", Token: "secret2"},
+	}
+	if err := r.Reload(c); err != nil {
+		t.Fatalf("Reload() error: %v", err)
+	}
+	r2.SetState(r.State())
+	for _, rtr := range []*Router{r, r2} {
+		if err := rtr.Authenticate("10.0.0.4:7324", Identity{"node4", "secret4"}, "10.0.0.4"); err != nil {
+			t.Errorf("Authenticate() of an approved node error: %v", err)
+		}
+		if err := rtr.Authenticate("10.0.0.3:7323", Identity{"node3", "secret3"}, "10.0.0.3"); err != storage.ErrUnauthenticated {
+			t.Errorf("Authenticate() of a node without identity got error %v, want %v", err, storage.ErrUnauthenticated)
+		}
+	}
+}
+
+func TestSetNodeAddr(t *testing.T) {
+	c := cfg
+	c.ForgetTimeout = time.Hour
+	c.Identities = map[storage.ServiceAddr]Identity{
+		"node1": {ID: "node1", Token: "secret1"},
+	}
+	r, err := New(c)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	r2, err := New(c)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	registerNodes(t, r, c.Nodes, 0)
+	if err := r.HeartbeatStats("node1", storage.Stats{Records: 1}); err != nil {
+		t.Fatalf("HeartbeatStats() error: %v", err)
+	}
+
+	if err := r.SetNodeAddr("unknown", "node4"); err != storage.ErrUnknownDaemon {
+		t.Errorf("SetNodeAddr() got error %v, want %v", err, storage.ErrUnknownDaemon)
+	}
+	if err := r.SetNodeAddr("node1", "node2"); err == nil {
+		t.Errorf("SetNodeAddr() to a served address succeeded")
+	}
+	if err := r.SetNodeAddr("node1", "node4"); err != nil {
+		t.Fatalf("SetNodeAddr() error: %v", err)
+	}
+
+	want := []storage.ServiceAddr{"node3", "node2", "node4"}
+	if got, err := r.NodesFind(1); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("NodesFind() got %v, %v, want %v", got, err, want)
+	}
+	if got := r.Topology(); got.Epoch != 2 || got.IDs["node4"] != "node1" {
+		t.Errorf("Topology() got %+v, want epoch 2 and node4 with ID node1", got)
+	}
+	if err := r.Heartbeat("node1"); err != storage.ErrUnknownDaemon {
+		t.Errorf("Heartbeat() got error %v, want %v", err, storage.ErrUnknownDaemon)
+	}
+	if _, ok := r.idents["node4"]; !ok {
+		t.Errorf("identity of node1 was not moved to node4")
+	}
+	for _, info := range r.ClusterStatus() {
+		if info.Node == "node4" && info.Stats.Records != 1 {
+			t.Errorf("ClusterStatus() got stats %+v of node4, want stats of node1", info.Stats)
+		}
+	}
+
+	r2.SetState(r.State())
+	if got, err := r2.NodesFind(1); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("NodesFind() after SetState() got %v, %v, want %v", got, err, want)
+	}
+	if _, err := r2.Status("node4"); err != nil {
+		t.Errorf("Status() after SetState() error: %v", err)
+	}
+	if _, ok := r2.idents["node4"]; !ok {
+		t.Errorf("identity of node1 was not moved to node4 after SetState()")
+	}
+}
+
+func TestReload(t *testing.T) {
+	r, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	ch, cancel := r.Watch()
+	defer cancel()
+	<-ch
+
+	c := cfg
+	c.ForgetTimeout = time.Hour
+	if err := r.Reload(c); err != nil {
+		t.Fatalf("Reload() error: %v", err)
+	}
+	if got := r.Config(); got.ForgetTimeout != time.Hour || got.NodesFinder.placement == nil {
+		t.Errorf("Config() after Reload() got %+v, want forget timeout %v", got, time.Hour)
+	}
+	select {
+	case got := <-ch:
+		t.Errorf("Watch() got %+v after Reload() not changing topology", got)
+	default:
+	}
+
+	c.Weights = map[storage.ServiceAddr]float64{"node1": 2}
+	c.Addr = "other"
+	err = r.Reload(c)
+	if e, ok := err.(storage.RestartError); !ok || !reflect.DeepEqual(e.Fields, []string{"addr"}) {
+		t.Errorf("Reload() got error %v, want restart of addr", err)
+	}
+	if got := <-ch; got.Epoch != 2 || got.Weights["node1"] != 2 {
+		t.Errorf("Watch() got %+v, want epoch 2 and new weights", got)
+	}
+	if got := r.Config(); got.Addr != cfg.Addr {
+		t.Errorf("Config() got addr %v, want %v", got.Addr, cfg.Addr)
+	}
+
+	if err := r.SetNodeState("node2", NodeDraining); err != nil {
+		t.Fatalf("SetNodeState() error: %v", err)
+	}
+	<-ch
+	c.Addr = cfg.Addr
+	c.Nodes = c.Nodes[:2]
+	if err := r.Reload(c); err != storage.ErrNotEnoughDaemons {
+		t.Errorf("Reload() got error %v, want %v", err, storage.ErrNotEnoughDaemons)
+	}
+	c.Nodes = []storage.ServiceAddr{"node1", "node2", "node3", "node4"}
+	if err := r.Reload(c); err != nil {
+		t.Fatalf("Reload() error: %v", err)
+	}
+	if got := <-ch; got.Epoch != 4 || !equalNodes(got.Nodes, c.Nodes) || got.States["node2"] != NodeDraining {
+		t.Errorf("Watch() got %+v, want epoch 4, nodes %v and draining node2", got, c.Nodes)
+	}
+
+	// runtime changes of nodes survive reloads
+	if err := r.SetNodeAddr("node3", "node3b"); err != nil {
+		t.Fatalf("SetNodeAddr() error: %v", err)
+	}
+	<-ch
+	c.ForgetTimeout = 2 * time.Hour
+	if err := r.Reload(c); err != nil {
+		t.Fatalf("Reload() error: %v", err)
+	}
+	want := []storage.ServiceAddr{"node1", "node2", "node3b", "node4"}
+	if got := r.Topology(); got.Epoch != 5 || !equalNodes(got.Nodes, want) {
+		t.Errorf("Topology() after Reload() got %+v, want epoch 5 and nodes %v", got, want)
+	}
+	c.Nodes = []storage.ServiceAddr{"node1", "node3", "node4", "node5"}
+	if err := r.Reload(c); err != nil {
+		t.Fatalf("Reload() error: %v", err)
+	}
+	want = []storage.ServiceAddr{"node1", "node3b", "node4", "node5"}
+	if got := <-ch; got.Epoch != 6 || !equalNodes(got.Nodes, want) {
+		t.Errorf("Watch() got %+v, want epoch 6 and nodes %v", got, want)
+	}
+	c.Nodes = []storage.ServiceAddr{"node1", "node3", "node6"}
+	if err := r.Reload(c); err != nil {
+		t.Fatalf("Reload() error: %v", err)
+	}
+	want = []storage.ServiceAddr{"node1", "node3b", "node6"}
+	if got := <-ch; got.Epoch != 7 || !equalNodes(got.Nodes, want) {
+		t.Errorf("Watch() got %+v, want epoch 7 and nodes %v", got, want)
+	}
+}
+
+func TestReloadFollower(t *testing.T) {
+	leader, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	r, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	ch, cancel := r.Watch()
+	defer cancel()
+	<-ch
+
+	c := cfg
+	c.ForgetTimeout = time.Hour
+	c.Nodes = []storage.ServiceAddr{"node1", "node2", "node3", "node4"}
+	c.Weights = map[storage.ServiceAddr]float64{"node1": 2}
+	if err := r.ReloadFollower(c); err != nil {
+		t.Fatalf("ReloadFollower() error: %v", err)
+	}
+	if got := r.Config(); got.ForgetTimeout != time.Hour || !equalNodes(got.Nodes, cfg.Nodes) || got.Weights != nil {
+		t.Errorf("Config() after ReloadFollower() got %+v, want forget timeout %v and nodes %v", got, time.Hour, cfg.Nodes)
+	}
+	select {
+	case got := <-ch:
+		t.Errorf("Watch() got %+v after ReloadFollower()", got)
+	default:
+	}
+
+	// the change made by the leader is replicated
+	if err := leader.Reload(c); err != nil {
+		t.Fatalf("Reload() error: %v", err)
+	}
+	r.SetState(leader.State())
+	if got := <-ch; got.Epoch != 2 || !equalNodes(got.Nodes, c.Nodes) || got.Weights["node1"] != 2 {
+		t.Errorf("Watch() got %+v, want epoch 2, nodes %v and new weights", got, c.Nodes)
+	}
+}
+
+func TestPending(t *testing.T) {
+	clock := &FakeClock{t: time.Unix(0, 0)}
+	c := cfg
+	c.Clock = clock
+	c.ForgetTimeout = time.Hour
+	c.AutoApprove = []string{"10.0.0.0/8"}
+	r, err := New(c)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	registerNodes(t, r, c.Nodes, 0)
+
+	if err := r.Heartbeat("192.168.0.1:7000"); err != storage.ErrUnknownDaemon {
+		t.Errorf("Heartbeat() got error %v, want %v", err, storage.ErrUnknownDaemon)
+	}
+	clock.Advance(time.Second)
+	if err := r.HeartbeatStats("192.168.0.2:7000", storage.Stats{}); err != storage.ErrUnknownDaemon {
+		t.Errorf("HeartbeatStats() got error %v, want %v", err, storage.ErrUnknownDaemon)
+	}
+	want := []PendingNode{
+		{Node: "192.168.0.1:7000", Age: time.Second, HeartbeatAge: time.Second},
+		{Node: "192.168.0.2:7000"},
+	}
+	if got := r.Pending(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Pending() got %+v, want %+v", got, want)
+	}
+
+	if err := r.ApproveNode("unknown", true); err != storage.ErrUnknownDaemon {
+		t.Errorf("ApproveNode() got error %v, want %v", err, storage.ErrUnknownDaemon)
+	}
+	if err := r.ApproveNode("192.168.0.1:7000", false); err != nil {
+		t.Fatalf("ApproveNode() error: %v", err)
+	}
+	if got := r.Topology(); got.Epoch != 2 || len(got.Nodes) != 4 || got.States["192.168.0.1:7000"] != NodeJoining {
+		t.Errorf("Topology() after ApproveNode() got %+v, want epoch 2 and the joining node", got)
+	}
+	if err := r.Heartbeat("192.168.0.1:7000"); err != nil {
+		t.Errorf("Heartbeat() of an approved node error: %v", err)
+	}
+
+	if err := r.RejectNode("192.168.0.2:7000"); err != nil {
+		t.Fatalf("RejectNode() error: %v", err)
+	}
+	r.Heartbeat("192.168.0.2:7000")
+	if got := r.Pending(); len(got) != 0 {
+		t.Errorf("Pending() after RejectNode() got %+v, want none", got)
+	}
+
+	// the claimed address is not enough for auto-approval
+	if err := r.Heartbeat("10.1.2.3:7000"); err != storage.ErrUnknownDaemon {
+		t.Errorf("Heartbeat() of a node in range got error %v, want %v", err, storage.ErrUnknownDaemon)
+	}
+	if err := r.HeartbeatFrom("10.1.2.3:7000", "192.168.0.9"); err != storage.ErrUnknownDaemon {
+		t.Errorf("HeartbeatFrom() out of range got error %v, want %v", err, storage.ErrUnknownDaemon)
+	}
+	if err := r.HeartbeatFrom("10.1.2.3:7000", "10.1.2.4"); err != storage.ErrUnknownDaemon {
+		t.Errorf("HeartbeatFrom() in range from another host got error %v, want %v", err, storage.ErrUnknownDaemon)
+	}
+	if err := r.HeartbeatFrom("10.1.2.3:7000", "10.1.2.3"); err != nil {
+		t.Errorf("HeartbeatFrom() of an auto-approved node error: %v", err)
+	}
+	if got := r.Topology(); got.Epoch != 3 || len(got.Nodes) != 5 || got.States["10.1.2.3:7000"] != NodeJoining {
+		t.Errorf("Topology() after auto-approval got %+v, want epoch 3 and the joining node", got)
+	}
+
+	if err := r.ApproveNode("192.168.0.2:7000", true); err != nil {
+		t.Fatalf("ApproveNode() of a rejected node error: %v", err)
+	}
+	if got := r.Topology(); len(got.Nodes) != 6 || got.States["192.168.0.2:7000"] != NodeActive {
+		t.Errorf("Topology() after ApproveNode() got %+v, want the active node", got)
+	}
+}
@@ -0,0 +1,176 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+
+	"storage"
+)
+
+// NodeState is an administrative state of a node.
+//
+// NodeState -- административное состояние node.
+type NodeState int
+
+const (
+	// NodeActive means that the node serves reads and writes.
+	// NodeActive -- node обслуживает чтение и запись.
+	NodeActive NodeState = iota
+	// NodeReadOnly means that the node serves reads but gets no writes.
+	// NodeReadOnly -- node обслуживает чтение, но не получает запись.
+	NodeReadOnly
+	// NodeDraining means that the node gets no new records while its data
+	// is handed off to other nodes. Writes are placed as if the node was
+	// removed, but it stays in the placement for reads and deletes.
+	// NodeDraining -- node не получает новых записей, пока ее данные
+	// передаются другим node. Запись размещается так, как будто node
+	// удалена, но для чтения и удаления node остается в размещении.
+	NodeDraining
+	// NodeMaintenance means that the node is taken out temporarily.
+	// It keeps its records but serves neither reads nor writes.
+	// NodeMaintenance -- node временно выведена из работы. Она сохраняет
+	// свои записи, но не обслуживает ни чтение, ни запись.
+	NodeMaintenance
+	// NodeJoining means that the node is approved but gets no records
+	// until data is copied to it. Records are placed as if the node was
+	// not added, so it serves neither reads nor writes.
+	// NodeJoining -- node одобрена, но не получает записей, пока на нее
+	// не скопированы данные. Записи размещаются так, как будто node
+	// не добавлена, поэтому она не обслуживает ни чтение, ни запись.
+	NodeJoining
+)
+
+// ErrNodeNotEmpty is returned by Router.SetNodeState when a draining node
+// which still reports records is put to maintenance.
+//
+// ErrNodeNotEmpty возвращается Router.SetNodeState при переводе
+// в maintenance draining node, которая еще сообщает о записях.
+var ErrNodeNotEmpty = errors.New("Draining node still has records")
+
+var nodeStateNames = []string{"active", "read-only", "draining", "maintenance", "joining"}
+
+func (s NodeState) String() string {
+	if s < 0 || int(s) >= len(nodeStateNames) {
+		return "unknown"
+	}
+	return nodeStateNames[s]
+}
+
+// Writable reports whether the node accepts writes.
+//
+// Writable сообщает, принимает ли node запись.
+func (s NodeState) Writable() bool {
+	return s == NodeActive
+}
+
+// Readable reports whether the node serves reads.
+//
+// Readable сообщает, обслуживает ли node чтение.
+func (s NodeState) Readable() bool {
+	return s != NodeMaintenance && s != NodeJoining
+}
+
+// Serving returns the topology new records are placed on, i.e. t without
+// draining and joining nodes.
+//
+// Serving возвращает топологию, на которой размещаются новые записи,
+// т.е. t без draining и joining node.
+func (t Topology) Serving() Topology {
+	return t.without(func(st NodeState) bool {
+		return st == NodeDraining || st == NodeJoining
+	})
+}
+
+// placed returns the topology existing records are placed on, i.e. t
+// without joining nodes.
+func (t Topology) placed() Topology {
+	return t.without(func(st NodeState) bool {
+		return st == NodeJoining
+	})
+}
+
+// without returns t without nodes whose states match skip.
+func (t Topology) without(skip func(st NodeState) bool) Topology {
+	found := false
+	for _, st := range t.States {
+		if skip(st) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return t
+	}
+	ret := t
+	ret.Nodes = make([]storage.ServiceAddr, 0, len(t.Nodes))
+	for _, node := range t.Nodes {
+		if !skip(t.States[node]) {
+			ret.Nodes = append(ret.Nodes, node)
+		}
+	}
+	return ret
+}
+
+func copyStates(states map[storage.ServiceAddr]NodeState) map[storage.ServiceAddr]NodeState {
+	if len(states) == 0 {
+		return nil
+	}
+	ret := make(map[storage.ServiceAddr]NodeState, len(states))
+	for node, st := range states {
+		ret[node] = st
+	}
+	return ret
+}
+
+// SetNodeState sets the administrative state of node and increments
+// the topology epoch. Returns storage.ErrUnknownDaemon error if node
+// is not served by the Router. A draining node is put to maintenance only
+// once its heartbeats report no records, ErrNodeNotEmpty is returned
+// otherwise.
+//
+// SetNodeState задает административное состояние node и увеличивает
+// эпоху. Возвращает ошибку storage.ErrUnknownDaemon если node не
+// обслуживается Router. Draining node переводится в maintenance, только
+// когда ее heartbeat сообщают об отсутствии записей, иначе возвращается
+// ErrNodeNotEmpty.
+func (r *Router) SetNodeState(node storage.ServiceAddr, st NodeState) error {
+	if st < NodeActive || st > NodeJoining {
+		return fmt.Errorf("Unknown node state %d", st)
+	}
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.lastHB[node]; !ok {
+		return storage.ErrUnknownDaemon
+	}
+	if r.states[node] == st {
+		return nil
+	}
+	if r.states[node] == NodeDraining && st == NodeMaintenance {
+		if stats, ok := r.stats[node]; !ok || stats.Records > 0 {
+			return ErrNodeNotEmpty
+		}
+	}
+	if st == NodeActive {
+		delete(r.states, node)
+	} else {
+		r.states[node] = st
+	}
+	r.epoch++
+	r.notify()
+	return nil
+}
+
+// NodeState returns the administrative state of node.
+// Returns storage.ErrUnknownDaemon error if node is not served by the Router.
+//
+// NodeState возвращает административное состояние node.
+// Возвращает ошибку storage.ErrUnknownDaemon если node не
+// обслуживается Router.
+func (r *Router) NodeState(node storage.ServiceAddr) (NodeState, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if _, ok := r.lastHB[node]; !ok {
+		return NodeActive, storage.ErrUnknownDaemon
+	}
+	return r.states[node], nil
+}
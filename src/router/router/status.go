@@ -0,0 +1,75 @@
+package router
+
+import (
+	"time"
+
+	"storage"
+)
+
+// NodeInfo is a state of a node as seen by the Router.
+//
+// NodeInfo -- состояние node с точки зрения Router.
+type NodeInfo struct {
+	// Node is an address of the node.
+	// Node -- адрес node.
+	Node storage.ServiceAddr
+
+	// HeartbeatAge is time passed since the last heartbeat of the node.
+	// HeartbeatAge -- время, прошедшее с последнего heartbeat node.
+	HeartbeatAge time.Duration
+
+	// Status is a liveness verdict for the node.
+	// Status -- вердикт о доступности node.
+	Status NodeStatus
+
+	// Phi is a suspicion level of the phi-accrual failure detector.
+	// Phi -- уровень подозрения phi-accrual детектора отказов.
+	Phi float64
+
+	// Stats is the latest load statistics reported by the node.
+	// Stats -- последняя статистика нагрузки, присланная node.
+	Stats storage.Stats
+
+	// State is the administrative state of the node.
+	// State -- административное состояние node.
+	State NodeState
+}
+
+// ClusterStatus returns states of all nodes served by the Router.
+//
+// ClusterStatus возвращает состояния всех node, обслуживаемых Router.
+func (r *Router) ClusterStatus() []NodeInfo {
+	r.RLock()
+	defer r.RUnlock()
+	tNow := r.clock.Now()
+	ret := make([]NodeInfo, 0, len(r.nodes))
+	for _, node := range r.nodes {
+		ret = append(ret, NodeInfo{
+			Node:         node,
+			HeartbeatAge: tNow.Sub(r.lastHB[node]),
+			Status:       r.status(node, tNow),
+			Phi:          r.phi[node].Phi(tNow),
+			Stats:        r.stats[node],
+			State:        r.states[node],
+		})
+	}
+	return ret
+}
+
+// Alive returns nodes with the NodeAlive status and the topology epoch
+// they were found at.
+//
+// Alive возвращает node со статусом NodeAlive и эпоху, в которую
+// они были найдены.
+func (r *Router) Alive() ([]storage.ServiceAddr, uint64) {
+	r.RLock()
+	defer r.RUnlock()
+	tNow := r.clock.Now()
+	ret := make([]storage.ServiceAddr, 0, len(r.nodes))
+	for _, node := range r.nodes {
+		if r.status(node, tNow) == NodeAlive {
+			ret = append(ret, node)
+		}
+	}
+	return ret, r.epoch
+}
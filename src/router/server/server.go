@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	yaml "gopkg.in/yaml.v2"
 
+	"router/cluster"
 	"router/pb"
 	"router/router"
 	"storage"
@@ -16,13 +20,20 @@ import (
 type Server struct {
 	addr string
 	rtr  *router.Router
+	cl   *cluster.Cluster
 	srv  *grpc.Server
 }
 
 func New(rtr *router.Router, addr string) *Server {
+	return NewReplicated(rtr, nil, addr)
+}
+
+// NewReplicated creates a Server which serves requests only while cl is a leader.
+func NewReplicated(rtr *router.Router, cl *cluster.Cluster, addr string) *Server {
 	return &Server{
 		addr: addr,
 		rtr:  rtr,
+		cl:   cl,
 		srv:  grpc.NewServer(),
 	}
 }
@@ -42,15 +53,44 @@ func (s *Server) Stop() {
 	s.srv.Stop()
 }
 
+// leader returns storage.ErrNotLeader and an address of the current leader
+// if the router is not the leader of its cluster.
+func (s *Server) leader() (string, error) {
+	if s.cl == nil {
+		return "", nil
+	}
+	leader, ok := s.cl.Leader()
+	if !ok {
+		return string(leader), storage.ErrNotLeader
+	}
+	return string(leader), nil
+}
+
 func (s *Server) Heartbeat(ctx context.Context, req *pb.HBRequest) (*pb.HBReply, error) {
 	node := storage.ServiceAddr(req.Node)
 	log.Printf("Hearbeat request: node = %q", node)
 
-	err := s.rtr.Heartbeat(node)
+	from := peerHost(ctx)
+	leader, err := s.leader()
+	if err == nil {
+		id := router.Identity{ID: req.NodeId, Token: req.Token}
+		err = s.rtr.Authenticate(node, id, from)
+		if err != nil {
+			log.Printf("Rejected heartbeat of %q from %q: %v", node, from, err)
+		}
+	}
+	if err == nil {
+		if req.Stats != nil {
+			err = s.rtr.HeartbeatStatsFrom(node, from, statsFromPB(req.Stats))
+		} else {
+			err = s.rtr.HeartbeatFrom(node, from)
+		}
+	}
 	status := storage.ErrToStatus(err)
 
 	reply := pb.HBReply{
 		Status: int32(status),
+		Leader: leader,
 	}
 	if status == storage.StatusUnknown {
 		reply.Error = err.Error()
@@ -58,15 +98,37 @@ func (s *Server) Heartbeat(ctx context.Context, req *pb.HBRequest) (*pb.HBReply,
 	return &reply, nil
 }
 
+// peerHost returns the host a request was sent from.
+func peerHost(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
 func (s *Server) NodesFind(ctx context.Context, req *pb.NFRequest) (*pb.NFReply, error) {
 	key := storage.RecordID(req.Key)
 	log.Printf("NodesFind request: key = %v", key)
 
-	nodes, err := s.rtr.NodesFind(key)
+	leader, err := s.leader()
+	var (
+		nodes []storage.ServiceAddr
+		epoch uint64
+	)
+	if err == nil {
+		nodes, epoch, err = s.rtr.NodesFindEpoch(key)
+	}
 	status := storage.ErrToStatus(err)
 
 	reply := pb.NFReply{
 		Status: int32(status),
+		Leader: leader,
+		Epoch:  epoch,
 	}
 	if status == storage.StatusUnknown {
 		reply.Error = err.Error()
@@ -83,13 +145,358 @@ func (s *Server) NodesFind(ctx context.Context, req *pb.NFRequest) (*pb.NFReply,
 func (s *Server) List(ctx context.Context, req *pb.Empty) (*pb.ListReply, error) {
 	log.Printf("List request")
 
-	nodes := s.rtr.List()
+	leader, err := s.leader()
+	if err != nil {
+		return &pb.ListReply{
+			Status: int32(storage.ErrToStatus(err)),
+			Leader: leader,
+		}, nil
+	}
+
+	topology := s.rtr.Topology()
 	reply := pb.ListReply{
+		Status:  int32(storage.StatusOk),
+		Leader:  leader,
+		Epoch:   topology.Epoch,
+		Weights: weightsToPB(topology.Weights),
+		Domains: domainsToPB(topology.Domains),
+		States:  statesToPB(topology.States),
+		Ids:     idsToPB(topology.IDs),
+	}
+	reply.Nodes = make([]string, 0, len(topology.Nodes))
+	for _, node := range topology.Nodes {
+		reply.Nodes = append(reply.Nodes, string(node))
+	}
+	return &reply, nil
+}
+
+// ListAlive returns nodes with the NodeAlive status. Only the leader
+// receives heartbeats, so followers redirect to it.
+func (s *Server) ListAlive(ctx context.Context, req *pb.Empty) (*pb.AliveReply, error) {
+	log.Printf("ListAlive request")
+
+	leader, err := s.leader()
+	if err != nil {
+		return &pb.AliveReply{
+			Status: int32(storage.ErrToStatus(err)),
+			Leader: leader,
+		}, nil
+	}
+
+	nodes, epoch := s.rtr.Alive()
+	reply := pb.AliveReply{
 		Status: int32(storage.StatusOk),
+		Leader: leader,
+		Epoch:  epoch,
+		Nodes:  make([]string, 0, len(nodes)),
 	}
-	reply.Nodes = make([]string, 0, len(nodes))
 	for _, node := range nodes {
 		reply.Nodes = append(reply.Nodes, string(node))
 	}
 	return &reply, nil
 }
+
+// ClusterStatus returns states of all nodes. Only the leader receives
+// heartbeats, so followers redirect to it.
+func (s *Server) ClusterStatus(ctx context.Context, req *pb.Empty) (*pb.ClusterStatusReply, error) {
+	log.Printf("ClusterStatus request")
+
+	leader, err := s.leader()
+	if err != nil {
+		return &pb.ClusterStatusReply{
+			Status: int32(storage.ErrToStatus(err)),
+			Leader: leader,
+		}, nil
+	}
+
+	infos := s.rtr.ClusterStatus()
+	reply := pb.ClusterStatusReply{
+		Status: int32(storage.StatusOk),
+		Leader: leader,
+		Epoch:  s.rtr.Topology().Epoch,
+		Nodes:  make([]*pb.NodeInfo, 0, len(infos)),
+	}
+	for _, info := range infos {
+		reply.Nodes = append(reply.Nodes, &pb.NodeInfo{
+			Node:         string(info.Node),
+			HeartbeatAge: int64(info.HeartbeatAge),
+			NodeStatus:   int32(info.Status),
+			Phi:          info.Phi,
+			Stats:        statsToPB(info.Stats),
+			State:        int32(info.State),
+		})
+	}
+	return &reply, nil
+}
+
+// SetNodeState sets the administrative state of a node. Only the leader
+// changes the topology, so followers redirect to it.
+func (s *Server) SetNodeState(ctx context.Context, req *pb.NodeStateRequest) (*pb.NodeStateReply, error) {
+	node := storage.ServiceAddr(req.Node)
+	state := router.NodeState(req.State)
+	log.Printf("SetNodeState request: node = %q, state = %v", node, state)
+
+	leader, err := s.leader()
+	if err == nil {
+		err = s.rtr.SetNodeState(node, state)
+	}
+	status := storage.ErrToStatus(err)
+
+	reply := pb.NodeStateReply{
+		Status: int32(status),
+		Leader: leader,
+	}
+	if status == storage.StatusUnknown {
+		reply.Error = err.Error()
+	}
+	return &reply, nil
+}
+
+// SetNodeAddr changes the address of a node keeping its ID. Only the leader
+// changes the topology, so followers redirect to it.
+func (s *Server) SetNodeAddr(ctx context.Context, req *pb.NodeAddrRequest) (*pb.NodeAddrReply, error) {
+	node := storage.ServiceAddr(req.Node)
+	addr := storage.ServiceAddr(req.Addr)
+	log.Printf("SetNodeAddr request: node = %q, addr = %q", node, addr)
+
+	leader, err := s.leader()
+	if err == nil {
+		err = s.rtr.SetNodeAddr(node, addr)
+	}
+	status := storage.ErrToStatus(err)
+
+	reply := pb.NodeAddrReply{
+		Status: int32(status),
+		Leader: leader,
+	}
+	if status == storage.StatusUnknown {
+		reply.Error = err.Error()
+	}
+	return &reply, nil
+}
+
+// Config returns the effective configuration of the router in YAML.
+// Tokens of node identities are not reported. It is served by followers
+// too, as each router has its own configuration.
+func (s *Server) Config(ctx context.Context, req *pb.Empty) (*pb.ConfigReply, error) {
+	log.Printf("Config request")
+
+	cfg := s.rtr.Config()
+	for node, id := range cfg.Identities {
+		id.Token = "<redacted>"
+		cfg.Identities[node] = id
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return &pb.ConfigReply{Status: int32(storage.StatusUnknown), Error: err.Error()}, nil
+	}
+	return &pb.ConfigReply{Status: int32(storage.StatusOk), Config: data}, nil
+}
+
+// ListPending returns nodes waiting for approval. Only the leader
+// receives heartbeats, so followers redirect to it.
+func (s *Server) ListPending(ctx context.Context, req *pb.Empty) (*pb.PendingReply, error) {
+	log.Printf("ListPending request")
+
+	leader, err := s.leader()
+	if err != nil {
+		return &pb.PendingReply{
+			Status: int32(storage.ErrToStatus(err)),
+			Leader: leader,
+		}, nil
+	}
+
+	pending := s.rtr.Pending()
+	reply := pb.PendingReply{
+		Status: int32(storage.StatusOk),
+		Leader: leader,
+		Nodes:  make([]*pb.PendingNode, 0, len(pending)),
+	}
+	for _, p := range pending {
+		reply.Nodes = append(reply.Nodes, &pb.PendingNode{
+			Node:         string(p.Node),
+			Age:          int64(p.Age),
+			HeartbeatAge: int64(p.HeartbeatAge),
+			NodeId:       p.ID,
+		})
+	}
+	return &reply, nil
+}
+
+func (s *Server) ApproveNode(ctx context.Context, req *pb.ApproveRequest) (*pb.ApproveReply, error) {
+	node := storage.ServiceAddr(req.Node)
+	log.Printf("ApproveNode request: node = %q, rebalance = %v", node, req.Rebalance)
+
+	leader, err := s.leader()
+	if err == nil {
+		err = s.rtr.ApproveNode(node, req.Rebalance)
+	}
+	status := storage.ErrToStatus(err)
+
+	reply := pb.ApproveReply{
+		Status: int32(status),
+		Leader: leader,
+	}
+	if status == storage.StatusUnknown {
+		reply.Error = err.Error()
+	}
+	return &reply, nil
+}
+
+func (s *Server) RejectNode(ctx context.Context, req *pb.RejectRequest) (*pb.RejectReply, error) {
+	node := storage.ServiceAddr(req.Node)
+	log.Printf("RejectNode request: node = %q", node)
+
+	leader, err := s.leader()
+	if err == nil {
+		err = s.rtr.RejectNode(node)
+	}
+	status := storage.ErrToStatus(err)
+
+	reply := pb.RejectReply{
+		Status: int32(status),
+		Leader: leader,
+	}
+	if status == storage.StatusUnknown {
+		reply.Error = err.Error()
+	}
+	return &reply, nil
+}
+
+func weightsToPB(weights map[storage.ServiceAddr]float64) map[string]float64 {
+	if len(weights) == 0 {
+		return nil
+	}
+	ret := make(map[string]float64, len(weights))
+	for node, w := range weights {
+		ret[string(node)] = w
+	}
+	return ret
+}
+
+func domainsToPB(domains map[storage.ServiceAddr]router.Domain) map[string]*pb.Domain {
+	if len(domains) == 0 {
+		return nil
+	}
+	ret := make(map[string]*pb.Domain, len(domains))
+	for node, d := range domains {
+		ret[string(node)] = &pb.Domain{Zone: d.Zone, Rack: d.Rack}
+	}
+	return ret
+}
+
+func statesToPB(states map[storage.ServiceAddr]router.NodeState) map[string]int32 {
+	if len(states) == 0 {
+		return nil
+	}
+	ret := make(map[string]int32, len(states))
+	for node, st := range states {
+		ret[string(node)] = int32(st)
+	}
+	return ret
+}
+
+func idsToPB(ids map[storage.ServiceAddr]string) map[string]string {
+	if len(ids) == 0 {
+		return nil
+	}
+	ret := make(map[string]string, len(ids))
+	for node, id := range ids {
+		ret[string(node)] = id
+	}
+	return ret
+}
+
+func statsFromPB(st *pb.NodeStats) storage.Stats {
+	return storage.Stats{
+		Records:    st.Records,
+		Bytes:      st.Bytes,
+		GetRate:    st.GetRate,
+		PutRate:    st.PutRate,
+		DelRate:    st.DelRate,
+		LatencyP50: time.Duration(st.LatencyP50),
+		LatencyP99: time.Duration(st.LatencyP99),
+		Uptime:     time.Duration(st.Uptime),
+		Version:    st.Version,
+	}
+}
+
+func statsToPB(st storage.Stats) *pb.NodeStats {
+	return &pb.NodeStats{
+		Records:    st.Records,
+		Bytes:      st.Bytes,
+		GetRate:    st.GetRate,
+		PutRate:    st.PutRate,
+		DelRate:    st.DelRate,
+		LatencyP50: int64(st.LatencyP50),
+		LatencyP99: int64(st.LatencyP99),
+		Uptime:     int64(st.Uptime),
+		Version:    st.Version,
+	}
+}
+
+// WatchTopology streams the current topology and then each its change.
+// It is served by followers too, as the topology is replicated.
+func (s *Server) WatchTopology(req *pb.Empty, stream pb.Router_WatchTopologyServer) error {
+	log.Printf("WatchTopology request")
+
+	ch, cancel := s.rtr.Watch()
+	defer cancel()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case topology := <-ch:
+			reply := pb.TopologyReply{
+				Status:  int32(storage.StatusOk),
+				Epoch:   topology.Epoch,
+				Weights: weightsToPB(topology.Weights),
+				Domains: domainsToPB(topology.Domains),
+				States:  statesToPB(topology.States),
+				Ids:     idsToPB(topology.IDs),
+			}
+			reply.Nodes = make([]string, 0, len(topology.Nodes))
+			for _, node := range topology.Nodes {
+				reply.Nodes = append(reply.Nodes, string(node))
+			}
+			if err := stream.Send(&reply); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) RequestVote(ctx context.Context, req *pb.VoteRequest) (*pb.VoteReply, error) {
+	candidate := storage.ServiceAddr(req.Candidate)
+	log.Printf("RequestVote request: candidate = %q, term = %v", candidate, req.Term)
+
+	if s.cl == nil {
+		return &pb.VoteReply{Status: int32(storage.StatusUnknown), Error: "Router is not replicated"}, nil
+	}
+	term, granted := s.cl.RequestVote(req.Term, candidate, req.Epoch)
+	return &pb.VoteReply{
+		Status:  int32(storage.StatusOk),
+		Term:    term,
+		Granted: granted,
+	}, nil
+}
+
+func (s *Server) AppendState(ctx context.Context, req *pb.AppendRequest) (*pb.AppendReply, error) {
+	leader := storage.ServiceAddr(req.Leader)
+	log.Printf("AppendState request: leader = %q, term = %v", leader, req.Term)
+
+	if s.cl == nil {
+		return &pb.AppendReply{Status: int32(storage.StatusUnknown), Error: "Router is not replicated"}, nil
+	}
+	term, ok, err := s.cl.AppendState(req.Term, leader, req.State)
+	status := storage.ErrToStatus(err)
+	reply := pb.AppendReply{
+		Status: int32(status),
+		Term:   term,
+		Ok:     ok,
+	}
+	if status == storage.StatusUnknown {
+		reply.Error = err.Error()
+	}
+	return &reply, nil
+}
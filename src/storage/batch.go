@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storage/pb"
+)
+
+const (
+	// MaxBatch is a maximum number of keys sent in one batched request.
+	// Larger batches are split.
+	MaxBatch = 512
+	// MaxBatchBytes is a maximum size of data sent in one batched request.
+	// Larger batches are split.
+	MaxBatchBytes = 1 << 20
+)
+
+// Record is a key with its data.
+type Record struct {
+	Key  RecordID
+	Data []byte
+}
+
+// Result is an outcome of a batched request for one key.
+// Data is set only by gets.
+type Result struct {
+	Key  RecordID
+	Data []byte
+	Err  error
+}
+
+// MultiStorage is implemented by a Storage which can serve batches
+// of requests at once. Server calls Storage methods for each key
+// of a batch otherwise. Results are in the order of keys.
+type MultiStorage interface {
+	MultiGet(ctx context.Context, keys []RecordID) []Result
+	MultiPut(ctx context.Context, records []Record) []Result
+	MultiDel(ctx context.Context, keys []RecordID) []Result
+}
+
+// MultiClient is implemented by clients which can send batches
+// of requests to a node. Results are in the order of keys. If a batch
+// fails as a whole, all its keys get the error.
+type MultiClient interface {
+	MultiGet(ctx context.Context, node ServiceAddr, keys []RecordID) []Result
+	MultiPut(ctx context.Context, node ServiceAddr, records []Record) []Result
+	MultiDel(ctx context.Context, node ServiceAddr, keys []RecordID) []Result
+}
+
+func resultsToPB(results []Result) *pb.MultiReply {
+	reply := pb.MultiReply{
+		Status:  int32(StatusOk),
+		Results: make([]*pb.KeyResult, 0, len(results)),
+	}
+	for _, res := range results {
+		status := ErrToStatus(res.Err)
+		r := pb.KeyResult{
+			Key:    uint32(res.Key),
+			Status: int32(status),
+			Data:   res.Data,
+		}
+		if status == StatusUnknown {
+			r.Error = res.Err.Error()
+		}
+		reply.Results = append(reply.Results, &r)
+	}
+	return &reply
+}
+
+func resultsFromPB(reply *pb.MultiReply) ([]Result, error) {
+	if status := StatusCode(reply.Status); status != StatusOk {
+		if err := status.ToError(); err != ErrUnknownStatus {
+			return nil, err
+		}
+		return nil, errors.New(reply.Error)
+	}
+	results := make([]Result, 0, len(reply.Results))
+	for _, r := range reply.Results {
+		res := Result{Key: RecordID(r.Key), Data: r.Data}
+		status := StatusCode(r.Status)
+		if status != StatusOk {
+			res.Err = status.ToError()
+			if res.Err == ErrUnknownStatus {
+				res.Err = errors.New(r.Error)
+			}
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func (s *Server) MultiGet(ctx context.Context, req *pb.MultiGetRequest) (*pb.MultiReply, error) {
+	log.Printf("MULTIGET request: %v keys", len(req.Keys))
+
+	keys := make([]RecordID, 0, len(req.Keys))
+	for _, k := range req.Keys {
+		keys = append(keys, RecordID(k))
+	}
+	if ms, ok := s.st.(MultiStorage); ok {
+		return resultsToPB(ms.MultiGet(ctx, keys)), nil
+	}
+	results := make([]Result, 0, len(keys))
+	for _, k := range keys {
+		data, err := s.st.Get(k)
+		results = append(results, Result{Key: k, Data: data, Err: err})
+	}
+	return resultsToPB(results), nil
+}
+
+func (s *Server) MultiPut(ctx context.Context, req *pb.MultiPutRequest) (*pb.MultiReply, error) {
+	log.Printf("MULTIPUT request: %v keys", len(req.Records))
+
+	records := make([]Record, 0, len(req.Records))
+	for _, r := range req.Records {
+		records = append(records, Record{Key: RecordID(r.Key), Data: r.Data})
+	}
+	if ms, ok := s.st.(MultiStorage); ok {
+		return resultsToPB(ms.MultiPut(ctx, records)), nil
+	}
+	results := make([]Result, 0, len(records))
+	for _, r := range records {
+		results = append(results, Result{Key: r.Key, Err: s.st.Put(r.Key, r.Data)})
+	}
+	return resultsToPB(results), nil
+}
+
+func (s *Server) MultiDel(ctx context.Context, req *pb.MultiDelRequest) (*pb.MultiReply, error) {
+	log.Printf("MULTIDEL request: %v keys", len(req.Keys))
+
+	keys := make([]RecordID, 0, len(req.Keys))
+	for _, k := range req.Keys {
+		keys = append(keys, RecordID(k))
+	}
+	if ms, ok := s.st.(MultiStorage); ok {
+		return resultsToPB(ms.MultiDel(ctx, keys)), nil
+	}
+	results := make([]Result, 0, len(keys))
+	for _, k := range keys {
+		results = append(results, Result{Key: k, Err: s.st.Del(k)})
+	}
+	return resultsToPB(results), nil
+}
+
+// multi sends a batched request made by cb for each chunk of keys.
+// chunk returns the end of a chunk starting at i.
+func (c StorageClient) multi(ctx context.Context, node ServiceAddr, keys []RecordID, chunk func(i int) int, cb func(ctx context.Context, client pb.StorageClient, i, j int) (*pb.MultiReply, error)) []Result {
+	results := make([]Result, 0, len(keys))
+	for i := 0; i < len(keys); {
+		j := chunk(i)
+		results = c.multiChunk(ctx, node, keys, i, j, cb, results)
+		i = j
+	}
+	return results
+}
+
+// multiChunk sends a batched request for keys[i:j] and appends its
+// results to results. A chunk whose request or reply exceeds the gRPC
+// message size is split in halves, so that large values of a few keys
+// do not fail the others. Keys of a failed chunk get its error.
+func (c StorageClient) multiChunk(ctx context.Context, node ServiceAddr, keys []RecordID, i, j int, cb func(ctx context.Context, client pb.StorageClient, i, j int) (*pb.MultiReply, error), results []Result) []Result {
+	var reply *pb.MultiReply
+	_, err := c.do(ctx, node, func(ctx context.Context, client pb.StorageClient) ([]byte, error) {
+		var err error
+		reply, err = cb(ctx, client, i, j)
+		return nil, err
+	})
+	if status.Code(err) == codes.ResourceExhausted && j-i > 1 {
+		m := i + (j-i)/2
+		results = c.multiChunk(ctx, node, keys, i, m, cb, results)
+		return c.multiChunk(ctx, node, keys, m, j, cb, results)
+	}
+	var chunkResults []Result
+	if err == nil {
+		chunkResults, err = resultsFromPB(reply)
+	}
+	if err == nil && len(chunkResults) != j-i {
+		err = fmt.Errorf("Got %v results for %v keys", len(chunkResults), j-i)
+	}
+	if err != nil {
+		for _, k := range keys[i:j] {
+			results = append(results, Result{Key: k, Err: err})
+		}
+		return results
+	}
+	return append(results, chunkResults...)
+}
+
+// chunkKeys returns a chunk function splitting n keys by MaxBatch.
+func chunkKeys(n int) func(i int) int {
+	return func(i int) int {
+		if i+MaxBatch < n {
+			return i + MaxBatch
+		}
+		return n
+	}
+}
+
+// MultiGet gets records for keys from node in batches of MaxBatch keys.
+// Batches whose replies do not fit a gRPC message are split.
+func (c StorageClient) MultiGet(ctx context.Context, node ServiceAddr, keys []RecordID) []Result {
+	log.Printf("Getting %v records from %q", len(keys), node)
+	return c.multi(ctx, node, keys, chunkKeys(len(keys)), func(ctx context.Context, client pb.StorageClient, i, j int) (*pb.MultiReply, error) {
+		req := pb.MultiGetRequest{Keys: make([]uint32, 0, j-i)}
+		for _, k := range keys[i:j] {
+			req.Keys = append(req.Keys, uint32(k))
+		}
+		return client.MultiGet(ctx, &req)
+	})
+}
+
+// MultiPut puts records to node in batches of MaxBatch records
+// and MaxBatchBytes of data.
+func (c StorageClient) MultiPut(ctx context.Context, node ServiceAddr, records []Record) []Result {
+	log.Printf("Putting %v records to %q", len(records), node)
+	keys := make([]RecordID, 0, len(records))
+	for _, r := range records {
+		keys = append(keys, r.Key)
+	}
+	chunk := func(i int) int {
+		j, size := i, 0
+		for j < len(records) && j-i < MaxBatch && (j == i || size+len(records[j].Data) <= MaxBatchBytes) {
+			size += len(records[j].Data)
+			j++
+		}
+		return j
+	}
+	return c.multi(ctx, node, keys, chunk, func(ctx context.Context, client pb.StorageClient, i, j int) (*pb.MultiReply, error) {
+		req := pb.MultiPutRequest{Records: make([]*pb.Record, 0, j-i)}
+		for _, r := range records[i:j] {
+			req.Records = append(req.Records, &pb.Record{Key: uint32(r.Key), Data: r.Data})
+		}
+		return client.MultiPut(ctx, &req)
+	})
+}
+
+// MultiDel deletes records for keys from node in batches of MaxBatch keys.
+func (c StorageClient) MultiDel(ctx context.Context, node ServiceAddr, keys []RecordID) []Result {
+	log.Printf("Deleting %v records from %q", len(keys), node)
+	return c.multi(ctx, node, keys, chunkKeys(len(keys)), func(ctx context.Context, client pb.StorageClient, i, j int) (*pb.MultiReply, error) {
+		req := pb.MultiDelRequest{Keys: make([]uint32, 0, j-i)}
+		for _, k := range keys[i:j] {
+			req.Keys = append(req.Keys, uint32(k))
+		}
+		return client.MultiDel(ctx, &req)
+	})
+}
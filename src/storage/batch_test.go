@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+// FakeBigStorage returns values of size bytes and counts gets.
+type FakeBigStorage struct {
+	FakeStorage
+	size int
+	n    *int32
+}
+
+func (s FakeBigStorage) Get(k RecordID) ([]byte, error) {
+	atomic.AddInt32(s.n, 1)
+	return make([]byte, s.size), nil
+}
+
+func (s FakeBigStorage) gets() int {
+	return int(atomic.LoadInt32(s.n))
+}
+
+func TestStorageClient_Multi(t *testing.T) {
+	addr, srv := startServer(t)
+	defer srv.Stop()
+	c := StorageClient{}
+
+	// more keys than fit one batch
+	keys := make([]RecordID, MaxBatch+10)
+	for i := range keys {
+		keys[i] = RecordID(i)
+	}
+	check := func(name string, results []Result, n int, data []byte) {
+		if len(results) != n {
+			t.Fatalf("%v() got %v results, want %v", name, len(results), n)
+		}
+		for i, res := range results {
+			if res.Key != RecordID(i) || res.Err != nil || !bytes.Equal(res.Data, data) {
+				t.Fatalf("%v() got %v, %q, %v, want %v, %q, <nil>", name, res.Key, res.Data, res.Err, i, data)
+			}
+		}
+	}
+	check("MultiGet", c.MultiGet(context.Background(), addr, keys), len(keys), []byte("value"))
+	check("MultiDel", c.MultiDel(context.Background(), addr, keys), len(keys), nil)
+
+	// records which do not fit one batch by size
+	records := make([]Record, 3)
+	for i := range records {
+		records[i] = Record{Key: RecordID(i), Data: make([]byte, MaxBatchBytes/2+1)}
+	}
+	check("MultiPut", c.MultiPut(context.Background(), addr, records), len(records), nil)
+
+	srv.Stop()
+	st := FakeBigStorage{size: MaxBatchBytes, n: new(int32)}
+	srv = NewServer(st, string(addr))
+	go srv.ListenAndServe()
+	defer srv.Stop()
+	waitServer(t, string(addr))
+	// replies of whole batches exceed the gRPC message size
+	check("MultiGet", c.MultiGet(context.Background(), addr, keys[:6]), 6, make([]byte, MaxBatchBytes))
+	if n := st.gets(); n <= 6 {
+		t.Errorf("MultiGet() of large values read %v records, want them read again after a split", n)
+	}
+
+	srv.Stop()
+	results := c.MultiGet(context.Background(), addr, keys[:2])
+	if len(results) != 2 || results[0].Err == nil || results[1].Err == nil {
+		t.Errorf("MultiGet() from a stopped server got %+v, want errors for all keys", results)
+	}
+}
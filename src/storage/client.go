@@ -6,8 +6,6 @@ import (
 	"fmt"
 	"log"
 
-	"google.golang.org/grpc"
-
 	"storage/pb"
 )
 
@@ -17,7 +15,35 @@ type Client interface {
 	Del(node ServiceAddr, k RecordID) error
 }
 
-type StorageClient struct{}
+// ContextClient is implemented by clients whose requests can be cancelled
+// and given deadlines with a context. Requests made with a context without
+// a deadline time out after Timeout, as do requests made by Client methods.
+type ContextClient interface {
+	PutContext(ctx context.Context, node ServiceAddr, k RecordID, d []byte) error
+	GetContext(ctx context.Context, node ServiceAddr, k RecordID) ([]byte, error)
+	DelContext(ctx context.Context, node ServiceAddr, k RecordID) error
+}
+
+// InvalidatorClient is implemented by clients which can tell services
+// that a record was changed, see Invalidator.
+type InvalidatorClient interface {
+	Invalidate(node ServiceAddr, k RecordID) error
+}
+
+// WithTimeout returns a copy of ctx which times out after Timeout
+// unless ctx already has a deadline.
+func WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, Timeout)
+}
+
+// StorageClient sends requests to services over connections of Pool,
+// DefaultPool is used if Pool is nil.
+type StorageClient struct {
+	Pool *Pool
+}
 
 var defaultClient Client = StorageClient{}
 
@@ -25,23 +51,42 @@ func NewClient() Client {
 	return defaultClient
 }
 
-func (c StorageClient) do(addr ServiceAddr, cb func(client pb.StorageClient) ([]byte, error)) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
-	defer cancel()
-	conn, err := grpc.DialContext(ctx, string(addr), grpc.WithInsecure())
+// pool returns the pool of c.
+func (c StorageClient) pool() *Pool {
+	if c.Pool == nil {
+		return DefaultPool
+	}
+	return c.Pool
+}
+
+// stream returns a client for addr over a connection of the pool of c
+// which is held until release is called.
+func (c StorageClient) stream(addr ServiceAddr) (client pb.StorageClient, release func(), err error) {
+	conn, release, err := c.pool().Acquire(addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error dialing %q: %v", addr, err)
+	}
+	return pb.NewStorageClient(conn), release, nil
+}
+
+func (c StorageClient) do(ctx context.Context, addr ServiceAddr, cb func(ctx context.Context, client pb.StorageClient) ([]byte, error)) ([]byte, error) {
+	conn, err := c.pool().Get(addr)
 	if err != nil {
 		return nil, fmt.Errorf("Error dialing %q: %v", addr, err)
 	}
-	defer conn.Close()
-	client := pb.NewStorageClient(conn)
-	return cb(client)
+	ctx, cancel := WithTimeout(ctx)
+	defer cancel()
+	return cb(ctx, pb.NewStorageClient(conn))
 }
 
 func (c StorageClient) Put(node ServiceAddr, k RecordID, d []byte) error {
+	return c.PutContext(context.Background(), node, k, d)
+}
+
+// PutContext is like Put but the request is bound to ctx.
+func (c StorageClient) PutContext(ctx context.Context, node ServiceAddr, k RecordID, d []byte) error {
 	log.Printf("Putting record to %q, key = %v", node, k)
-	_, err := c.do(node, func(client pb.StorageClient) ([]byte, error) {
-		ctx, cancel := context.WithTimeout(context.Background(), Timeout)
-		defer cancel()
+	_, err := c.do(ctx, node, func(ctx context.Context, client pb.StorageClient) ([]byte, error) {
 		req := pb.PutRequest{
 			Key:  uint32(k),
 			Data: d,
@@ -63,10 +108,13 @@ func (c StorageClient) Put(node ServiceAddr, k RecordID, d []byte) error {
 }
 
 func (c StorageClient) Get(node ServiceAddr, k RecordID) ([]byte, error) {
+	return c.GetContext(context.Background(), node, k)
+}
+
+// GetContext is like Get but the request is bound to ctx.
+func (c StorageClient) GetContext(ctx context.Context, node ServiceAddr, k RecordID) ([]byte, error) {
 	log.Printf("Getting record from %q, key = %v", node, k)
-	return c.do(node, func(client pb.StorageClient) ([]byte, error) {
-		ctx, cancel := context.WithTimeout(context.Background(), Timeout)
-		defer cancel()
+	return c.do(ctx, node, func(ctx context.Context, client pb.StorageClient) ([]byte, error) {
 		req := pb.GetRequest{
 			Key: uint32(k),
 		}
@@ -86,10 +134,13 @@ func (c StorageClient) Get(node ServiceAddr, k RecordID) ([]byte, error) {
 }
 
 func (c StorageClient) Del(node ServiceAddr, k RecordID) error {
+	return c.DelContext(context.Background(), node, k)
+}
+
+// DelContext is like Del but the request is bound to ctx.
+func (c StorageClient) DelContext(ctx context.Context, node ServiceAddr, k RecordID) error {
 	log.Printf("Deleting record from %q, key = %v", node, k)
-	_, err := c.do(node, func(client pb.StorageClient) ([]byte, error) {
-		ctx, cancel := context.WithTimeout(context.Background(), Timeout)
-		defer cancel()
+	_, err := c.do(ctx, node, func(ctx context.Context, client pb.StorageClient) ([]byte, error) {
 		req := pb.DelRequest{
 			Key: uint32(k),
 		}
@@ -108,3 +159,59 @@ func (c StorageClient) Del(node ServiceAddr, k RecordID) error {
 	})
 	return err
 }
+
+// Ping checks whether node is able to serve requests.
+func (c StorageClient) Ping(node ServiceAddr) error {
+	_, err := c.do(context.Background(), node, func(ctx context.Context, client pb.StorageClient) ([]byte, error) {
+		reply, err := client.Ping(ctx, &pb.PingRequest{})
+		if err != nil {
+			return nil, err
+		}
+		status := StatusCode(reply.Status)
+		if status == StatusOk {
+			return nil, nil
+		}
+		if err := status.ToError(); err != ErrUnknownStatus {
+			return nil, err
+		}
+		return nil, errors.New(reply.Error)
+	})
+	return err
+}
+
+// Config returns the effective configuration of node in YAML.
+func (c StorageClient) Config(node ServiceAddr) ([]byte, error) {
+	return c.do(context.Background(), node, func(ctx context.Context, client pb.StorageClient) ([]byte, error) {
+		reply, err := client.Config(ctx, &pb.ConfigRequest{})
+		if err != nil {
+			return nil, err
+		}
+		status := StatusCode(reply.Status)
+		if status == StatusOk {
+			return reply.Config, nil
+		}
+		if err := status.ToError(); err != ErrUnknownStatus {
+			return nil, err
+		}
+		return nil, errors.New(reply.Error)
+	})
+}
+
+// Invalidate tells node that the record k was changed, see Invalidator.
+func (c StorageClient) Invalidate(node ServiceAddr, k RecordID) error {
+	_, err := c.do(context.Background(), node, func(ctx context.Context, client pb.StorageClient) ([]byte, error) {
+		reply, err := client.Invalidate(ctx, &pb.InvalidateRequest{Key: uint32(k)})
+		if err != nil {
+			return nil, err
+		}
+		status := StatusCode(reply.Status)
+		if status == StatusOk {
+			return nil, nil
+		}
+		if err := status.ToError(); err != ErrUnknownStatus {
+			return nil, err
+		}
+		return nil, errors.New(reply.Error)
+	})
+	return err
+}
@@ -10,6 +10,8 @@ var (
 	ErrUnknownDaemon    = errors.New("Unknown Daemon")
 	ErrRecordNotFound   = errors.New("Record Not Found")
 	ErrRecordExists     = errors.New("Already have record")
+	ErrNotLeader        = errors.New("Router is not a leader")
+	ErrUnauthenticated  = errors.New("Node identity not verified")
 
 	ErrUnknownStatus = errors.New("Error Unknown")
 )
@@ -25,6 +27,11 @@ const (
 	StatusRecordExists
 
 	StatusUnknown
+
+	// Codes added later go after StatusUnknown, so that values of
+	// existing codes never change on the wire.
+	StatusNotLeader
+	StatusUnauthenticated
 )
 
 func (s StatusCode) ToError() error {
@@ -41,6 +48,10 @@ func (s StatusCode) ToError() error {
 		return ErrRecordNotFound
 	case StatusRecordExists:
 		return ErrRecordExists
+	case StatusNotLeader:
+		return ErrNotLeader
+	case StatusUnauthenticated:
+		return ErrUnauthenticated
 	default:
 		return ErrUnknownStatus
 	}
@@ -62,6 +73,10 @@ func ErrToStatus(err error) StatusCode {
 		return StatusRecordNotFound
 	case ErrRecordExists:
 		return StatusRecordExists
+	case ErrNotLeader:
+		return StatusNotLeader
+	case ErrUnauthenticated:
+		return StatusUnauthenticated
 	default:
 		return StatusUnknown
 	}
@@ -0,0 +1,26 @@
+package storage
+
+import "testing"
+
+// TestStatusCode_Wire checks that status codes keep their values on the
+// wire, so that services of different versions understand each other.
+func TestStatusCode_Wire(t *testing.T) {
+	for status, want := range map[StatusCode]int32{
+		StatusOk:               0,
+		StatusQuorumNotReached: 1,
+		StatusNotEnoughDaemons: 2,
+		StatusUnknownDaemon:    3,
+		StatusRecordNotFound:   4,
+		StatusRecordExists:     5,
+		StatusUnknown:          6,
+		StatusNotLeader:        7,
+		StatusUnauthenticated:  8,
+	} {
+		if int32(status) != want {
+			t.Errorf("Status %v has value %v, want %v", status.ToError(), int32(status), want)
+		}
+		if status != StatusOk && status != StatusUnknown && ErrToStatus(status.ToError()) != status {
+			t.Errorf("ErrToStatus(%v) got %v, want %v", status.ToError(), ErrToStatus(status.ToError()), status)
+		}
+	}
+}
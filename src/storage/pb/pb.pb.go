@@ -34,7 +34,7 @@ func (m *GetRequest) Reset()         { *m = GetRequest{} }
 func (m *GetRequest) String() string { return proto.CompactTextString(m) }
 func (*GetRequest) ProtoMessage()    {}
 func (*GetRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pb_fd9dd68e056fbc00, []int{0}
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{0}
 }
 func (m *GetRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetRequest.Unmarshal(m, b)
@@ -74,7 +74,7 @@ func (m *GetReply) Reset()         { *m = GetReply{} }
 func (m *GetReply) String() string { return proto.CompactTextString(m) }
 func (*GetReply) ProtoMessage()    {}
 func (*GetReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pb_fd9dd68e056fbc00, []int{1}
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{1}
 }
 func (m *GetReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetReply.Unmarshal(m, b)
@@ -127,7 +127,7 @@ func (m *PutRequest) Reset()         { *m = PutRequest{} }
 func (m *PutRequest) String() string { return proto.CompactTextString(m) }
 func (*PutRequest) ProtoMessage()    {}
 func (*PutRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pb_fd9dd68e056fbc00, []int{2}
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{2}
 }
 func (m *PutRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutRequest.Unmarshal(m, b)
@@ -173,7 +173,7 @@ func (m *PutReply) Reset()         { *m = PutReply{} }
 func (m *PutReply) String() string { return proto.CompactTextString(m) }
 func (*PutReply) ProtoMessage()    {}
 func (*PutReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pb_fd9dd68e056fbc00, []int{3}
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{3}
 }
 func (m *PutReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutReply.Unmarshal(m, b)
@@ -218,7 +218,7 @@ func (m *DelRequest) Reset()         { *m = DelRequest{} }
 func (m *DelRequest) String() string { return proto.CompactTextString(m) }
 func (*DelRequest) ProtoMessage()    {}
 func (*DelRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pb_fd9dd68e056fbc00, []int{4}
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{4}
 }
 func (m *DelRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DelRequest.Unmarshal(m, b)
@@ -257,7 +257,7 @@ func (m *DelReply) Reset()         { *m = DelReply{} }
 func (m *DelReply) String() string { return proto.CompactTextString(m) }
 func (*DelReply) ProtoMessage()    {}
 func (*DelReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pb_fd9dd68e056fbc00, []int{5}
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{5}
 }
 func (m *DelReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DelReply.Unmarshal(m, b)
@@ -291,6 +291,680 @@ func (m *DelReply) GetError() string {
 	return ""
 }
 
+type PingRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PingRequest) Reset()         { *m = PingRequest{} }
+func (m *PingRequest) String() string { return proto.CompactTextString(m) }
+func (*PingRequest) ProtoMessage()    {}
+func (*PingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{6}
+}
+func (m *PingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PingRequest.Unmarshal(m, b)
+}
+func (m *PingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PingRequest.Marshal(b, m, deterministic)
+}
+func (dst *PingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PingRequest.Merge(dst, src)
+}
+func (m *PingRequest) XXX_Size() int {
+	return xxx_messageInfo_PingRequest.Size(m)
+}
+func (m *PingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PingRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PingRequest proto.InternalMessageInfo
+
+type PingReply struct {
+	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PingReply) Reset()         { *m = PingReply{} }
+func (m *PingReply) String() string { return proto.CompactTextString(m) }
+func (*PingReply) ProtoMessage()    {}
+func (*PingReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{7}
+}
+func (m *PingReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PingReply.Unmarshal(m, b)
+}
+func (m *PingReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PingReply.Marshal(b, m, deterministic)
+}
+func (dst *PingReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PingReply.Merge(dst, src)
+}
+func (m *PingReply) XXX_Size() int {
+	return xxx_messageInfo_PingReply.Size(m)
+}
+func (m *PingReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_PingReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PingReply proto.InternalMessageInfo
+
+func (m *PingReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *PingReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type ConfigRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConfigRequest) Reset()         { *m = ConfigRequest{} }
+func (m *ConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*ConfigRequest) ProtoMessage()    {}
+func (*ConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{8}
+}
+func (m *ConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConfigRequest.Unmarshal(m, b)
+}
+func (m *ConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConfigRequest.Marshal(b, m, deterministic)
+}
+func (dst *ConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConfigRequest.Merge(dst, src)
+}
+func (m *ConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_ConfigRequest.Size(m)
+}
+func (m *ConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConfigRequest proto.InternalMessageInfo
+
+type ConfigReply struct {
+	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Config               []byte   `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConfigReply) Reset()         { *m = ConfigReply{} }
+func (m *ConfigReply) String() string { return proto.CompactTextString(m) }
+func (*ConfigReply) ProtoMessage()    {}
+func (*ConfigReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{9}
+}
+func (m *ConfigReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConfigReply.Unmarshal(m, b)
+}
+func (m *ConfigReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConfigReply.Marshal(b, m, deterministic)
+}
+func (dst *ConfigReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConfigReply.Merge(dst, src)
+}
+func (m *ConfigReply) XXX_Size() int {
+	return xxx_messageInfo_ConfigReply.Size(m)
+}
+func (m *ConfigReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConfigReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConfigReply proto.InternalMessageInfo
+
+func (m *ConfigReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *ConfigReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ConfigReply) GetConfig() []byte {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+type InvalidateRequest struct {
+	Key                  uint32   `protobuf:"varint,1,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InvalidateRequest) Reset()         { *m = InvalidateRequest{} }
+func (m *InvalidateRequest) String() string { return proto.CompactTextString(m) }
+func (*InvalidateRequest) ProtoMessage()    {}
+func (*InvalidateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{10}
+}
+func (m *InvalidateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InvalidateRequest.Unmarshal(m, b)
+}
+func (m *InvalidateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InvalidateRequest.Marshal(b, m, deterministic)
+}
+func (dst *InvalidateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InvalidateRequest.Merge(dst, src)
+}
+func (m *InvalidateRequest) XXX_Size() int {
+	return xxx_messageInfo_InvalidateRequest.Size(m)
+}
+func (m *InvalidateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InvalidateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InvalidateRequest proto.InternalMessageInfo
+
+func (m *InvalidateRequest) GetKey() uint32 {
+	if m != nil {
+		return m.Key
+	}
+	return 0
+}
+
+type InvalidateReply struct {
+	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InvalidateReply) Reset()         { *m = InvalidateReply{} }
+func (m *InvalidateReply) String() string { return proto.CompactTextString(m) }
+func (*InvalidateReply) ProtoMessage()    {}
+func (*InvalidateReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{11}
+}
+func (m *InvalidateReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InvalidateReply.Unmarshal(m, b)
+}
+func (m *InvalidateReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InvalidateReply.Marshal(b, m, deterministic)
+}
+func (dst *InvalidateReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InvalidateReply.Merge(dst, src)
+}
+func (m *InvalidateReply) XXX_Size() int {
+	return xxx_messageInfo_InvalidateReply.Size(m)
+}
+func (m *InvalidateReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_InvalidateReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InvalidateReply proto.InternalMessageInfo
+
+func (m *InvalidateReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *InvalidateReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type MultiGetRequest struct {
+	Keys                 []uint32 `protobuf:"varint,1,rep,packed,name=keys,proto3" json:"keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MultiGetRequest) Reset()         { *m = MultiGetRequest{} }
+func (m *MultiGetRequest) String() string { return proto.CompactTextString(m) }
+func (*MultiGetRequest) ProtoMessage()    {}
+func (*MultiGetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{12}
+}
+func (m *MultiGetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MultiGetRequest.Unmarshal(m, b)
+}
+func (m *MultiGetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MultiGetRequest.Marshal(b, m, deterministic)
+}
+func (dst *MultiGetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MultiGetRequest.Merge(dst, src)
+}
+func (m *MultiGetRequest) XXX_Size() int {
+	return xxx_messageInfo_MultiGetRequest.Size(m)
+}
+func (m *MultiGetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MultiGetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MultiGetRequest proto.InternalMessageInfo
+
+func (m *MultiGetRequest) GetKeys() []uint32 {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+type Record struct {
+	Key                  uint32   `protobuf:"varint,1,opt,name=key,proto3" json:"key,omitempty"`
+	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Record) Reset()         { *m = Record{} }
+func (m *Record) String() string { return proto.CompactTextString(m) }
+func (*Record) ProtoMessage()    {}
+func (*Record) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{13}
+}
+func (m *Record) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Record.Unmarshal(m, b)
+}
+func (m *Record) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Record.Marshal(b, m, deterministic)
+}
+func (dst *Record) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Record.Merge(dst, src)
+}
+func (m *Record) XXX_Size() int {
+	return xxx_messageInfo_Record.Size(m)
+}
+func (m *Record) XXX_DiscardUnknown() {
+	xxx_messageInfo_Record.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Record proto.InternalMessageInfo
+
+func (m *Record) GetKey() uint32 {
+	if m != nil {
+		return m.Key
+	}
+	return 0
+}
+
+func (m *Record) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type MultiPutRequest struct {
+	Records              []*Record `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *MultiPutRequest) Reset()         { *m = MultiPutRequest{} }
+func (m *MultiPutRequest) String() string { return proto.CompactTextString(m) }
+func (*MultiPutRequest) ProtoMessage()    {}
+func (*MultiPutRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{14}
+}
+func (m *MultiPutRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MultiPutRequest.Unmarshal(m, b)
+}
+func (m *MultiPutRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MultiPutRequest.Marshal(b, m, deterministic)
+}
+func (dst *MultiPutRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MultiPutRequest.Merge(dst, src)
+}
+func (m *MultiPutRequest) XXX_Size() int {
+	return xxx_messageInfo_MultiPutRequest.Size(m)
+}
+func (m *MultiPutRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MultiPutRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MultiPutRequest proto.InternalMessageInfo
+
+func (m *MultiPutRequest) GetRecords() []*Record {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+type MultiDelRequest struct {
+	Keys                 []uint32 `protobuf:"varint,1,rep,packed,name=keys,proto3" json:"keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MultiDelRequest) Reset()         { *m = MultiDelRequest{} }
+func (m *MultiDelRequest) String() string { return proto.CompactTextString(m) }
+func (*MultiDelRequest) ProtoMessage()    {}
+func (*MultiDelRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{15}
+}
+func (m *MultiDelRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MultiDelRequest.Unmarshal(m, b)
+}
+func (m *MultiDelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MultiDelRequest.Marshal(b, m, deterministic)
+}
+func (dst *MultiDelRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MultiDelRequest.Merge(dst, src)
+}
+func (m *MultiDelRequest) XXX_Size() int {
+	return xxx_messageInfo_MultiDelRequest.Size(m)
+}
+func (m *MultiDelRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MultiDelRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MultiDelRequest proto.InternalMessageInfo
+
+func (m *MultiDelRequest) GetKeys() []uint32 {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+type KeyResult struct {
+	Key                  uint32   `protobuf:"varint,1,opt,name=key,proto3" json:"key,omitempty"`
+	Status               int32    `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Data                 []byte   `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KeyResult) Reset()         { *m = KeyResult{} }
+func (m *KeyResult) String() string { return proto.CompactTextString(m) }
+func (*KeyResult) ProtoMessage()    {}
+func (*KeyResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{16}
+}
+func (m *KeyResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeyResult.Unmarshal(m, b)
+}
+func (m *KeyResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeyResult.Marshal(b, m, deterministic)
+}
+func (dst *KeyResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyResult.Merge(dst, src)
+}
+func (m *KeyResult) XXX_Size() int {
+	return xxx_messageInfo_KeyResult.Size(m)
+}
+func (m *KeyResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeyResult proto.InternalMessageInfo
+
+func (m *KeyResult) GetKey() uint32 {
+	if m != nil {
+		return m.Key
+	}
+	return 0
+}
+
+func (m *KeyResult) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *KeyResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *KeyResult) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type MultiReply struct {
+	Status               int32        `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string       `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Results              []*KeyResult `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *MultiReply) Reset()         { *m = MultiReply{} }
+func (m *MultiReply) String() string { return proto.CompactTextString(m) }
+func (*MultiReply) ProtoMessage()    {}
+func (*MultiReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{17}
+}
+func (m *MultiReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MultiReply.Unmarshal(m, b)
+}
+func (m *MultiReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MultiReply.Marshal(b, m, deterministic)
+}
+func (dst *MultiReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MultiReply.Merge(dst, src)
+}
+func (m *MultiReply) XXX_Size() int {
+	return xxx_messageInfo_MultiReply.Size(m)
+}
+func (m *MultiReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_MultiReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MultiReply proto.InternalMessageInfo
+
+func (m *MultiReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *MultiReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *MultiReply) GetResults() []*KeyResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type PutChunk struct {
+	Key                  uint32   `protobuf:"varint,1,opt,name=key,proto3" json:"key,omitempty"`
+	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PutChunk) Reset()         { *m = PutChunk{} }
+func (m *PutChunk) String() string { return proto.CompactTextString(m) }
+func (*PutChunk) ProtoMessage()    {}
+func (*PutChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{18}
+}
+func (m *PutChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PutChunk.Unmarshal(m, b)
+}
+func (m *PutChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PutChunk.Marshal(b, m, deterministic)
+}
+func (dst *PutChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PutChunk.Merge(dst, src)
+}
+func (m *PutChunk) XXX_Size() int {
+	return xxx_messageInfo_PutChunk.Size(m)
+}
+func (m *PutChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_PutChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PutChunk proto.InternalMessageInfo
+
+func (m *PutChunk) GetKey() uint32 {
+	if m != nil {
+		return m.Key
+	}
+	return 0
+}
+
+func (m *PutChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type GetChunk struct {
+	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetChunk) Reset()         { *m = GetChunk{} }
+func (m *GetChunk) String() string { return proto.CompactTextString(m) }
+func (*GetChunk) ProtoMessage()    {}
+func (*GetChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{19}
+}
+func (m *GetChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetChunk.Unmarshal(m, b)
+}
+func (m *GetChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetChunk.Marshal(b, m, deterministic)
+}
+func (dst *GetChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetChunk.Merge(dst, src)
+}
+func (m *GetChunk) XXX_Size() int {
+	return xxx_messageInfo_GetChunk.Size(m)
+}
+func (m *GetChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetChunk proto.InternalMessageInfo
+
+func (m *GetChunk) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *GetChunk) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *GetChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type DigestReply struct {
+	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Digest               []byte   `protobuf:"bytes,3,opt,name=digest,proto3" json:"digest,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DigestReply) Reset()         { *m = DigestReply{} }
+func (m *DigestReply) String() string { return proto.CompactTextString(m) }
+func (*DigestReply) ProtoMessage()    {}
+func (*DigestReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pb_64a714b47f61cdf5, []int{20}
+}
+func (m *DigestReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DigestReply.Unmarshal(m, b)
+}
+func (m *DigestReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DigestReply.Marshal(b, m, deterministic)
+}
+func (dst *DigestReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DigestReply.Merge(dst, src)
+}
+func (m *DigestReply) XXX_Size() int {
+	return xxx_messageInfo_DigestReply.Size(m)
+}
+func (m *DigestReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_DigestReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DigestReply proto.InternalMessageInfo
+
+func (m *DigestReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *DigestReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *DigestReply) GetDigest() []byte {
+	if m != nil {
+		return m.Digest
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*GetRequest)(nil), "GetRequest")
 	proto.RegisterType((*GetReply)(nil), "GetReply")
@@ -298,6 +972,21 @@ func init() {
 	proto.RegisterType((*PutReply)(nil), "PutReply")
 	proto.RegisterType((*DelRequest)(nil), "DelRequest")
 	proto.RegisterType((*DelReply)(nil), "DelReply")
+	proto.RegisterType((*PingRequest)(nil), "PingRequest")
+	proto.RegisterType((*PingReply)(nil), "PingReply")
+	proto.RegisterType((*ConfigRequest)(nil), "ConfigRequest")
+	proto.RegisterType((*ConfigReply)(nil), "ConfigReply")
+	proto.RegisterType((*InvalidateRequest)(nil), "InvalidateRequest")
+	proto.RegisterType((*InvalidateReply)(nil), "InvalidateReply")
+	proto.RegisterType((*MultiGetRequest)(nil), "MultiGetRequest")
+	proto.RegisterType((*Record)(nil), "Record")
+	proto.RegisterType((*MultiPutRequest)(nil), "MultiPutRequest")
+	proto.RegisterType((*MultiDelRequest)(nil), "MultiDelRequest")
+	proto.RegisterType((*KeyResult)(nil), "KeyResult")
+	proto.RegisterType((*MultiReply)(nil), "MultiReply")
+	proto.RegisterType((*PutChunk)(nil), "PutChunk")
+	proto.RegisterType((*GetChunk)(nil), "GetChunk")
+	proto.RegisterType((*DigestReply)(nil), "DigestReply")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -315,6 +1004,15 @@ type StorageClient interface {
 	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetReply, error)
 	Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutReply, error)
 	Del(ctx context.Context, in *DelRequest, opts ...grpc.CallOption) (*DelReply, error)
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingReply, error)
+	Config(ctx context.Context, in *ConfigRequest, opts ...grpc.CallOption) (*ConfigReply, error)
+	Invalidate(ctx context.Context, in *InvalidateRequest, opts ...grpc.CallOption) (*InvalidateReply, error)
+	MultiGet(ctx context.Context, in *MultiGetRequest, opts ...grpc.CallOption) (*MultiReply, error)
+	MultiPut(ctx context.Context, in *MultiPutRequest, opts ...grpc.CallOption) (*MultiReply, error)
+	MultiDel(ctx context.Context, in *MultiDelRequest, opts ...grpc.CallOption) (*MultiReply, error)
+	PutStream(ctx context.Context, opts ...grpc.CallOption) (Storage_PutStreamClient, error)
+	GetStream(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (Storage_GetStreamClient, error)
+	Digest(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*DigestReply, error)
 }
 
 type storageClient struct {
@@ -352,11 +1050,149 @@ func (c *storageClient) Del(ctx context.Context, in *DelRequest, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *storageClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingReply, error) {
+	out := new(PingReply)
+	err := c.cc.Invoke(ctx, "/Storage/Ping", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Config(ctx context.Context, in *ConfigRequest, opts ...grpc.CallOption) (*ConfigReply, error) {
+	out := new(ConfigReply)
+	err := c.cc.Invoke(ctx, "/Storage/Config", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Invalidate(ctx context.Context, in *InvalidateRequest, opts ...grpc.CallOption) (*InvalidateReply, error) {
+	out := new(InvalidateReply)
+	err := c.cc.Invoke(ctx, "/Storage/Invalidate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) MultiGet(ctx context.Context, in *MultiGetRequest, opts ...grpc.CallOption) (*MultiReply, error) {
+	out := new(MultiReply)
+	err := c.cc.Invoke(ctx, "/Storage/MultiGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) MultiPut(ctx context.Context, in *MultiPutRequest, opts ...grpc.CallOption) (*MultiReply, error) {
+	out := new(MultiReply)
+	err := c.cc.Invoke(ctx, "/Storage/MultiPut", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) MultiDel(ctx context.Context, in *MultiDelRequest, opts ...grpc.CallOption) (*MultiReply, error) {
+	out := new(MultiReply)
+	err := c.cc.Invoke(ctx, "/Storage/MultiDel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) PutStream(ctx context.Context, opts ...grpc.CallOption) (Storage_PutStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[0], "/Storage/PutStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storagePutStreamClient{stream}
+	return x, nil
+}
+
+type Storage_PutStreamClient interface {
+	Send(*PutChunk) error
+	CloseAndRecv() (*PutReply, error)
+	grpc.ClientStream
+}
+
+type storagePutStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *storagePutStreamClient) Send(m *PutChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *storagePutStreamClient) CloseAndRecv() (*PutReply, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(PutReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *storageClient) GetStream(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (Storage_GetStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[1], "/Storage/GetStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageGetStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Storage_GetStreamClient interface {
+	Recv() (*GetChunk, error)
+	grpc.ClientStream
+}
+
+type storageGetStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageGetStreamClient) Recv() (*GetChunk, error) {
+	m := new(GetChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *storageClient) Digest(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*DigestReply, error) {
+	out := new(DigestReply)
+	err := c.cc.Invoke(ctx, "/Storage/Digest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StorageServer is the server API for Storage service.
 type StorageServer interface {
 	Get(context.Context, *GetRequest) (*GetReply, error)
 	Put(context.Context, *PutRequest) (*PutReply, error)
 	Del(context.Context, *DelRequest) (*DelReply, error)
+	Ping(context.Context, *PingRequest) (*PingReply, error)
+	Config(context.Context, *ConfigRequest) (*ConfigReply, error)
+	Invalidate(context.Context, *InvalidateRequest) (*InvalidateReply, error)
+	MultiGet(context.Context, *MultiGetRequest) (*MultiReply, error)
+	MultiPut(context.Context, *MultiPutRequest) (*MultiReply, error)
+	MultiDel(context.Context, *MultiDelRequest) (*MultiReply, error)
+	PutStream(Storage_PutStreamServer) error
+	GetStream(*GetRequest, Storage_GetStreamServer) error
+	Digest(context.Context, *GetRequest) (*DigestReply, error)
 }
 
 func RegisterStorageServer(s *grpc.Server, srv StorageServer) {
@@ -417,6 +1253,179 @@ func _Storage_Del_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Storage_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Storage/Ping",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Ping(ctx, req.(*PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Config_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Config(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Storage/Config",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Config(ctx, req.(*ConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Invalidate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvalidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Invalidate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Storage/Invalidate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Invalidate(ctx, req.(*InvalidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_MultiGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).MultiGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Storage/MultiGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).MultiGet(ctx, req.(*MultiGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_MultiPut_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiPutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).MultiPut(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Storage/MultiPut",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).MultiPut(ctx, req.(*MultiPutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_MultiDel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiDelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).MultiDel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Storage/MultiDel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).MultiDel(ctx, req.(*MultiDelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_PutStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StorageServer).PutStream(&storagePutStreamServer{stream})
+}
+
+type Storage_PutStreamServer interface {
+	SendAndClose(*PutReply) error
+	Recv() (*PutChunk, error)
+	grpc.ServerStream
+}
+
+type storagePutStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *storagePutStreamServer) SendAndClose(m *PutReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *storagePutStreamServer) Recv() (*PutChunk, error) {
+	m := new(PutChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Storage_GetStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).GetStream(m, &storageGetStreamServer{stream})
+}
+
+type Storage_GetStreamServer interface {
+	Send(*GetChunk) error
+	grpc.ServerStream
+}
+
+type storageGetStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageGetStreamServer) Send(m *GetChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Storage_Digest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Digest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Storage/Digest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Digest(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Storage_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "Storage",
 	HandlerType: (*StorageServer)(nil),
@@ -433,28 +1442,87 @@ var _Storage_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Del",
 			Handler:    _Storage_Del_Handler,
 		},
+		{
+			MethodName: "Ping",
+			Handler:    _Storage_Ping_Handler,
+		},
+		{
+			MethodName: "Config",
+			Handler:    _Storage_Config_Handler,
+		},
+		{
+			MethodName: "Invalidate",
+			Handler:    _Storage_Invalidate_Handler,
+		},
+		{
+			MethodName: "MultiGet",
+			Handler:    _Storage_MultiGet_Handler,
+		},
+		{
+			MethodName: "MultiPut",
+			Handler:    _Storage_MultiPut_Handler,
+		},
+		{
+			MethodName: "MultiDel",
+			Handler:    _Storage_MultiDel_Handler,
+		},
+		{
+			MethodName: "Digest",
+			Handler:    _Storage_Digest_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PutStream",
+			Handler:       _Storage_PutStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetStream",
+			Handler:       _Storage_GetStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pb.proto",
 }
 
-func init() { proto.RegisterFile("pb.proto", fileDescriptor_pb_fd9dd68e056fbc00) }
-
-var fileDescriptor_pb_fd9dd68e056fbc00 = []byte{
-	// 226 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x28, 0x48, 0xd2, 0x2b,
-	0x28, 0xca, 0x2f, 0xc9, 0x57, 0x92, 0xe3, 0xe2, 0x72, 0x4f, 0x2d, 0x09, 0x4a, 0x2d, 0x2c, 0x4d,
-	0x2d, 0x2e, 0x11, 0x12, 0xe0, 0x62, 0xce, 0x4e, 0xad, 0x94, 0x60, 0x54, 0x60, 0xd4, 0xe0, 0x0d,
-	0x02, 0x31, 0x95, 0x7c, 0xb8, 0x38, 0xc0, 0xf2, 0x05, 0x39, 0x95, 0x42, 0x62, 0x5c, 0x6c, 0xc5,
-	0x25, 0x89, 0x25, 0xa5, 0xc5, 0x60, 0x05, 0xac, 0x41, 0x50, 0x9e, 0x90, 0x08, 0x17, 0x6b, 0x6a,
-	0x51, 0x51, 0x7e, 0x91, 0x04, 0x93, 0x02, 0xa3, 0x06, 0x67, 0x10, 0x84, 0x23, 0x24, 0xc4, 0xc5,
-	0x92, 0x92, 0x58, 0x92, 0x28, 0xc1, 0xac, 0xc0, 0xa8, 0xc1, 0x13, 0x04, 0x66, 0x2b, 0x19, 0x71,
-	0x71, 0x05, 0x94, 0xe2, 0xb6, 0x0d, 0xae, 0x87, 0x09, 0x49, 0x8f, 0x05, 0x17, 0x07, 0x58, 0x0f,
-	0xc9, 0x2e, 0x00, 0xf9, 0xcd, 0x25, 0x35, 0x07, 0xb7, 0xdf, 0x2c, 0xb8, 0x38, 0xc0, 0xf2, 0x24,
-	0x9b, 0x6c, 0x94, 0xc3, 0xc5, 0x1e, 0x5c, 0x92, 0x5f, 0x94, 0x98, 0x9e, 0x2a, 0x24, 0xcf, 0xc5,
-	0xec, 0x9e, 0x5a, 0x22, 0xc4, 0xad, 0x87, 0x08, 0x46, 0x29, 0x4e, 0x3d, 0x58, 0x98, 0x29, 0x31,
-	0x80, 0x14, 0x04, 0x94, 0x82, 0x14, 0x20, 0x7c, 0x2e, 0xc5, 0xa9, 0x07, 0xf3, 0x12, 0x44, 0x81,
-	0x4b, 0x6a, 0x8e, 0x10, 0xb7, 0x1e, 0xc2, 0xb1, 0x52, 0x9c, 0x7a, 0x30, 0x97, 0x29, 0x31, 0x24,
-	0xb1, 0x81, 0xa3, 0xca, 0x18, 0x10, 0x00, 0x00, 0xff, 0xff, 0x38, 0xe5, 0xcc, 0x66, 0xb6, 0x01,
-	0x00, 0x00,
+func init() { proto.RegisterFile("pb.proto", fileDescriptor_pb_64a714b47f61cdf5) }
+
+var fileDescriptor_pb_64a714b47f61cdf5 = []byte{
+	// 549 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0x5d, 0x8f, 0xd2, 0x40,
+	0x14, 0x6d, 0x29, 0x5b, 0xe8, 0x2d, 0x08, 0xde, 0x98, 0x0d, 0xe9, 0x83, 0xe2, 0x44, 0x62, 0x13,
+	0x93, 0xc9, 0x06, 0xf7, 0x61, 0x7d, 0xf2, 0x61, 0x49, 0x88, 0x51, 0x93, 0x66, 0xf8, 0x01, 0xda,
+	0x5d, 0x46, 0x24, 0x54, 0x8a, 0xed, 0x8c, 0x09, 0x7f, 0xda, 0xdf, 0x60, 0x3a, 0xfd, 0x1a, 0x58,
+	0x88, 0x0b, 0xfb, 0x36, 0xb7, 0x73, 0xee, 0xe1, 0xde, 0xd3, 0x73, 0x28, 0xb4, 0x37, 0x77, 0x74,
+	0x93, 0xc4, 0x22, 0x26, 0x2f, 0x01, 0xa6, 0x5c, 0x30, 0xfe, 0x5b, 0xf2, 0x54, 0x60, 0x1f, 0xac,
+	0x15, 0xdf, 0x0e, 0xcc, 0xa1, 0xe9, 0x77, 0x59, 0x76, 0x24, 0x5f, 0xa0, 0xad, 0xee, 0x37, 0xd1,
+	0x16, 0x2f, 0xc1, 0x4e, 0x45, 0x28, 0x64, 0xaa, 0x00, 0x17, 0xac, 0xa8, 0xf0, 0x05, 0x5c, 0xf0,
+	0x24, 0x89, 0x93, 0x41, 0x63, 0x68, 0xfa, 0x0e, 0xcb, 0x0b, 0x44, 0x68, 0xce, 0x43, 0x11, 0x0e,
+	0xac, 0xa1, 0xe9, 0x77, 0x98, 0x3a, 0x93, 0x31, 0x40, 0x20, 0x8f, 0xff, 0x5a, 0xd5, 0xd3, 0xd0,
+	0x7a, 0x6e, 0xa0, 0x1d, 0xc8, 0x73, 0x26, 0xc8, 0x76, 0x9b, 0xf0, 0xe8, 0xf8, 0x6e, 0x37, 0xd0,
+	0x56, 0xf7, 0xa7, 0x33, 0x77, 0xc1, 0x0d, 0x96, 0xeb, 0x45, 0x41, 0x4d, 0x3e, 0x80, 0x93, 0x97,
+	0xa7, 0x33, 0xf5, 0xa0, 0x7b, 0x1b, 0xaf, 0x7f, 0x2c, 0x2b, 0xae, 0x19, 0xb8, 0xe5, 0x83, 0xd3,
+	0x35, 0xbf, 0x04, 0xfb, 0x5e, 0x35, 0x17, 0xaa, 0x17, 0x15, 0x19, 0xc1, 0xf3, 0x4f, 0xeb, 0x3f,
+	0x61, 0xb4, 0x9c, 0x87, 0x82, 0x1f, 0x17, 0xe4, 0x23, 0xf4, 0x74, 0xd8, 0xe9, 0xdb, 0x8c, 0xa0,
+	0xf7, 0x55, 0x46, 0x62, 0xa9, 0x59, 0x0a, 0xa1, 0xb9, 0xe2, 0xdb, 0xac, 0xdd, 0xf2, 0xbb, 0x4c,
+	0x9d, 0x09, 0x05, 0x9b, 0xf1, 0xfb, 0x38, 0x99, 0x3f, 0xd2, 0x02, 0xd7, 0x05, 0xad, 0xe6, 0x9d,
+	0xd7, 0xd0, 0x4a, 0x14, 0x45, 0xce, 0xec, 0x8e, 0x5b, 0x34, 0xa7, 0x64, 0xe5, 0xf3, 0x6a, 0x18,
+	0xcd, 0x03, 0x87, 0x86, 0xf9, 0x06, 0xce, 0x67, 0xbe, 0x65, 0x3c, 0x95, 0xd1, 0x21, 0x4b, 0xd6,
+	0x02, 0x34, 0x0e, 0x0b, 0x60, 0x1d, 0x32, 0x7d, 0x53, 0x9b, 0xfe, 0x3b, 0x80, 0x9a, 0xe3, 0x9c,
+	0x17, 0xfa, 0x26, 0x5b, 0x33, 0x9b, 0x2c, 0x1d, 0x58, 0x6a, 0x4d, 0xa0, 0xd5, 0xb0, 0xac, 0xbc,
+	0x22, 0x57, 0x2a, 0x22, 0xb7, 0x3f, 0xe5, 0x7a, 0xf5, 0x48, 0x45, 0xf3, 0x58, 0xe7, 0x1d, 0x4f,
+	0x8f, 0xf5, 0x0c, 0xdc, 0xc9, 0x72, 0xc1, 0x53, 0x71, 0xa6, 0x67, 0xe7, 0xaa, 0xb9, 0xf4, 0x6c,
+	0x5e, 0x8d, 0xff, 0x5a, 0xd0, 0x9a, 0x89, 0x38, 0x09, 0x17, 0x1c, 0x5f, 0x81, 0x35, 0xe5, 0x02,
+	0x5d, 0x5a, 0x1b, 0xcb, 0x73, 0x68, 0xf9, 0xc7, 0x44, 0x8c, 0x0c, 0x10, 0xc8, 0x0c, 0x50, 0x5b,
+	0xc4, 0x73, 0x68, 0x20, 0x75, 0xc0, 0x84, 0x47, 0xe8, 0xd2, 0xda, 0x0d, 0x9e, 0x43, 0xcb, 0xf8,
+	0x13, 0x03, 0x09, 0x34, 0xb3, 0x0c, 0x63, 0x87, 0x6a, 0xc9, 0xf6, 0x80, 0x56, 0xc1, 0x26, 0x06,
+	0xfa, 0x60, 0xe7, 0xd9, 0xc4, 0x67, 0x74, 0x27, 0xb5, 0x5e, 0x87, 0x6a, 0xa1, 0x25, 0x06, 0x5e,
+	0x03, 0xd4, 0x49, 0x42, 0xa4, 0x0f, 0xd2, 0xe7, 0xf5, 0xe9, 0x5e, 0xd4, 0x88, 0x81, 0xef, 0xa0,
+	0x5d, 0xc6, 0x07, 0xfb, 0x74, 0x2f, 0x49, 0x9e, 0x4b, 0x6b, 0x1b, 0x69, 0xe0, 0x40, 0x56, 0xe0,
+	0x40, 0xfe, 0x0f, 0x9c, 0x69, 0x50, 0x80, 0x35, 0x21, 0xf6, 0xc0, 0x23, 0x70, 0x02, 0x29, 0x66,
+	0x22, 0xe1, 0xe1, 0x2f, 0x54, 0x2a, 0x2a, 0xa3, 0xec, 0x08, 0xea, 0x9b, 0xf8, 0x16, 0x9c, 0x29,
+	0x2f, 0x61, 0x0f, 0x5f, 0x8d, 0xea, 0x21, 0xc6, 0x95, 0x89, 0x23, 0xb0, 0x73, 0x7b, 0xec, 0xa2,
+	0x3a, 0x54, 0x33, 0x0d, 0x31, 0xee, 0x6c, 0xf5, 0x45, 0x7a, 0xff, 0x6f, 0x00, 0x50, 0xec, 0xaa,
+	0x43, 0x9d, 0x06, 0x00, 0x00,
 }
@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+const (
+	// DefaultIdleTimeout is a time after which an unused connection
+	// is closed. It must be longer than Timeout.
+	DefaultIdleTimeout = time.Minute
+	// DefaultMinBackoff is a delay before the first redial of a failed
+	// connection.
+	DefaultMinBackoff = 20 * time.Millisecond
+	// DefaultMaxBackoff is an upper bound of delays between redials.
+	// Requests to a restarted service fail for up to this delay,
+	// so it is kept short.
+	DefaultMaxBackoff = 250 * time.Millisecond
+)
+
+// DefaultPool is a Pool shared by clients of services.
+var DefaultPool = NewPool(DefaultIdleTimeout, DefaultMinBackoff, DefaultMaxBackoff)
+
+// Pool is a cache of gRPC connections keyed by service address.
+// A connection is dialed on the first request and reused by the following
+// ones. A failed connection is redialed with exponential backoff, requests
+// made meanwhile fail fast. Connections unused for the idle timeout
+// are closed unless they are acquired.
+type Pool struct {
+	idle       time.Duration
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mu      sync.Mutex
+	conns   map[ServiceAddr]*poolConn
+	janitor sync.Once
+	stop    chan struct{}
+}
+
+type poolConn struct {
+	conn    *grpc.ClientConn
+	dialed  time.Time
+	used    time.Time
+	backoff time.Duration
+	// users is a number of holders of the connection, see Acquire.
+	users int
+}
+
+// NewPool creates a Pool closing connections unused for idle and redialing
+// failed ones with delays growing from minBackoff to maxBackoff.
+func NewPool(idle, minBackoff, maxBackoff time.Duration) *Pool {
+	return &Pool{
+		idle:       idle,
+		minBackoff: minBackoff,
+		maxBackoff: maxBackoff,
+		conns:      make(map[ServiceAddr]*poolConn),
+		stop:       make(chan struct{}),
+	}
+}
+
+// Get returns a connection to addr. The connection must not be closed
+// by the caller. It may be evicted once unused for the idle timeout,
+// so it should be held for a single request, see Acquire otherwise.
+func (p *Pool) Get(addr ServiceAddr) (*grpc.ClientConn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pc, err := p.get(addr)
+	if err != nil {
+		return nil, err
+	}
+	return pc.conn, nil
+}
+
+// Acquire is like Get but the connection is not evicted until release
+// is called, so it can be held longer than the idle timeout, e.g. by
+// streams.
+func (p *Pool) Acquire(addr ServiceAddr) (conn *grpc.ClientConn, release func(), err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pc, err := p.get(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	pc.users++
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			pc.users--
+			pc.used = time.Now()
+		})
+	}
+	return pc.conn, release, nil
+}
+
+// get returns the entry of a connection to addr, dialing it if needed.
+// p.mu must be held.
+func (p *Pool) get(addr ServiceAddr) (*poolConn, error) {
+	p.janitor.Do(func() {
+		go p.evictLoop()
+	})
+
+	now := time.Now()
+	pc, ok := p.conns[addr]
+	if !ok {
+		pc = &poolConn{backoff: p.minBackoff}
+	} else {
+		pc.used = now
+		switch pc.conn.GetState() {
+		case connectivity.Ready:
+			pc.backoff = p.minBackoff
+			return pc, nil
+		case connectivity.TransientFailure:
+			if now.Sub(pc.dialed) < pc.backoff {
+				return pc, nil
+			}
+			pc.backoff *= 2
+			if pc.backoff > p.maxBackoff {
+				pc.backoff = p.maxBackoff
+			}
+		case connectivity.Shutdown:
+		default:
+			return pc, nil
+		}
+		pc.conn.Close()
+	}
+
+	conn, err := grpc.Dial(string(addr), grpc.WithInsecure(), grpc.WithBackoffMaxDelay(p.maxBackoff))
+	if err != nil {
+		delete(p.conns, addr)
+		return nil, err
+	}
+	pc.conn, pc.dialed, pc.used = conn, now, now
+	p.conns[addr] = pc
+	return pc, nil
+}
+
+// State returns the state of the connection to addr, connectivity.Shutdown
+// if there is none.
+func (p *Pool) State(addr ServiceAddr) connectivity.State {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pc, ok := p.conns[addr]
+	if !ok {
+		return connectivity.Shutdown
+	}
+	return pc.conn.GetState()
+}
+
+func (p *Pool) evictLoop() {
+	ticker := time.NewTicker(p.idle / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.evict(time.Now())
+		}
+	}
+}
+
+// evict closes connections unused since tNow - idle timeout and not
+// acquired.
+func (p *Pool) evict(tNow time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for addr, pc := range p.conns {
+		if pc.users == 0 && tNow.Sub(pc.used) >= p.idle {
+			pc.conn.Close()
+			delete(p.conns, addr)
+		}
+	}
+}
+
+// Close closes all connections of the pool.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	select {
+	case <-p.stop:
+	default:
+		close(p.stop)
+	}
+	for addr, pc := range p.conns {
+		pc.conn.Close()
+		delete(p.conns, addr)
+	}
+}
@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"storage/pb"
+)
+
+type FakeStorage struct{}
+
+func (FakeStorage) Put(k RecordID, d []byte) error { return nil }
+func (FakeStorage) Get(k RecordID) ([]byte, error) { return []byte("value"), nil }
+func (FakeStorage) Del(k RecordID) error           { return nil }
+
+// startServer starts a Server with FakeStorage at a free local port.
+func startServer(t testing.TB) (ServiceAddr, *Server) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error: %v", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	log.SetOutput(ioutil.Discard)
+	srv := NewServer(FakeStorage{}, addr)
+	go srv.ListenAndServe()
+	waitServer(t, addr)
+	return ServiceAddr(addr), srv
+}
+
+// waitServer waits for a server to listen at addr.
+func waitServer(t testing.TB, addr string) {
+	for i := 0; ; i++ {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		if i == 100 {
+			t.Fatalf("Server at %v is not started: %v", addr, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPool_Reuse(t *testing.T) {
+	addr, srv := startServer(t)
+	defer srv.Stop()
+	p := NewPool(time.Minute, time.Millisecond, time.Second)
+	defer p.Close()
+
+	c := StorageClient{Pool: p}
+	for i := 0; i < 3; i++ {
+		if _, err := c.Get(addr, 1); err != nil {
+			t.Fatalf("Get() error: %v", err)
+		}
+	}
+	conn1, _ := p.Get(addr)
+	conn2, _ := p.Get(addr)
+	if conn1 != conn2 {
+		t.Errorf("Get() returned different connections to %v", addr)
+	}
+	if st := p.State(addr); st.String() != "READY" {
+		t.Errorf("State() got %v, want READY", st)
+	}
+
+	p.evict(time.Now().Add(time.Minute))
+	if st := p.State(addr); st.String() != "SHUTDOWN" {
+		t.Errorf("State() after eviction got %v, want SHUTDOWN", st)
+	}
+	if _, err := c.Get(addr, 1); err != nil {
+		t.Fatalf("Get() after eviction error: %v", err)
+	}
+}
+
+func TestPool_Reconnect(t *testing.T) {
+	addr, srv := startServer(t)
+	p := NewPool(time.Minute, time.Millisecond, time.Second)
+	defer p.Close()
+
+	c := StorageClient{Pool: p}
+	if _, err := c.Get(addr, 1); err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	srv.Stop()
+	srv = NewServer(FakeStorage{}, string(addr))
+	go srv.ListenAndServe()
+	defer srv.Stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		_, err := c.Get(addr, 1)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Get() after restart error: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// slowReader reads n bytes, one chunk of size per delay.
+type slowReader struct {
+	n, size int
+	delay   time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if r.n == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(r.delay)
+	n := r.size
+	if n > r.n {
+		n = r.n
+	}
+	if n > len(p) {
+		n = len(p)
+	}
+	r.n -= n
+	return n, nil
+}
+
+// TestPool_Stream checks that a stream outliving the idle timeout keeps
+// its connection.
+func TestPool_Stream(t *testing.T) {
+	addr, srv := startServer(t)
+	srv.Stop()
+	st := &FakeStreamStorage{values: make(map[RecordID][]byte)}
+	srv = NewServer(st, string(addr))
+	go srv.ListenAndServe()
+	defer srv.Stop()
+	waitServer(t, string(addr))
+
+	const idle = 50 * time.Millisecond
+	p := NewPool(idle, time.Millisecond, time.Second)
+	defer p.Close()
+	c := StorageClient{Pool: p}
+
+	r := &slowReader{n: 20 << 10, size: 1 << 10, delay: idle / 4}
+	if err := c.PutStream(context.Background(), addr, 1, r); err != nil {
+		t.Fatalf("PutStream() longer than the idle timeout error: %v", err)
+	}
+	if d, err := c.Digest(context.Background(), addr, 1); err != nil || !bytes.Equal(d, DigestOf(make([]byte, 20<<10))) {
+		t.Errorf("Digest() after a slow PutStream() got %x, %v", d, err)
+	}
+
+	// the released connection is evicted as usual
+	time.Sleep(3 * idle)
+	if st := p.State(addr); st.String() != "SHUTDOWN" {
+		t.Errorf("State() of a released idle connection got %v, want SHUTDOWN", st)
+	}
+
+	_, release, err := p.Acquire(addr)
+	if err != nil {
+		t.Fatalf("Acquire() error: %v", err)
+	}
+	p.evict(time.Now().Add(time.Minute))
+	if st := p.State(addr); st.String() == "SHUTDOWN" {
+		t.Errorf("evict() closed an acquired connection")
+	}
+	release()
+	release()
+	p.evict(time.Now().Add(time.Minute))
+	if st := p.State(addr); st.String() != "SHUTDOWN" {
+		t.Errorf("evict() after release got %v, want SHUTDOWN", st)
+	}
+}
+
+// BenchmarkGet_Dial measures requests dialing a connection each time.
+func BenchmarkGet_Dial(b *testing.B) {
+	addr, srv := startServer(b)
+	defer srv.Stop()
+	for i := 0; i < b.N; i++ {
+		conn, err := grpc.Dial(string(addr), grpc.WithInsecure())
+		if err != nil {
+			b.Fatalf("Dial() error: %v", err)
+		}
+		_, err = pb.NewStorageClient(conn).Get(context.Background(), &pb.GetRequest{Key: 1})
+		conn.Close()
+		if err != nil {
+			b.Fatalf("Get() error: %v", err)
+		}
+	}
+}
+
+// BenchmarkGet_Pool measures requests over pooled connections.
+func BenchmarkGet_Pool(b *testing.B) {
+	addr, srv := startServer(b)
+	defer srv.Stop()
+	p := NewPool(time.Minute, time.Millisecond, time.Second)
+	defer p.Close()
+	c := StorageClient{Pool: p}
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Get(addr, 1); err != nil {
+			b.Fatalf("Get() error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ConfigPollInterval is a time interval between checks of a config file
+// for changes.
+const ConfigPollInterval = time.Second
+
+// RestartError is returned by reloads of a config changing fields which
+// take effect only after a restart. Other fields are applied.
+type RestartError struct {
+	Fields []string
+}
+
+func (e RestartError) Error() string {
+	return fmt.Sprintf("Changes of %s require a restart", strings.Join(e.Fields, ", "))
+}
+
+// ConfigReporter is implemented by a Storage which can report
+// its effective configuration.
+type ConfigReporter interface {
+	// EffectiveConfig returns the running configuration in YAML.
+	EffectiveConfig() ([]byte, error)
+}
+
+// WatchConfig calls reload each time the process receives SIGHUP
+// and each time the file fname is modified.
+func WatchConfig(fname string, reload func()) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	last, _ := os.Stat(fname)
+	go func() {
+		ticker := time.NewTicker(ConfigPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-sig:
+				log.Printf("Reloading config %q on SIGHUP", fname)
+			case <-ticker.C:
+				fi, err := os.Stat(fname)
+				if err != nil || !modified(last, fi) {
+					continue
+				}
+				last = fi
+				log.Printf("Reloading modified config %q", fname)
+			}
+			reload()
+		}
+	}()
+}
+
+func modified(last, fi os.FileInfo) bool {
+	return last == nil || !fi.ModTime().Equal(last.ModTime()) || fi.Size() != last.Size()
+}
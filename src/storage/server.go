@@ -20,6 +20,26 @@ type Storage interface {
 	Del(k RecordID) error
 }
 
+// ContextStorage is implemented by a Storage whose requests can be cancelled
+// and given deadlines with a context. Server passes it contexts of incoming
+// requests carrying their gRPC deadlines.
+type ContextStorage interface {
+	PutContext(ctx context.Context, k RecordID, d []byte) error
+	GetContext(ctx context.Context, k RecordID) ([]byte, error)
+	DelContext(ctx context.Context, k RecordID) error
+}
+
+// Invalidator is implemented by a Storage which caches records
+// and can be told that a record was changed elsewhere.
+type Invalidator interface {
+	Invalidate(k RecordID)
+}
+
+// Pinger is implemented by a Storage which can check its own health.
+type Pinger interface {
+	Ping() error
+}
+
 type Server struct {
 	addr string
 	st   Storage
@@ -53,7 +73,15 @@ func (s *Server) Get(ctx context.Context, req *pb.GetRequest) (*pb.GetReply, err
 	key := RecordID(req.Key)
 	log.Printf("GET request: key = %v", key)
 
-	data, err := s.st.Get(key)
+	var (
+		data []byte
+		err  error
+	)
+	if cs, ok := s.st.(ContextStorage); ok {
+		data, err = cs.GetContext(ctx, key)
+	} else {
+		data, err = s.st.Get(key)
+	}
 	status := ErrToStatus(err)
 
 	reply := pb.GetReply{
@@ -72,7 +100,12 @@ func (s *Server) Put(ctx context.Context, req *pb.PutRequest) (*pb.PutReply, err
 	key := RecordID(req.Key)
 	log.Printf("PUT request: key = %v", key)
 
-	err := s.st.Put(key, req.Data)
+	var err error
+	if cs, ok := s.st.(ContextStorage); ok {
+		err = cs.PutContext(ctx, key, req.Data)
+	} else {
+		err = s.st.Put(key, req.Data)
+	}
 	status := ErrToStatus(err)
 	reply := pb.PutReply{
 		Status: int32(status),
@@ -87,7 +120,12 @@ func (s *Server) Del(ctx context.Context, req *pb.DelRequest) (*pb.DelReply, err
 	key := RecordID(req.Key)
 	log.Printf("DEL request: key = %v", key)
 
-	err := s.st.Del(key)
+	var err error
+	if cs, ok := s.st.(ContextStorage); ok {
+		err = cs.DelContext(ctx, key)
+	} else {
+		err = s.st.Del(key)
+	}
 	status := ErrToStatus(err)
 	reply := pb.DelReply{
 		Status: int32(status),
@@ -97,3 +135,49 @@ func (s *Server) Del(ctx context.Context, req *pb.DelRequest) (*pb.DelReply, err
 	}
 	return &reply, nil
 }
+
+func (s *Server) Ping(ctx context.Context, req *pb.PingRequest) (*pb.PingReply, error) {
+	log.Printf("PING request")
+
+	var err error
+	if p, ok := s.st.(Pinger); ok {
+		err = p.Ping()
+	}
+	status := ErrToStatus(err)
+	reply := pb.PingReply{
+		Status: int32(status),
+	}
+	if status == StatusUnknown {
+		reply.Error = err.Error()
+	}
+	return &reply, nil
+}
+
+func (s *Server) Config(ctx context.Context, req *pb.ConfigRequest) (*pb.ConfigReply, error) {
+	log.Printf("CONFIG request")
+
+	c, ok := s.st.(ConfigReporter)
+	if !ok {
+		return &pb.ConfigReply{Status: int32(StatusUnknown), Error: "Config is not reported"}, nil
+	}
+	data, err := c.EffectiveConfig()
+	status := ErrToStatus(err)
+	reply := pb.ConfigReply{
+		Status: int32(status),
+		Config: data,
+	}
+	if status == StatusUnknown {
+		reply.Error = err.Error()
+	}
+	return &reply, nil
+}
+
+func (s *Server) Invalidate(ctx context.Context, req *pb.InvalidateRequest) (*pb.InvalidateReply, error) {
+	key := RecordID(req.Key)
+	log.Printf("INVALIDATE request: key = %v", key)
+
+	if i, ok := s.st.(Invalidator); ok {
+		i.Invalidate(key)
+	}
+	return &pb.InvalidateReply{Status: int32(StatusOk)}, nil
+}
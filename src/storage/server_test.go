@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// FakeContextStorage reports deadlines of contexts of its requests.
+type FakeContextStorage struct {
+	FakeStorage
+	deadlines chan time.Time
+}
+
+func (s FakeContextStorage) GetContext(ctx context.Context, k RecordID) ([]byte, error) {
+	d, _ := ctx.Deadline()
+	s.deadlines <- d
+	return []byte("value"), nil
+}
+
+func (s FakeContextStorage) PutContext(ctx context.Context, k RecordID, d []byte) error {
+	return s.Put(k, d)
+}
+
+func (s FakeContextStorage) DelContext(ctx context.Context, k RecordID) error {
+	return s.Del(k)
+}
+
+func TestServer_Deadline(t *testing.T) {
+	addr, srv := startServer(t)
+	srv.Stop()
+	st := FakeContextStorage{deadlines: make(chan time.Time, 2)}
+	srv = NewServer(st, string(addr))
+	go srv.ListenAndServe()
+	defer srv.Stop()
+	waitServer(t, string(addr))
+
+	p := NewPool(time.Minute, time.Millisecond, time.Second)
+	defer p.Close()
+	c := StorageClient{Pool: p}
+
+	start := time.Now()
+	if _, err := c.Get(addr, 1); err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if d := <-st.deadlines; d.Sub(start) > Timeout+time.Second || d.Sub(start) < Timeout-time.Second {
+		t.Errorf("Get() deadline in %v, want %v", d.Sub(start), Timeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	want, _ := ctx.Deadline()
+	if _, err := c.GetContext(ctx, addr, 1); err != nil {
+		t.Fatalf("GetContext() error: %v", err)
+	}
+	if d := <-st.deadlines; d.Sub(want) > 100*time.Millisecond || want.Sub(d) > 100*time.Millisecond {
+		t.Errorf("GetContext() deadline %v, want %v", d, want)
+	}
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Version is a version of the storage services.
+var Version = "dev"
+
+// Op is a kind of a storage request.
+type Op int
+
+const (
+	OpGet Op = iota
+	OpPut
+	OpDel
+
+	opCount
+)
+
+// Stats contains load statistics of a node.
+type Stats struct {
+	Records uint64
+	Bytes   uint64
+
+	// GetRate, PutRate and DelRate are numbers of requests per second.
+	GetRate float64
+	PutRate float64
+	DelRate float64
+
+	LatencyP50 time.Duration
+	LatencyP99 time.Duration
+
+	Uptime  time.Duration
+	Version string
+}
+
+// latencySamples is a maximum number of latencies remembered by a Recorder
+// between two snapshots.
+const latencySamples = 1024
+
+// Recorder collects request rates and latencies.
+type Recorder struct {
+	sync.Mutex
+	since     time.Time
+	counts    [opCount]uint64
+	latencies []time.Duration
+	next      int
+}
+
+// NewRecorder creates a new Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		since:     time.Now(),
+		latencies: make([]time.Duration, 0, latencySamples),
+	}
+}
+
+// Record registers a request of kind op which took d.
+func (r *Recorder) Record(op Op, d time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+	r.counts[op]++
+	if len(r.latencies) < cap(r.latencies) {
+		r.latencies = append(r.latencies, d)
+		return
+	}
+	r.latencies[r.next] = d
+	r.next = (r.next + 1) % len(r.latencies)
+}
+
+// Snapshot fills request rates and latency percentiles of st with values
+// collected since the previous snapshot and starts collecting anew.
+func (r *Recorder) Snapshot(st *Stats) {
+	r.Lock()
+	defer r.Unlock()
+	tNow := time.Now()
+	elapsed := tNow.Sub(r.since).Seconds()
+	if elapsed > 0 {
+		st.GetRate = float64(r.counts[OpGet]) / elapsed
+		st.PutRate = float64(r.counts[OpPut]) / elapsed
+		st.DelRate = float64(r.counts[OpDel]) / elapsed
+	}
+	if n := len(r.latencies); n > 0 {
+		sort.Slice(r.latencies, func(i, j int) bool {
+			return r.latencies[i] < r.latencies[j]
+		})
+		st.LatencyP50 = r.latencies[(n-1)*50/100]
+		st.LatencyP99 = r.latencies[(n-1)*99/100]
+	}
+
+	r.since = tNow
+	r.counts = [opCount]uint64{}
+	r.latencies = r.latencies[:0]
+	r.next = 0
+}
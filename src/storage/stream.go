@@ -0,0 +1,295 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"io"
+	"io/ioutil"
+	"log"
+
+	"storage/pb"
+)
+
+// ChunkSize is the largest size of chunks values are streamed in.
+const ChunkSize = 64 << 10
+
+// StreamStorage is implemented by a Storage which can store values
+// streamed in chunks without holding them whole in memory. Server
+// assembles streamed values for other storages.
+type StreamStorage interface {
+	// PutStream puts the value read from r until io.EOF. Nothing is put
+	// if r fails.
+	PutStream(ctx context.Context, k RecordID, r io.Reader) error
+	// GetStream writes the value of k to w. Errors of the record such as
+	// ErrRecordNotFound are returned before anything is written.
+	GetStream(ctx context.Context, k RecordID, w io.Writer) error
+	// Digest returns the digest of the value of k, see NewDigest.
+	Digest(ctx context.Context, k RecordID) ([]byte, error)
+}
+
+// StreamClient is implemented by clients which can stream values,
+// see StreamStorage.
+type StreamClient interface {
+	PutStream(ctx context.Context, node ServiceAddr, k RecordID, r io.Reader) error
+	GetStream(ctx context.Context, node ServiceAddr, k RecordID, w io.Writer) error
+	Digest(ctx context.Context, node ServiceAddr, k RecordID) ([]byte, error)
+}
+
+// NewDigest returns a new hash computing digests of values.
+func NewDigest() hash.Hash {
+	return sha256.New()
+}
+
+// DigestOf returns the digest of d.
+func DigestOf(d []byte) []byte {
+	h := NewDigest()
+	h.Write(d)
+	return h.Sum(nil)
+}
+
+// chunkReader reads data of chunks returned by recv until it fails.
+type chunkReader struct {
+	recv func() ([]byte, error)
+	buf  []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		data, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// chunkWriter sends data written to it with send in chunks of ChunkSize
+// at most.
+type chunkWriter struct {
+	send func(d []byte) error
+	n    int64
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := len(p)
+		if n > ChunkSize {
+			n = ChunkSize
+		}
+		if err := w.send(p[:n]); err != nil {
+			return written, err
+		}
+		p = p[n:]
+		written += n
+		w.n += int64(n)
+	}
+	return written, nil
+}
+
+// statusError returns the error of a reply with status and error e.
+func statusError(status int32, e string) error {
+	if err := StatusCode(status).ToError(); err != ErrUnknownStatus {
+		return err
+	}
+	return errors.New(e)
+}
+
+func (s *Server) PutStream(stream pb.Storage_PutStreamServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	key := RecordID(first.Key)
+	log.Printf("PUT STREAM request: key = %v", key)
+
+	ctx := stream.Context()
+	r := &chunkReader{buf: first.Data, recv: func() ([]byte, error) {
+		chunk, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		return chunk.Data, nil
+	}}
+	if ss, ok := s.st.(StreamStorage); ok {
+		err = ss.PutStream(ctx, key, r)
+	} else {
+		var data []byte
+		data, err = ioutil.ReadAll(r)
+		if err == nil {
+			if cs, ok := s.st.(ContextStorage); ok {
+				err = cs.PutContext(ctx, key, data)
+			} else {
+				err = s.st.Put(key, data)
+			}
+		}
+	}
+	status := ErrToStatus(err)
+	reply := pb.PutReply{
+		Status: int32(status),
+	}
+	if status == StatusUnknown {
+		reply.Error = err.Error()
+	}
+	return stream.SendAndClose(&reply)
+}
+
+func (s *Server) GetStream(req *pb.GetRequest, stream pb.Storage_GetStreamServer) error {
+	key := RecordID(req.Key)
+	log.Printf("GET STREAM request: key = %v", key)
+
+	ctx := stream.Context()
+	w := &chunkWriter{send: func(d []byte) error {
+		return stream.Send(&pb.GetChunk{Data: d})
+	}}
+	var err error
+	if ss, ok := s.st.(StreamStorage); ok {
+		err = ss.GetStream(ctx, key, w)
+	} else {
+		var data []byte
+		if cs, ok := s.st.(ContextStorage); ok {
+			data, err = cs.GetContext(ctx, key)
+		} else {
+			data, err = s.st.Get(key)
+		}
+		if err == nil {
+			_, err = w.Write(data)
+		}
+	}
+	if err == nil {
+		return nil
+	}
+	if w.n > 0 {
+		// the value is cut, the stream is failed as a whole
+		return err
+	}
+	status := ErrToStatus(err)
+	chunk := pb.GetChunk{
+		Status: int32(status),
+	}
+	if status == StatusUnknown {
+		chunk.Error = err.Error()
+	}
+	return stream.Send(&chunk)
+}
+
+func (s *Server) Digest(ctx context.Context, req *pb.GetRequest) (*pb.DigestReply, error) {
+	key := RecordID(req.Key)
+	log.Printf("DIGEST request: key = %v", key)
+
+	var (
+		digest []byte
+		err    error
+	)
+	if ss, ok := s.st.(StreamStorage); ok {
+		digest, err = ss.Digest(ctx, key)
+	} else {
+		var data []byte
+		if cs, ok := s.st.(ContextStorage); ok {
+			data, err = cs.GetContext(ctx, key)
+		} else {
+			data, err = s.st.Get(key)
+		}
+		if err == nil {
+			digest = DigestOf(data)
+		}
+	}
+	status := ErrToStatus(err)
+	reply := pb.DigestReply{
+		Status: int32(status),
+		Digest: digest,
+	}
+	if status == StatusUnknown {
+		reply.Error = err.Error()
+	}
+	return &reply, nil
+}
+
+// PutStream puts the value read from r to node in chunks. Unlike other
+// requests streams are not limited by Timeout, since they take as long
+// as their values take to transfer. The connection is acquired from the
+// pool for the whole stream, so it is not evicted as idle meanwhile.
+func (c StorageClient) PutStream(ctx context.Context, node ServiceAddr, k RecordID, r io.Reader) error {
+	log.Printf("Streaming record to %q, key = %v", node, k)
+	client, release, err := c.stream(node)
+	if err != nil {
+		return err
+	}
+	defer release()
+	// the stream is cancelled on return unless closed, so nothing is put
+	// if r fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := client.PutStream(ctx)
+	if err != nil {
+		return err
+	}
+	w := &chunkWriter{send: func(d []byte) error {
+		return stream.Send(&pb.PutChunk{Key: uint32(k), Data: d})
+	}}
+	// io.EOF on Send means that the node has answered already
+	if _, err := io.CopyBuffer(w, r, make([]byte, ChunkSize)); err != nil && err != io.EOF {
+		return err
+	}
+	if w.n == 0 {
+		if err := stream.Send(&pb.PutChunk{Key: uint32(k)}); err != nil && err != io.EOF {
+			return err
+		}
+	}
+	reply, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	return statusError(reply.Status, reply.Error)
+}
+
+// GetStream writes the value of k streamed from node to w. Like
+// PutStream it is not limited by Timeout.
+func (c StorageClient) GetStream(ctx context.Context, node ServiceAddr, k RecordID, w io.Writer) error {
+	log.Printf("Streaming record from %q, key = %v", node, k)
+	client, release, err := c.stream(node)
+	if err != nil {
+		return err
+	}
+	defer release()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := client.GetStream(ctx, &pb.GetRequest{Key: uint32(k)})
+	if err != nil {
+		return err
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := statusError(chunk.Status, chunk.Error); err != nil {
+			return err
+		}
+		if _, err := w.Write(chunk.Data); err != nil {
+			return err
+		}
+	}
+}
+
+// Digest returns the digest of the value of k at node.
+func (c StorageClient) Digest(ctx context.Context, node ServiceAddr, k RecordID) ([]byte, error) {
+	return c.do(ctx, node, func(ctx context.Context, client pb.StorageClient) ([]byte, error) {
+		reply, err := client.Digest(ctx, &pb.GetRequest{Key: uint32(k)})
+		if err != nil {
+			return nil, err
+		}
+		if err := statusError(reply.Status, reply.Error); err != nil {
+			return nil, err
+		}
+		return reply.Digest, nil
+	})
+}
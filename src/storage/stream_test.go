@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+// FakeStreamStorage keeps streamed values in memory.
+type FakeStreamStorage struct {
+	FakeStorage
+	lock   sync.Mutex
+	values map[RecordID][]byte
+}
+
+func (s *FakeStreamStorage) PutStream(ctx context.Context, k RecordID, r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.values[k]; ok {
+		return ErrRecordExists
+	}
+	s.values[k] = data
+	return nil
+}
+
+func (s *FakeStreamStorage) GetStream(ctx context.Context, k RecordID, w io.Writer) error {
+	s.lock.Lock()
+	data, ok := s.values[k]
+	s.lock.Unlock()
+	if !ok {
+		return ErrRecordNotFound
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+func (s *FakeStreamStorage) Digest(ctx context.Context, k RecordID) ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	data, ok := s.values[k]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+	return DigestOf(data), nil
+}
+
+// failingReader fails after reading its data.
+type failingReader struct {
+	r io.Reader
+}
+
+func (r failingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err == io.EOF {
+		return n, errors.New("read failed")
+	}
+	return n, err
+}
+
+func TestStorageClient_Stream(t *testing.T) {
+	addr, srv := startServer(t)
+	c := StorageClient{}
+
+	// FakeStorage does not stream, values are assembled by the server
+	var buf bytes.Buffer
+	if err := c.GetStream(context.Background(), addr, 1, &buf); err != nil || buf.String() != "value" {
+		t.Errorf("GetStream() got %q, %v, want \"value\"", buf.String(), err)
+	}
+	if d, err := c.Digest(context.Background(), addr, 1); err != nil || !bytes.Equal(d, DigestOf([]byte("value"))) {
+		t.Errorf("Digest() got %x, %v", d, err)
+	}
+	if err := c.PutStream(context.Background(), addr, 1, bytes.NewReader(make([]byte, 3*ChunkSize))); err != nil {
+		t.Errorf("PutStream() error: %v", err)
+	}
+	srv.Stop()
+
+	st := &FakeStreamStorage{values: make(map[RecordID][]byte)}
+	srv = NewServer(st, string(addr))
+	go srv.ListenAndServe()
+	defer srv.Stop()
+	waitServer(t, string(addr))
+
+	// larger than the default gRPC message limit of 4MB
+	value := make([]byte, 5<<20+1)
+	for i := range value {
+		value[i] = byte(i)
+	}
+	if err := c.PutStream(context.Background(), addr, 1, bytes.NewReader(value)); err != nil {
+		t.Fatalf("PutStream() error: %v", err)
+	}
+	if err := c.PutStream(context.Background(), addr, 1, bytes.NewReader(value)); err != ErrRecordExists {
+		t.Errorf("PutStream() of an existing record got %v, want %v", err, ErrRecordExists)
+	}
+	buf.Reset()
+	if err := c.GetStream(context.Background(), addr, 1, &buf); err != nil || !bytes.Equal(buf.Bytes(), value) {
+		t.Errorf("GetStream() got %v bytes, %v, want the put value", buf.Len(), err)
+	}
+	if d, err := c.Digest(context.Background(), addr, 1); err != nil || !bytes.Equal(d, DigestOf(value)) {
+		t.Errorf("Digest() got %x, %v, want %x", d, err, DigestOf(value))
+	}
+
+	if err := c.PutStream(context.Background(), addr, 2, failingReader{bytes.NewReader(value)}); err == nil {
+		t.Errorf("PutStream() from a failing reader succeeded")
+	}
+	if err := c.GetStream(context.Background(), addr, 2, ioutil.Discard); err != ErrRecordNotFound {
+		t.Errorf("GetStream() after a failed PutStream() got %v, want %v", err, ErrRecordNotFound)
+	}
+	if err := c.PutStream(context.Background(), addr, 3, bytes.NewReader(nil)); err != nil {
+		t.Fatalf("PutStream() of an empty value error: %v", err)
+	}
+	buf.Reset()
+	if err := c.GetStream(context.Background(), addr, 3, &buf); err != nil || buf.Len() != 0 {
+		t.Errorf("GetStream() of an empty value got %q, %v", buf.String(), err)
+	}
+}